@@ -0,0 +1,96 @@
+package typegen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+)
+
+// StaleFileError is returned when a generated file doesn't match what the
+// generator would write.
+type StaleFileError struct {
+	// Name is the name of the file.
+	Name string
+	// Diff is a unified diff from the file to the generator's output.
+	Diff string
+}
+
+func (e *StaleFileError) Error() string {
+	return fmt.Sprintf("%s does not match the generator, regenerate it:\n%s", e.Name, e.Diff)
+}
+
+// CheckEncodersFile is a convenience wrapper around Gen.CheckEncodersFile using default options.
+func CheckEncodersFile(fname, pkg string, types ...interface{}) error {
+	return Gen{}.CheckEncodersFile(fname, pkg, types...)
+}
+
+// CheckEncodersFile generates the file that WriteEncodersToFile would write in memory, and
+// returns a *StaleFileError if the file on disk differs from it.
+func (g Gen) CheckEncodersFile(fname, pkg string, types ...interface{}) error {
+	return g.checkFile(fname, pkg, types, g.GenEncodersForType)
+}
+
+// CheckTupleEncodersFile is a convenience wrapper around Gen.CheckTupleEncodersFile using default
+// options.
+func CheckTupleEncodersFile(fname, pkg string, types ...interface{}) error {
+	return Gen{}.CheckTupleEncodersFile(fname, pkg, types...)
+}
+
+// CheckTupleEncodersFile is like CheckEncodersFile, for a file written by
+// WriteTupleEncodersToFile.
+func (g Gen) CheckTupleEncodersFile(fname, pkg string, types ...interface{}) error {
+	return g.checkFile(fname, pkg, types, g.GenTupleEncodersForType)
+}
+
+// CheckMapEncodersFile is a convenience wrapper around Gen.CheckMapEncodersFile using default
+// options.
+func CheckMapEncodersFile(fname, pkg string, types ...interface{}) error {
+	return Gen{}.CheckMapEncodersFile(fname, pkg, types...)
+}
+
+// CheckMapEncodersFile is like CheckEncodersFile, for a file written by
+// WriteMapEncodersToFile.
+func (g Gen) CheckMapEncodersFile(fname, pkg string, types ...interface{}) error {
+	return g.checkFile(fname, pkg, types, g.GenMapEncodersForType)
+}
+
+func (g Gen) checkFile(fname, pkg string, types []interface{}, gen genFunc) error {
+	expected, err := g.render(pkg, types, gen)
+	if err != nil {
+		return err
+	}
+	actual, err := os.ReadFile(fname)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(actual, expected) {
+		return &StaleFileError{Name: fname, Diff: unifiedDiff(fname, fname+" (generated)", actual, expected)}
+	}
+	return nil
+}
+
+// TestingT is the part of testing.TB used by AssertGenerated.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// AssertGenerated is a convenience wrapper around Gen.AssertGenerated using default options.
+func AssertGenerated(t TestingT, fname, pkg string, types ...interface{}) {
+	t.Helper()
+	Gen{}.AssertGenerated(t, fname, pkg, types...)
+}
+
+// AssertGenerated fails the test if the generated file fname is not what
+// WriteEncodersToFile would write for the given types, so that a plain go test
+// catches checked in code that needs regenerating:
+//
+//	func TestGenerated(t *testing.T) {
+//		jsg.AssertGenerated(t, "dag_json_gen.go", "mypackage", MyType{})
+//	}
+func (g Gen) AssertGenerated(t TestingT, fname, pkg string, types ...interface{}) {
+	t.Helper()
+	if err := g.CheckEncodersFile(fname, pkg, types...); err != nil {
+		t.Errorf("%s", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// readDocument reads the document named by the only argument, or standard
+// input if there are no arguments.
+func readDocument(fs *flag.FlagSet, stdin io.Reader) ([]byte, error) {
+	switch fs.NArg() {
+	case 0:
+		return io.ReadAll(stdin)
+	case 1:
+		if fs.Arg(0) == "-" {
+			return io.ReadAll(stdin)
+		}
+		return os.ReadFile(fs.Arg(0))
+	default:
+		fs.Usage()
+		return nil, errUsage
+	}
+}
+
+// canonicalize decodes a single DAG-JSON document and encodes it again in
+// canonical form.
+func canonicalize(data []byte, opts ...jsg.ReaderOption) ([]byte, error) {
+	jr := jsg.NewDagJsonReader(bytes.NewReader(data), opts...)
+	defer jr.Release(nil)
+	var n jsg.Node
+	if err := n.UnmarshalDagJSON(jr); err != nil {
+		return nil, err
+	}
+	if err := jr.ReadEOF(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := n.MarshalDagJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func runFmt(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	data, err := readDocument(fs, stdin)
+	if err != nil {
+		return err
+	}
+	out, err := canonicalize(data)
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(out)
+	return err
+}
+
+func runValidate(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	data, err := readDocument(fs, stdin)
+	if err != nil {
+		return err
+	}
+	_, err = canonicalDocument(data)
+	return err
+}
+
+// canonicalDocument returns data, without the whitespace around it, if it is
+// a document in canonical form.
+func canonicalDocument(data []byte) ([]byte, error) {
+	// Whitespace around the document is not part of it.
+	data = bytes.TrimSpace(data)
+	if _, err := canonicalize(data, jsg.Strict()); err != nil {
+		return nil, fmt.Errorf("document is not in canonical form: %w", err)
+	}
+	return data, nil
+}
+
+func runCid(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	hash := fs.String("mh", "sha2-256", "name of the multihash function")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	code, ok := mh.Names[*hash]
+	if !ok {
+		return fmt.Errorf("unknown multihash function %q", *hash)
+	}
+	data, err := readDocument(fs, stdin)
+	if err != nil {
+		return err
+	}
+	// The CID addresses the document as given, so it must be canonical
+	// rather than be re-encoded.
+	data, err = canonicalDocument(data)
+	if err != nil {
+		return err
+	}
+
+	prefix := cid.Prefix{Version: 1, Codec: cid.DagJSON, MhType: code, MhLength: -1}
+	c, err := prefix.Sum(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, c)
+	return err
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+)
+
+// genFlags are the flags shared by gen and check.
+type genFlags struct {
+	dir   string
+	out   string
+	tuple bool
+	gen   jsg.Gen
+}
+
+func (f *genFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.dir, "dir", ".", "directory of the package to generate for")
+	fs.StringVar(&f.out, "o", "dag_json_gen.go", "generated file, relative to the package directory")
+	fs.BoolVar(&f.tuple, "tuple", false, "encode structs that don't declare a representation as lists of fields instead of maps")
+	fs.IntVar(&f.gen.MaxArrayLength, "max-array-length", 0, "maximum length of decoded arrays (default 8192)")
+	fs.IntVar(&f.gen.MaxByteLength, "max-byte-length", 0, "maximum length of decoded byte slices (default 2097152)")
+	fs.IntVar(&f.gen.MaxStringLength, "max-string-length", 0, "maximum length of decoded strings (default 8192)")
+	fs.BoolVar(&f.gen.SortTypeNames, "sort", false, "write types in order of their names")
+	fs.BoolVar(&f.gen.StrictFields, "strict-fields", false, "reject unknown and duplicate keys when decoding map-encoded structs")
+	fs.Func("import", "import the package at `path=name` by name (repeatable)", func(v string) error {
+		path, name, ok := strings.Cut(v, "=")
+		if !ok || path == "" || name == "" {
+			return fmt.Errorf("expected path=name, got %q", v)
+		}
+		if f.gen.ImportAliases == nil {
+			f.gen.ImportAliases = make(map[string]string)
+		}
+		f.gen.ImportAliases[path] = name
+		return nil
+	})
+}
+
+// types loads the package and the named types, or the marked types if none
+// are named.
+func (f *genFlags) types(names []string) (string, []interface{}, error) {
+	p, err := jsg.LoadPackage(f.dir)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(names) == 0 && len(p.MarkedTypes()) == 0 {
+		return "", nil, fmt.Errorf("no types named and none marked with %s in %s", jsg.MarkerComment, p.Dir)
+	}
+	gtis, err := p.TypeInfos(names...)
+	if err != nil {
+		return "", nil, err
+	}
+	types := make([]interface{}, len(gtis))
+	for i, gti := range gtis {
+		if f.tuple && gti.Representation == "" && gti.Union == "" {
+			gti.Representation = "tuple"
+		}
+		types[i] = gti
+	}
+	return p.Name, types, nil
+}
+
+func runGen(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	var f genFlags
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	pkg, types, err := f.types(fs.Args())
+	if err != nil {
+		return err
+	}
+	return f.gen.WriteEncodersToFile(filepath.Join(f.dir, f.out), pkg, types...)
+}
+
+func runCheck(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	var f genFlags
+	f.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	pkg, types, err := f.types(fs.Args())
+	if err != nil {
+		return err
+	}
+	fname := filepath.Join(f.dir, f.out)
+	err = f.gen.CheckEncodersFile(fname, pkg, types...)
+	var stale *jsg.StaleFileError
+	if errors.As(err, &stale) {
+		return fmt.Errorf("%s is stale, run dag-json-gen gen to update it:\n%s", fname, stale.Diff)
+	}
+	return err
+}
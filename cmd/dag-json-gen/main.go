@@ -0,0 +1,90 @@
+// Command dag-json-gen generates DAG-JSON codecs for Go types and works with
+// DAG-JSON documents.
+//
+// Usage:
+//
+//	dag-json-gen gen [flags] [type ...]
+//	dag-json-gen check [flags] [type ...]
+//	dag-json-gen fmt [file]
+//	dag-json-gen validate [file]
+//	dag-json-gen cid [-mh name] [file]
+//
+// gen and check read the package in the current directory from source, so
+// they work even when its generated code no longer compiles. With no types
+// named, the types marked with a //dagjsongen:generate comment are used. It
+// is typically run with a directive such as:
+//
+//	//go:generate go run github.com/alanshaw/dag-json-gen/cmd/dag-json-gen gen
+//
+// The other commands read a document from the file named, or from standard
+// input if there is none. cid hashes the document as read, and fails if it is
+// not in canonical form, as validate does; run it through fmt first otherwise.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"gen", "[flags] [type ...]", "generate codecs for the named types", runGen},
+	{"check", "[flags] [type ...]", "exit non-zero if the generated codecs are stale", runCheck},
+	{"fmt", "[file]", "re-encode a document canonically", runFmt},
+	{"validate", "[file]", "check a document is strict DAG-JSON", runValidate},
+	{"cid", "[-mh name] [file]", "print the CID of a document, which must be in canonical form", runCid},
+}
+
+// errUsage signals that the command line was invalid and usage has already
+// been printed.
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	usage := func() {
+		fmt.Fprintf(stderr, "usage: dag-json-gen <command> [arguments]\n\ncommands:\n")
+		for _, c := range commands {
+			fmt.Fprintf(stderr, "  %-9s %s\n", c.name, c.short)
+		}
+	}
+	if len(args) == 0 {
+		usage()
+		return 2
+	}
+
+	for _, c := range commands {
+		if c.name != args[0] {
+			continue
+		}
+		fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+		fs.SetOutput(stderr)
+		fs.Usage = func() {
+			fmt.Fprintf(stderr, "usage: dag-json-gen %s %s\n", c.name, c.args)
+			fs.PrintDefaults()
+		}
+		if err := c.run(fs, args[1:], stdin, stdout); err != nil {
+			if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+				return 2
+			}
+			fmt.Fprintf(stderr, "dag-json-gen %s: %s\n", c.name, err)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "dag-json-gen: unknown command %q\n", args[0])
+	usage()
+	return 2
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runArgs(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestDocumentCommands(t *testing.T) {
+	for _, tc := range []struct {
+		args   []string
+		in     string
+		code   int
+		stdout string
+	}{
+		{[]string{"fmt"}, `{"b": [1, 2.50], "a": {"/": "bafkqaaa"}}`, 0, `{"a":{"/":"bafkqaaa"},"b":[1,2.5]}`},
+		{[]string{"fmt"}, `{"a":1} 2`, 1, ""},
+		{[]string{"fmt"}, `{"a":1,"a":2}`, 1, ""},
+		{[]string{"validate"}, "{\"a\":[1.5,null]}\n", 0, ""},
+		{[]string{"validate"}, `{"b":1,"a":1}`, 1, ""},
+		{[]string{"validate"}, `{"a": 1}`, 1, ""},
+		{[]string{"validate"}, `{"/":"notacid"}`, 1, ""},
+		{[]string{"validate"}, `"a\/b"`, 1, ""},
+		{[]string{"validate"}, `"\u0061"`, 1, ""},
+		{[]string{"cid"}, `{"a":1}`, 0, "baguqeeraafnl2724yv5c3wklowipaswybbbhhec64m7mltv6vzrco2ux7bra\n"},
+		{[]string{"cid"}, "{\"a\":1}\n", 0, "baguqeeraafnl2724yv5c3wklowipaswybbbhhec64m7mltv6vzrco2ux7bra\n"},
+		{[]string{"cid"}, `{ "a" : 1 }`, 1, ""},
+		{[]string{"cid", "-mh", "identity"}, `1`, 0, "baguqeaabge\n"},
+		{[]string{"cid", "-mh", "nope"}, `1`, 1, ""},
+		{[]string{"fmt", "a", "b"}, ``, 2, ""},
+		{[]string{"nope"}, ``, 2, ""},
+		{nil, ``, 2, ""},
+	} {
+		code, stdout, stderr := runArgs(t, tc.in, tc.args...)
+		if code != tc.code || stdout != tc.stdout {
+			t.Errorf("%v on %s: expected %d %q, got %d %q (%s)", tc.args, tc.in, tc.code, tc.stdout, code, stdout, stderr)
+		}
+	}
+}
+
+func TestGenAndCheck(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/points\n",
+		"points.go": `package points
+
+//dagjsongen:generate
+type Point struct {
+	X, Y int64
+}
+
+type Line struct {
+	From, To Point
+}
+`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if code, _, stderr := runArgs(t, "", "check", "-dir", dir); code != 1 {
+		t.Fatalf("expected check to fail before generating, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := runArgs(t, "", "gen", "-dir", dir); code != 0 {
+		t.Fatalf("gen failed: %s", stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "dag_json_gen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(data, []byte("func (t *Point) MarshalDagJSON")) || bytes.Contains(data, []byte("Line")) {
+		t.Fatal("expected codecs for the marked type only")
+	}
+	if code, _, stderr := runArgs(t, "", "check", "-dir", dir); code != 0 {
+		t.Fatalf("expected check to pass after generating: %s", stderr)
+	}
+	if code, _, stderr := runArgs(t, "", "check", "-dir", dir, "Point", "Line"); code != 1 {
+		t.Fatal("expected check to fail for a different set of types")
+	} else if !strings.Contains(stderr, "@@") || !strings.Contains(stderr, "+func (t *Line) MarshalDagJSON") {
+		t.Fatalf("expected check to show a diff of the changes:\n%s", stderr)
+	}
+
+	// Generating again still works with the generated file broken.
+	if err := os.WriteFile(filepath.Join(dir, "dag_json_gen.go"), data[:len(data)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, stderr := runArgs(t, "", "gen", "-dir", dir, "-tuple", "Point", "Line"); code != 0 {
+		t.Fatalf("gen failed: %s", stderr)
+	}
+	if code, _, stderr := runArgs(t, "", "check", "-dir", dir, "-tuple", "Point", "Line"); code != 0 {
+		t.Fatalf("expected check to pass after generating: %s", stderr)
+	}
+
+	if code, _, stderr := runArgs(t, "", "gen", "-dir", dir, "-import", "github.com/ipfs/go-cid=gocid"); code != 1 {
+		t.Fatalf("expected an error renaming a default import, got %d: %s", code, stderr)
+	}
+	if code, _, _ := runArgs(t, "", "gen", "-dir", dir, "-import", "example.com/x"); code != 2 {
+		t.Fatal("expected a usage error for an import without a name")
+	}
+}
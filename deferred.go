@@ -36,6 +36,7 @@ func (d *Deferred) UnmarshalDagJSON(r io.Reader) error {
 
 func parse(r io.Reader, w io.Writer) error {
 	jr := NewDagJsonReader(r)
+	defer jr.Release(r)
 	typ, err := jr.PeekType()
 	if err != nil {
 		return err
@@ -65,7 +66,11 @@ func parse(r io.Reader, w io.Writer) error {
 				if err := jr.ReadObjectColon(); err != nil {
 					return err
 				}
-				if _, err := fmt.Fprintf(w, `"%s":`, k); err != nil {
+				mk, err := json.Marshal(k)
+				if err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(w, `%s:`, mk); err != nil {
 					return err
 				}
 				if err := parse(jr, w); err != nil {
@@ -0,0 +1,256 @@
+package typegen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// maxDiffCells bounds the size of the table used to match lines between
+// regions that share no unique lines. Larger regions are shown as replaced
+// entirely.
+const maxDiffCells = 1 << 22
+
+// diffOp is a line of an edit script: ' ' for a line kept, '-' for a line
+// deleted and '+' for a line inserted.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// unifiedDiff returns the changes from a, named aName, to b, named bName, in
+// unified diff format, or the empty string if they are equal.
+func unifiedDiff(aName, bName string, a, b []byte) string {
+	al, bl := splitLines(string(a)), splitLines(string(b))
+	ops := diffLines(al, bl)
+
+	var out strings.Builder
+	// The index in ops and the line numbers in a and b of each op.
+	aLine, bLine := 1, 1
+	starts := make([][2]int, len(ops))
+	for i, op := range ops {
+		starts[i] = [2]int{aLine, bLine}
+		if op.kind != '+' {
+			aLine++
+		}
+		if op.kind != '-' {
+			bLine++
+		}
+	}
+
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk while changes are close enough to share context.
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(ops); j++ {
+			if ops[j].kind != ' ' {
+				end = j + 1
+			} else if j-end >= 2*diffContext {
+				break
+			}
+		}
+		end = min(end+diffContext, len(ops))
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+		}
+		var aCount, bCount int
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				aCount++
+			}
+			if op.kind != '-' {
+				bCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(starts[start][0], aCount), hunkRange(starts[start][1], bCount))
+		for _, op := range ops[start:end] {
+			out.WriteByte(op.kind)
+			out.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+// hunkRange formats the start and length of a hunk in one file, where an empty
+// range starts at the line before it.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits s after each newline.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns an edit script turning a into b. Lines that appear once in
+// each are matched first, in the longest run that keeps their order, and the
+// regions between them are diffed in turn, as in patience diff.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	// Keep the common prefix and suffix.
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for _, l := range a[:prefix] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, l := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', l})
+	}
+	return ops
+}
+
+func diffMiddle(a, b []string) []diffOp {
+	if len(a) == 0 || len(b) == 0 {
+		return replaceLines(a, b)
+	}
+
+	anchors := uniqueAnchors(a, b)
+	if len(anchors) == 0 {
+		if len(a)*len(b) > maxDiffCells {
+			return replaceLines(a, b)
+		}
+		return lcsDiff(a, b)
+	}
+
+	var ops []diffOp
+	ai, bi := 0, 0
+	for _, m := range anchors {
+		ops = append(ops, diffLines(a[ai:m[0]], b[bi:m[1]])...)
+		ops = append(ops, diffOp{' ', a[m[0]]})
+		ai, bi = m[0]+1, m[1]+1
+	}
+	return append(ops, diffLines(a[ai:], b[bi:])...)
+}
+
+// uniqueAnchors returns the index pairs of lines occurring exactly once in both
+// a and b, keeping the longest sequence in which both indexes increase.
+func uniqueAnchors(a, b []string) [][2]int {
+	type count struct{ a, b, ai, bi int }
+	counts := make(map[string]*count)
+	for i, l := range a {
+		c := counts[l]
+		if c == nil {
+			c = &count{}
+			counts[l] = c
+		}
+		c.a++
+		c.ai = i
+	}
+	for i, l := range b {
+		if c := counts[l]; c != nil {
+			c.b++
+			c.bi = i
+		}
+	}
+	var pairs [][2]int
+	for i, l := range a {
+		if c := counts[l]; c.a == 1 && c.b == 1 {
+			pairs = append(pairs, [2]int{i, c.bi})
+		}
+	}
+
+	// Longest increasing subsequence of the b indexes, by patience sorting.
+	var tails []int // indexes in pairs of the smallest tail of each length
+	prev := make([]int, len(pairs))
+	for i, p := range pairs {
+		lo, hi := 0, len(tails)
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if pairs[tails[mid]][1] < p[1] {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		prev[i] = -1
+		if lo > 0 {
+			prev[i] = tails[lo-1]
+		}
+		if lo == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[lo] = i
+		}
+	}
+	if len(tails) == 0 {
+		return nil
+	}
+	seq := make([][2]int, len(tails))
+	for i, k := len(tails)-1, tails[len(tails)-1]; i >= 0; i, k = i-1, prev[k] {
+		seq[i] = pairs[k]
+	}
+	return seq
+}
+
+// lcsDiff diffs a and b through a table of the longest common subsequences of
+// their suffixes.
+func lcsDiff(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) *int32 { return &lcs[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				*at(i, j) = *at(i+1, j+1) + 1
+			} else {
+				*at(i, j) = max(*at(i+1, j), *at(i, j+1))
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i, j = i+1, j+1
+		case *at(i+1, j) >= *at(i, j+1):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	return append(ops, replaceLines(a[i:], b[j:])...)
+}
+
+// replaceLines deletes every line of a and inserts every line of b.
+func replaceLines(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, l := range a {
+		ops = append(ops, diffOp{'-', l})
+	}
+	for _, l := range b {
+		ops = append(ops, diffOp{'+', l})
+	}
+	return ops
+}
@@ -0,0 +1,59 @@
+package typegen
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{name: "equal", a: "a\nb\n", b: "a\nb\n"},
+		{name: "empty"},
+		{
+			name: "change",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:    "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expected: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "separate hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			expected: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n" +
+				"-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,3 @@\n 9\n 10\n 11\n-12\n",
+		},
+		{
+			name: "joined hunks",
+			a:    "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:    "one\n2\n3\n4\n5\n6\n7\neight\n",
+			expected: "--- a\n+++ b\n@@ -1,8 +1,8 @@\n" +
+				"-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n",
+		},
+		{
+			name:     "from empty",
+			b:        "a\n",
+			expected: "--- a\n+++ b\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:     "no newline",
+			a:        "a\nb",
+			b:        "a\nb\n",
+			expected: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+		{
+			name:     "moved",
+			a:        "x\na\nb\nc\n",
+			b:        "a\nb\nc\nx\n",
+			expected: "--- a\n+++ b\n@@ -1,4 +1,4 @@\n-x\n a\n b\n c\n+x\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := unifiedDiff("a", "b", []byte(tc.a), []byte(tc.b)); diff != tc.expected {
+				t.Fatalf("expected:\n%s\ngot:\n%s", tc.expected, diff)
+			}
+		})
+	}
+}
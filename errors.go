@@ -0,0 +1,97 @@
+package typegen
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DecodeError describes where decoding failed. Errors returned by
+// DagJsonReader methods and generated UnmarshalDagJSON methods can be
+// inspected with errors.As:
+//
+//	var de *jsg.DecodeError
+//	if errors.As(err, &de) {
+//		log.Printf("bad value at %s (byte %d)", de.Path, de.Offset)
+//	}
+type DecodeError struct {
+	// Offset is the number of bytes of input consumed when the error was
+	// detected.
+	Offset int64
+	// Path locates the value being decoded within the outermost value, using
+	// Go field names, list indexes and quoted map keys, such as
+	// .Stuff.Arrrrrghay[2].Foo or .Labels["a"]. It is empty for the outermost
+	// value itself.
+	Path string
+	// Expected and Actual name the kind of token that was wanted and the one
+	// found, when the error is a token mismatch.
+	Expected string
+	Actual   string
+	// Err is the underlying cause, if any.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	var b strings.Builder
+	if e.Path != "" {
+		b.WriteString(e.Path)
+		b.WriteString(": ")
+	}
+	if e.Expected != "" {
+		fmt.Fprintf(&b, "expected %s but read %s", e.Expected, e.Actual)
+		if e.Err != nil {
+			b.WriteString(": ")
+		}
+	}
+	if e.Err != nil {
+		b.WriteString(e.Err.Error())
+	}
+	fmt.Fprintf(&b, " (offset %d)", e.Offset)
+	return b.String()
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// IndexPath returns the DecodeError path element for a list index.
+func IndexPath(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
+
+// KeyPath returns the DecodeError path element for a map key.
+func KeyPath(k string) string {
+	return "[" + strconv.Quote(k) + "]"
+}
+
+// PathError places err at path, relative to the value the reader is
+// decoding. A DecodeError has the path added in front of its own, which is how
+// generated code builds up the full path as errors are returned from nested
+// values. Any other error becomes the cause of a new DecodeError at the
+// reader's current offset.
+func (d *DagJsonReader) PathError(path string, err error) error {
+	var de *DecodeError
+	if errors.As(err, &de) {
+		de.Path = path + de.Path
+		return err
+	}
+	return &DecodeError{Offset: d.Offset(), Path: path, Err: err}
+}
+
+// fail returns err as a DecodeError at the current offset, unless it already
+// is one.
+func (d *DagJsonReader) fail(err error) error {
+	return d.PathError("", err)
+}
+
+// errorf returns a DecodeError at the current offset with a formatted cause.
+func (d *DagJsonReader) errorf(format string, args ...any) error {
+	return d.fail(fmt.Errorf(format, args...))
+}
+
+// unexpected returns a DecodeError for reading something other than what was
+// expected.
+func (d *DagJsonReader) unexpected(expected, actual string) error {
+	return &DecodeError{Offset: d.Offset(), Expected: expected, Actual: actual}
+}
@@ -2,14 +2,17 @@ package typegen
 
 import (
 	"bytes"
+	"encoding"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	cid "github.com/ipfs/go-cid"
 )
@@ -21,9 +24,12 @@ const (
 )
 
 var (
-	cidType      = reflect.TypeOf(cid.Cid{})
-	bigIntType   = reflect.TypeOf(big.Int{})
-	deferredType = reflect.TypeOf(Deferred{})
+	cidType      = ReflectType(reflect.TypeOf(cid.Cid{}))
+	bigIntType   = ReflectType(reflect.TypeOf(big.Int{}))
+	deferredType = ReflectType(reflect.TypeOf(Deferred{}))
+
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 )
 
 // Gen is a configurable code generator for DAG JSON types. Use this instead of
@@ -32,9 +38,19 @@ type Gen struct {
 	MaxArrayLength  int // Default: 8192 (MaxLength)
 	MaxByteLength   int // Default: 2<<20 (ByteArrayMaxLen)
 	MaxStringLength int // Default: 8192 (MaxLength)
+	MaxBigIntDigits int // Default: 8192 (MaxLength)
+
+	// Reject unknown and duplicate keys when decoding map-encoded structs, as
+	// if every type had the "strictfields" option.
+	StrictFields bool
 
 	// Write output file in order of type names
 	SortTypeNames bool
+
+	// ImportAliases maps import paths to the names to import them by. Other
+	// packages are imported by their own name, or if that is taken, by their
+	// name followed by a number, choosing in order of import path.
+	ImportAliases map[string]string
 }
 
 func (g Gen) maxArrayLength() int {
@@ -58,6 +74,13 @@ func (g Gen) maxStringLength() int {
 	return g.MaxStringLength
 }
 
+func (g Gen) maxBigIntDigits() int {
+	if g.MaxBigIntDigits == 0 {
+		return MaxLength
+	}
+	return g.MaxBigIntDigits
+}
+
 func (g Gen) doTemplate(w io.Writer, info interface{}, templ string) error {
 	t := template.Must(template.New("").
 		Funcs(template.FuncMap{
@@ -70,6 +93,8 @@ func (g Gen) doTemplate(w io.Writer, info interface{}, templ string) error {
 						val = g.maxArrayLength()
 					case "String":
 						val = g.maxStringLength()
+					case "BigInt":
+						val = g.maxBigIntDigits()
 					default:
 						panic(fmt.Sprintf("error: unknown property [%s]", defaultType))
 					}
@@ -83,14 +108,33 @@ func (g Gen) doTemplate(w io.Writer, info interface{}, templ string) error {
 
 // PrintHeaderAndUtilityMethods is a convenience wrapper around Gen.PrintHeaderAndUtilityMethods
 // using default options.
-func PrintHeaderAndUtilityMethods(w io.Writer, pkg string, typeInfos []*GenTypeInfo) error {
+func PrintHeaderAndUtilityMethods(w io.Writer, pkg string, typeInfos []*GenTypeInfo) ([]*GenTypeInfo, error) {
 	return Gen{}.PrintHeaderAndUtilityMethods(w, pkg, typeInfos)
 }
 
-func (g Gen) PrintHeaderAndUtilityMethods(w io.Writer, pkg string, typeInfos []*GenTypeInfo) error {
+// PrintHeaderAndUtilityMethods writes the package clause and imports of a file
+// holding the given types. It returns copies of typeInfos holding the file's
+// import scope, which names the imported packages, and the encoders for the
+// file must be generated from the copies: encoders generated from the type
+// infos given may name a package differently from the imports written when
+// two packages share a name. typeInfos is left as is, so it can be shared by
+// files generated at the same time.
+func (g Gen) PrintHeaderAndUtilityMethods(w io.Writer, pkg string, typeInfos []*GenTypeInfo) ([]*GenTypeInfo, error) {
+	scope, err := g.newImportScope(typeInfos)
+	if err != nil {
+		return nil, err
+	}
+	scoped := make([]*GenTypeInfo, len(typeInfos))
 	var imports []Import
-	for _, gti := range typeInfos {
-		imports = append(imports, gti.Imports()...)
+	for i, gti := range typeInfos {
+		c := *gti
+		c.scope = scope
+		scoped[i] = &c
+		for _, imp := range c.Imports() {
+			if !slices.Contains(headerImports, imp.PkgPath) {
+				imports = append(imports, imp)
+			}
+		}
 	}
 
 	imports = append(imports, defaultImports...)
@@ -100,27 +144,35 @@ func (g Gen) PrintHeaderAndUtilityMethods(w io.Writer, pkg string, typeInfos []*
 		Package string
 		Imports []Import
 	}{pkg, imports}
-	return g.doTemplate(w, data, `// Code generated by github.com/alanshaw/dag-json-gen. DO NOT EDIT.
+	err = g.doTemplate(w, data, `// Code generated by github.com/alanshaw/dag-json-gen. DO NOT EDIT.
 
 package {{ .Package }}
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"strconv"
 
 {{ range .Imports }}{{ .Name }} "{{ .PkgPath }}"
 {{ end }}
 )
 
 var _ = cid.Undef
+var _ = bytes.NewReader
 var _ = math.E
 var _ = sort.Sort
+var _ = strconv.Itoa
 var _ = errors.Is
 
 `)
+	if err != nil {
+		return nil, err
+	}
+	return scoped, nil
 }
 
 // FieldNameSelf is the name of the field that is the marshal target itself.
@@ -131,59 +183,158 @@ type Field struct {
 	Name    string
 	MapKey  string
 	Pointer bool
-	Type    reflect.Type
-	Pkg     string
-	Const   *string
+	// Type is the field's type when it is known at runtime, and nil for types
+	// loaded from source. GoType returns the type in either case.
+	Type  reflect.Type
+	Pkg   string
+	Const *string
 
 	OmitEmpty   bool
 	Optional    bool
 	PreserveNil bool
 	IterLabel   string
 
+	// Required makes decoding a map-encoded struct fail if the field's key is
+	// missing.
+	Required bool
+	// Default is a Go expression for the value given to the field when it is
+	// missing, or empty for the zero value. It is also the value omitted by
+	// omitempty.
+	Default string
+
 	MaxLen int
+
+	// UnionKind is the data model kind that selects this member of a kinded
+	// union.
+	UnionKind string
+
+	// typ is the field's type, or nil to use Type.
+	typ Type
+	// path is a Go expression for the DecodeError path of the value, set
+	// when decoding.
+	path string
+	// scope names the packages imported by the file being generated.
+	scope *importScope
 }
 
-func typeName(pkg string, t reflect.Type) string {
+func typeName(s *importScope, pkg string, t Type) string {
 	switch t.Kind() {
 	case reflect.Array:
-		return fmt.Sprintf("[%d]%s", t.Len(), typeName(pkg, t.Elem()))
+		return fmt.Sprintf("[%d]%s", t.Len(), typeName(s, pkg, t.Elem()))
 	case reflect.Slice:
-		return "[]" + typeName(pkg, t.Elem())
+		return "[]" + typeName(s, pkg, t.Elem())
 	case reflect.Ptr:
-		return "*" + typeName(pkg, t.Elem())
+		return "*" + typeName(s, pkg, t.Elem())
 	case reflect.Map:
-		return "map[" + typeName(pkg, t.Key()) + "]" + typeName(pkg, t.Elem())
+		return "map[" + typeName(s, pkg, t.Key()) + "]" + typeName(s, pkg, t.Elem())
 	default:
-		pkgPath := t.PkgPath()
-		switch pkgPath {
-		case "":
-			// It's a built-in.
-			return t.String()
-		case pkg:
-			return t.Name()
-		}
-		return fmt.Sprintf("%s.%s", resolvePkgName(pkgPath, t.String()), t.Name())
+		return namedTypeName(s, pkg, t)
+	}
+}
+
+// namedTypeName returns the name of the named type t in the package pkg. The
+// type arguments of an instantiated generic type are qualified too.
+func namedTypeName(s *importScope, pkg string, t Type) string {
+	pkgPath := t.PkgPath()
+	if pkgPath == "" {
+		// It's a built-in.
+		return t.String()
+	}
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i] + qualifyTypeNames(name[i:], func(path, name string) string {
+			if path == pkg {
+				return name
+			}
+			return s.name(path, defaultPkgName(path)) + "." + name
+		})
 	}
+	if pkgPath == pkg {
+		return name
+	}
+	return s.name(pkgPath, pkgNameOf(t)) + "." + name
+}
+
+// GoType returns the type of the field, whether it was loaded from source or
+// is known at runtime.
+func (f Field) GoType() Type {
+	if f.typ == nil && f.Type != nil {
+		return ReflectType(f.Type)
+	}
+	return f.typ
 }
 
 func (f Field) TypeName() string {
-	return typeName(f.Pkg, f.Type)
+	return typeName(f.scope, f.Pkg, f.GoType())
 }
 
 func (f Field) ElemName() string {
-	return typeName(f.Pkg, f.Type.Elem())
+	return typeName(f.scope, f.Pkg, f.GoType().Elem())
 }
 
 func (f Field) IsArray() bool {
-	return f.Type.Kind() == reflect.Array
+	return f.GoType().Kind() == reflect.Array
 }
 
-func (f Field) EmptyVal() (string, error) {
-	return emptyValForField(f)
+// NonEmpty returns a condition that is true when the field is not empty, for
+// omitempty.
+func (f Field) NonEmpty() (string, error) {
+	return nonEmptyCond(f)
 }
 
 func (f Field) Len() int {
-	return f.Type.Len()
+	return f.GoType().Len()
+}
+
+// Var returns the name to use for a temporary variable, qualified by the
+// iteration label so that the variables of nested containers do not shadow
+// those of their parents.
+func (f Field) Var(name string) string {
+	if f.IterLabel == "" || f.IterLabel == "i" {
+		return name
+	}
+	return name + f.IterLabel
+}
+
+// Path returns a Go expression for the DecodeError path of the value, which
+// may include the indexes and keys of enclosing lists and maps.
+func (f Field) Path() string {
+	if f.path == "" {
+		return `""`
+	}
+	return f.path
+}
+
+// subPath returns the path expression for a value within f, given an
+// expression for its path element.
+func (f Field) subPath(elem string) string {
+	if f.path == "" {
+		return elem
+	}
+	return f.path + " + " + elem
+}
+
+// elemField describes the elements of the slice, array or map field f, as
+// accessed through the given expression.
+func (f Field) elemField(name string) Field {
+	e := f.GoType().Elem()
+	var pointer bool
+	if e.Kind() == reflect.Ptr {
+		e = e.Elem()
+		pointer = true
+	}
+	label := f.IterLabel
+	if label == "" {
+		label = "i"
+	}
+	return Field{
+		Name:      name,
+		typ:       e,
+		Pkg:       f.Pkg,
+		Pointer:   pointer,
+		IterLabel: string([]byte{label[0] + 1}),
+		scope:     f.scope,
+	}
 }
 
 type GenTypeInfo struct {
@@ -191,27 +342,160 @@ type GenTypeInfo struct {
 	Fields              []Field
 	MandatoryFieldCount int
 	Transparent         bool
+
+	// Unknown is the field tagged "unknown", a map[string]jsg.Deferred that
+	// collects the entries of a map-encoded struct with no matching field, or
+	// nil if there is none.
+	Unknown *Field
+	// StrictFields makes decoding a map-encoded struct fail on keys that
+	// don't match a field, unless there is an Unknown field to hold them, and
+	// on keys that appear more than once.
+	StrictFields bool
+
+	// Representation is "tuple" or "map" for a struct that declares how it is
+	// encoded with a blank field tagged as such, or empty to leave the choice
+	// to the generator.
+	Representation string
+
+	// Union is the representation of a union type ("keyed", "envelope",
+	// "inline" or "kinded"), or empty if the type is not a union.
+	Union           string
+	DiscriminantKey string
+	ContentKey      string
+
+	// Generic is the name of the generic type that Name instantiates, or empty
+	// if the type is not an instantiation, and TypeArgs the number of its type
+	// arguments. Go has no methods on instantiations, so their codecs are
+	// functions called by methods of the generic type that switch on the type
+	// of the receiver (see GenGenericMethods).
+	Generic  string
+	TypeArgs int
+
+	pkg string
+	typ Type
+	// scope names the packages imported by the file the type is generated
+	// into, set on the copies returned by PrintHeaderAndUtilityMethods.
+	scope *importScope
+}
+
+// Func returns the signature, without results, of the codec method called
+// method with parameters params. For an instantiated generic type it is
+// instead a function taking the receiver as its first parameter.
+func (gti *GenTypeInfo) Func(method, params string) string {
+	if gti.Generic == "" {
+		return fmt.Sprintf("(t *%s) %s(%s)", gti.Name, method, params)
+	}
+	return fmt.Sprintf("%s(t *%s, %s)", instanceFuncName(gti.Name, method), gti.Name, params)
+}
+
+// instanceFuncName returns the name of the function implementing method for
+// the instantiated generic type name.
+func instanceFuncName(name, method string) string {
+	name = strings.NewReplacer("*", " ptr ", "[]", " slice ").Replace(name)
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), "_")
+	return strings.ToLower(method[:1]) + method[1:] + "_" + name
 }
 
 func (gti *GenTypeInfo) Imports() []Import {
 	var imports []Import
+	if gti.Generic != "" {
+		// The type arguments are named by the codec functions.
+		imports = append(imports, gti.scope.importsForType(gti.pkg, gti.typ)...)
+	}
 	for _, f := range gti.Fields {
-		switch f.Type.Kind() {
+		switch f.GoType().Kind() {
 		case reflect.Struct:
-			if !f.Pointer && f.Type != bigIntType {
-				continue
-			}
-			if f.Type == cidType {
+			// The generated code never names big.Int or cid.Cid directly (and cid
+			// is always imported anyway), and other structs only need importing
+			// when allocated through a pointer.
+			if !f.Pointer || sameType(f.GoType(), bigIntType) || sameType(f.GoType(), cidType) {
 				continue
 			}
 		case reflect.Bool:
 			continue
 		}
-		imports = append(imports, ImportsForType(f.Pkg, f.Type)...)
+		imports = append(imports, gti.scope.importsForType(f.Pkg, f.GoType())...)
 	}
 	return imports
 }
 
+// packages calls add with the import path and default name of each package
+// named by the type and its fields, as typePackages does.
+func (gti *GenTypeInfo) packages(add func(path, name string, exact bool)) {
+	if gti.typ != nil {
+		typePackages(gti.pkg, gti.typ, add)
+	}
+	for _, f := range gti.Fields {
+		typePackages(f.Pkg, f.GoType(), add)
+	}
+	if gti.Unknown != nil {
+		typePackages(gti.Unknown.Pkg, gti.Unknown.GoType(), add)
+	}
+}
+
+// newImportScope returns an import scope for a file holding the given types,
+// with the names of their packages reserved.
+func (g Gen) newImportScope(typeInfos []*GenTypeInfo) (*importScope, error) {
+	s, err := newImportScope(g.ImportAliases)
+	if err != nil {
+		return nil, err
+	}
+	// Exact package names are preferred to guessed ones.
+	defaults := make(map[string]string)
+	exact := make(map[string]bool)
+	for _, gti := range typeInfos {
+		gti.packages(func(path, name string, isExact bool) {
+			if _, ok := defaults[path]; !ok || (isExact && !exact[path]) {
+				defaults[path] = name
+				exact[path] = isExact
+			}
+		})
+	}
+	s.reserve(defaults)
+	return s, nil
+}
+
+// inScope returns a copy of gti using the names of its import scope, or of a
+// scope of its own if it has none.
+func (g Gen) inScope(gti *GenTypeInfo) (*GenTypeInfo, error) {
+	s := gti.scope
+	if s == nil {
+		var err error
+		if s, err = g.newImportScope([]*GenTypeInfo{gti}); err != nil {
+			return nil, err
+		}
+	}
+	c := *gti
+	c.scope = s
+	if c.typ != nil {
+		c.Name = namedTypeName(s, c.pkg, c.typ)
+	}
+	c.Fields = make([]Field, len(gti.Fields))
+	for i, f := range gti.Fields {
+		f.scope = s
+		c.Fields[i] = f
+	}
+	if gti.Unknown != nil {
+		u := *gti.Unknown
+		u.scope = s
+		c.Unknown = &u
+	}
+	return &c, nil
+}
+
+// RequiredFields returns the fields tagged "required".
+func (gti *GenTypeInfo) RequiredFields() []Field {
+	var fields []Field
+	for _, f := range gti.Fields {
+		if f.Required {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
 func (gti *GenTypeInfo) MaxMapKeyLength() int {
 	var mlen int
 	for _, f := range gti.Fields {
@@ -226,7 +510,13 @@ func nameIsExported(name string) bool {
 	return strings.ToUpper(name[0:1]) == name[0:1]
 }
 
+// ParseTypeInfo describes the type of the given value for generation. A
+// *GenTypeInfo, such as one from SourcePackage.TypeInfo, is returned as is.
 func ParseTypeInfo(itype interface{}) (*GenTypeInfo, error) {
+	if gti, ok := itype.(*GenTypeInfo); ok {
+		return gti, nil
+	}
+
 	// If we're handed *Foo instead of value Foo, deref the pointer.
 	// ParseTypeInfo is only every handed a top level type, so this shouldn't violate any expectations.
 	iv := reflect.ValueOf(itype)
@@ -235,25 +525,45 @@ func ParseTypeInfo(itype interface{}) (*GenTypeInfo, error) {
 		iv = iv.Elem()
 	default:
 	}
-	t := iv.Type()
+	return ParseType(ReflectType(iv.Type()))
+}
 
+// ParseType describes the named type t for generation.
+func ParseType(t Type) (*GenTypeInfo, error) {
 	pkg := t.PkgPath()
 
+	name := namedTypeName(nil, pkg, t)
+	var generic string
+	var typeArgs int
+	if i := strings.IndexByte(t.Name(), '['); i >= 0 {
+		generic = t.Name()[:i]
+		typeArgs = 1 + strings.Count(topLevel(t.Name()[i+1:len(t.Name())-1]), ",")
+	}
+
 	out := GenTypeInfo{
-		Name:        t.Name(),
+		Name:        name,
+		Generic:     generic,
+		TypeArgs:    typeArgs,
+		pkg:         pkg,
+		typ:         t,
 		Transparent: false,
 	}
 
 	if t.Kind() != reflect.Struct {
 		return &GenTypeInfo{
-			Name:        t.Name(),
+			Name:        name,
+			Generic:     generic,
+			TypeArgs:    typeArgs,
+			pkg:         pkg,
+			typ:         t,
 			Transparent: true,
 			Fields: []Field{
 				{
 					Name:        FieldNameSelf,
 					MapKey:      "",
 					Pointer:     t.Kind() == reflect.Ptr,
-					Type:        t,
+					Type:        reflectTypeOf(t),
+					typ:         t,
 					Pkg:         pkg,
 					Const:       nil,
 					OmitEmpty:   false,
@@ -265,16 +575,98 @@ func ParseTypeInfo(itype interface{}) (*GenTypeInfo, error) {
 		}, nil
 	}
 
-	for i := 0; i < t.NumField(); i++ {
+	var depths []int
+	if err := parseFields(t, t, pkg, "", 0, &out, &depths); err != nil {
+		return nil, err
+	}
+	if err := promoteFields(t, &out, depths); err != nil {
+		return nil, err
+	}
+
+	if out.Union != "" && out.Representation != "" {
+		return nil, fmt.Errorf("%s: union types cannot be %s encoded", t, out.Representation)
+	}
+
+	if out.Unknown != nil && (out.Union != "" || out.Transparent) {
+		return nil, fmt.Errorf("%s: unknown fields are not supported in unions or transparent structs", t)
+	}
+
+	if out.Union != "" {
+		if err := checkUnion(&out); err != nil {
+			return nil, fmt.Errorf("%s: %w", t, err)
+		}
+		return &out, nil
+	}
+
+	for i, field := range out.Fields {
+		if field.Optional {
+			continue
+		}
+		if out.MandatoryFieldCount != i {
+			return nil, fmt.Errorf("mandatory field %s.%s cannot come after optional fields", t, field.Name)
+		}
+		out.MandatoryFieldCount++
+	}
+
+	return &out, nil
+}
+
+// topLevel returns s with anything in brackets or braces removed.
+func topLevel(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		default:
+			if depth == 0 {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// parseFields appends the fields of the struct st to out, and the depth of each
+// to depths. Embedded structs, and struct fields tagged inline or flatten, are
+// flattened: their fields are parsed in turn, one level deeper, with prefix
+// being the Go selector of the flattened struct within top.
+func parseFields(top, st Type, pkg, prefix string, depth int, out *GenTypeInfo, depths *[]int) error {
+	for i := 0; i < st.NumField(); i++ {
 		if out.Transparent {
-			return nil, fmt.Errorf("transparent structs must have exactly one field")
+			return fmt.Errorf("transparent structs must have exactly one field")
 		}
 
-		f := t.Field(i)
-		if !nameIsExported(f.Name) {
+		f := st.Field(i)
+		if f.Name == "_" {
+			if depth > 0 {
+				// Flattened structs have no type options, see hasTypeOptions.
+				continue
+			}
+			// Blank fields carry options for the type as a whole.
+			tags, err := tagparse(f.Tag.Get("dagjsongen"))
+			if err != nil {
+				return fmt.Errorf("invalid tag format: %w", err)
+			}
+			out.Union = tags["union"]
+			out.DiscriminantKey = tags["discriminantkey"]
+			out.ContentKey = tags["contentkey"]
+			_, out.StrictFields = tags["strictfields"]
+			// A bare word other than the options above names the
+			// representation.
+			switch r := tags["name"]; r {
+			case "", "tuple", "map":
+				out.Representation = r
+			default:
+				return fmt.Errorf("%s: unknown type option %q", top, r)
+			}
 			continue
 		}
 
+		name := prefix + f.Name
 		ft := f.Type
 		var pointer bool
 		if ft.Kind() == reflect.Ptr {
@@ -287,20 +679,59 @@ func ParseTypeInfo(itype interface{}) (*GenTypeInfo, error) {
 		tagval := f.Tag.Get("dagjsongen")
 		tags, err := tagparse(tagval)
 		if err != nil {
-			return nil, fmt.Errorf("invalid tag format: %w", err)
+			return fmt.Errorf("invalid tag format: %w", err)
 		}
 
 		if _, ok := tags["ignore"]; ok {
 			continue
 		}
 
+		// Like encoding/json, embedded structs are flattened unless given a
+		// name, including those of unexported types. Structs declaring type
+		// options, such as unions, keep their own encoding.
+		_, inline := tags["inline"]
+		if inline && tags["name"] != "" {
+			return fmt.Errorf("%s.%s: a flattened field cannot have a name", top, name)
+		}
+		if inline || (f.Anonymous && !pointer && tags["name"] == "" && flattenable(ft) && !hasTypeOptions(ft)) {
+			if pointer || !flattenable(ft) {
+				return fmt.Errorf("%s.%s: only struct values can be flattened", top, name)
+			}
+			if hasTypeOptions(ft) {
+				return fmt.Errorf("%s.%s: structs with type options cannot be flattened", top, name)
+			}
+			delete(tags, "inline")
+			if len(tags) > 0 {
+				return fmt.Errorf("%s.%s: flattened fields take no other options", top, name)
+			}
+			if err := parseFields(top, ft, pkg, name+".", depth+1, out, depths); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !nameIsExported(f.Name) {
+			continue
+		}
+
+		if _, ok := tags["unknown"]; ok {
+			if pointer || ft.Kind() != reflect.Map || ft.Name() != "" || ft.Key().Kind() != reflect.String || ft.Key().Name() != "string" || !sameType(ft.Elem(), deferredType) {
+				return fmt.Errorf("%s.%s: unknown fields must be collected in a map[string]jsg.Deferred", top, name)
+			}
+			if out.Unknown != nil {
+				return fmt.Errorf("%s: only one unknown field is allowed", top)
+			}
+			out.Unknown = &Field{Name: name, Type: reflectTypeOf(ft), typ: ft, Pkg: pkg, MaxLen: NoUsrMaxLen}
+			continue
+		}
+
 		if tags["name"] != "" {
 			mapk = tags["name"]
 		}
 		if msize := tags["maxlen"]; msize != "" {
 			val, err := strconv.Atoi(msize)
 			if err != nil {
-				return nil, fmt.Errorf("maxsize tag value was not valid: %w", err)
+				return fmt.Errorf("maxsize tag value was not valid: %w", err)
 			}
 
 			usrMaxLen = val
@@ -309,50 +740,209 @@ func ParseTypeInfo(itype interface{}) (*GenTypeInfo, error) {
 		var constval *string
 		if cv, hasconst := tags["const"]; hasconst {
 			if ft.Kind() != reflect.String {
-				return nil, fmt.Errorf("const vals are only supported for string types")
+				return fmt.Errorf("const vals are only supported for string types")
 			}
 			constval = &cv
 		}
 
 		_, transparent := tags["transparent"]
+		if transparent && depth > 0 {
+			return fmt.Errorf("%s.%s: flattened structs cannot have a transparent field", top, name)
+		}
 		if transparent && len(out.Fields) > 0 {
-			return nil, fmt.Errorf("only one transparent field is allowed")
+			return fmt.Errorf("only one transparent field is allowed")
 		}
 		out.Transparent = transparent
 
 		_, omitempty := tags["omitempty"]
 		_, optional := tags["optional"]
 		_, preservenil := tags["preservenil"]
+		_, required := tags["required"]
+
+		if required && optional {
+			return fmt.Errorf("%s.%s: a field cannot be both required and optional", top, name)
+		}
+		if required && omitempty {
+			return fmt.Errorf("%s.%s: a required field cannot be omitempty", top, name)
+		}
+
+		var defaultval string
+		if dv, hasdefault := tags["default"]; hasdefault {
+			if required {
+				return fmt.Errorf("%s.%s: a required field cannot have a default", top, name)
+			}
+			if pointer {
+				return fmt.Errorf("%s.%s: defaults are not supported for pointers", top, name)
+			}
+			defaultval, err = defaultValue(ft, dv)
+			if err != nil {
+				return fmt.Errorf("%s.%s: invalid default: %w", top, name, err)
+			}
+		}
 
 		if preservenil && ft.Kind() != reflect.Slice {
-			return nil, fmt.Errorf("%T.%s: preservenil is only supported on slice types", itype, f.Name)
+			return fmt.Errorf("%s.%s: preservenil is only supported on slice types", top, name)
 		}
 
 		out.Fields = append(out.Fields, Field{
-			Name:        f.Name,
+			Name:        name,
 			MapKey:      mapk,
 			Pointer:     pointer,
-			Type:        ft,
+			Type:        reflectTypeOf(ft),
+			typ:         ft,
 			Pkg:         pkg,
 			OmitEmpty:   omitempty,
 			PreserveNil: preservenil,
 			MaxLen:      usrMaxLen,
 			Const:       constval,
 			Optional:    optional,
+			Required:    required,
+			Default:     defaultval,
+			UnionKind:   tags["kind"],
 		})
+		*depths = append(*depths, depth)
 	}
+	return nil
+}
 
-	for i, field := range out.Fields {
-		if field.Optional {
+// flattenable reports whether t is a struct whose fields can be flattened into
+// another's.
+func flattenable(t Type) bool {
+	return t.Kind() == reflect.Struct && !sameType(t, cidType) && !sameType(t, bigIntType) && !sameType(t, deferredType)
+}
+
+// hasTypeOptions reports whether the struct t declares options for the type as
+// a whole in a tagged blank field.
+func hasTypeOptions(t Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Name == "_" && f.Tag.Get("dagjsongen") != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// promoteFields resolves the fields of out that share a key, at the given
+// depths, the way Go resolves promoted field names: the shallowest field wins,
+// and two fields at the same depth are a conflict.
+func promoteFields(t Type, out *GenTypeInfo, depths []int) error {
+	shallowest := make(map[string]int)
+	for i, f := range out.Fields {
+		d, ok := shallowest[f.MapKey]
+		if !ok || depths[i] < d {
+			shallowest[f.MapKey] = depths[i]
+		}
+	}
+	fields := out.Fields[:0]
+	winners := make(map[string]string)
+	for i, f := range out.Fields {
+		if depths[i] > shallowest[f.MapKey] {
 			continue
 		}
-		if out.MandatoryFieldCount != i {
-			return nil, fmt.Errorf("mandatory field %T.%s cannot come after optional fields", itype, field.Name)
+		if other, ok := winners[f.MapKey]; ok {
+			return fmt.Errorf("%s: fields %s and %s conflict on the key %q", t, other, f.Name, f.MapKey)
 		}
-		out.MandatoryFieldCount++
+		winners[f.MapKey] = f.Name
+		fields = append(fields, f)
 	}
+	out.Fields = fields
+	return nil
+}
 
-	return &out, nil
+// checkUnion validates the members of a union type and works out the kind of
+// each member of a kinded union.
+func checkUnion(gti *GenTypeInfo) error {
+	if gti.Transparent {
+		return fmt.Errorf("union types cannot be transparent")
+	}
+	if len(gti.Fields) == 0 {
+		return fmt.Errorf("union types must have at least one member")
+	}
+	switch gti.Union {
+	case "keyed", "kinded":
+	case "envelope":
+		if gti.DiscriminantKey == "" || gti.ContentKey == "" {
+			return fmt.Errorf("envelope unions need both discriminantkey and contentkey")
+		}
+		if gti.DiscriminantKey == gti.ContentKey {
+			return fmt.Errorf("envelope union discriminantkey and contentkey must differ")
+		}
+	case "inline":
+		if gti.DiscriminantKey == "" {
+			return fmt.Errorf("inline unions need a discriminantkey")
+		}
+	default:
+		return fmt.Errorf("unknown union representation %q", gti.Union)
+	}
+
+	seen := make(map[string]string)
+	for i, f := range gti.Fields {
+		if !f.Pointer {
+			return fmt.Errorf("union member %s must be a pointer", f.Name)
+		}
+		key := f.MapKey
+		if gti.Union == "kinded" {
+			kind, err := unionMemberKind(f)
+			if err != nil {
+				return err
+			}
+			gti.Fields[i].UnionKind = kind
+			key = kind
+		} else if gti.Union == "inline" {
+			if f.GoType().Kind() != reflect.Struct || sameType(f.GoType(), cidType) || sameType(f.GoType(), bigIntType) || sameType(f.GoType(), deferredType) {
+				return fmt.Errorf("inline union member %s must be a map encoded struct", f.Name)
+			}
+		}
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("union members %s and %s share the discriminant %q", other, f.Name, key)
+		}
+		seen[key] = f.Name
+	}
+	return nil
+}
+
+// unionMemberKind returns the data model kind of a kinded union member. Structs
+// other than links and big integers may be encoded as maps or lists, so they
+// must say which with a "kind" tag.
+func unionMemberKind(f Field) (string, error) {
+	kind := f.UnionKind
+	if kind == "" {
+		switch f.GoType().Kind() {
+		case reflect.String:
+			kind = "string"
+		case reflect.Bool:
+			kind = "bool"
+		case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int,
+			reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+			kind = "int"
+		case reflect.Float32, reflect.Float64:
+			kind = "float"
+		case reflect.Slice, reflect.Array:
+			kind = "list"
+			if f.GoType().Elem().Kind() == reflect.Uint8 {
+				kind = "bytes"
+			}
+		case reflect.Map:
+			kind = "map"
+		case reflect.Struct:
+			switch {
+			case sameType(f.GoType(), cidType):
+				kind = "link"
+			case sameType(f.GoType(), bigIntType):
+				kind = "int"
+			default:
+				return "", fmt.Errorf("kinded union member %s must have a kind tag of map or list", f.Name)
+			}
+		default:
+			return "", fmt.Errorf("kinded union member %s has unsupported kind %s", f.Name, f.GoType().Kind())
+		}
+	}
+	switch kind {
+	case "bool", "int", "float", "string", "bytes", "list", "map", "link":
+		return kind, nil
+	default:
+		return "", fmt.Errorf("kinded union member %s has unknown kind %q", f.Name, kind)
+	}
 }
 
 func tagparse(v string) (map[string]string, error) {
@@ -363,10 +953,7 @@ func tagparse(v string) (map[string]string, error) {
 			continue
 		}
 		if strings.Contains(elem, "=") {
-			parts := strings.Split(elem, "=")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("struct tags with params must be of form X=Y")
-			}
+			parts := strings.SplitN(elem, "=", 2)
 			out[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
 		} else if elem == "omitempty" {
 			out["omitempty"] = "true"
@@ -378,6 +965,14 @@ func tagparse(v string) (map[string]string, error) {
 			out["transparent"] = "true"
 		} else if elem == "optional" {
 			out["optional"] = "true"
+		} else if elem == "unknown" {
+			out["unknown"] = "true"
+		} else if elem == "required" {
+			out["required"] = "true"
+		} else if elem == "strictfields" {
+			out["strictfields"] = "true"
+		} else if elem == "inline" || elem == "flatten" {
+			out["inline"] = "true"
 		} else {
 			out["name"] = elem
 		}
@@ -385,6 +980,35 @@ func tagparse(v string) (map[string]string, error) {
 	return out, nil
 }
 
+// defaultValue returns the Go expression for the value of a default tag, which
+// must be a literal of the field's kind.
+func defaultValue(t Type, v string) (string, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return strconv.Quote(v), nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(v)
+		return strconv.FormatBool(b), err
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(v, 10, t.Bits())
+		return strconv.FormatInt(n, 10), err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(v, 10, t.Bits())
+		return strconv.FormatUint(n, 10), err
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(v, t.Bits())
+		if err != nil {
+			return "", err
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return "", fmt.Errorf("unsupported float value: %v", n)
+		}
+		return strconv.FormatFloat(n, 'g', -1, t.Bits()), nil
+	default:
+		return "", fmt.Errorf("defaults are not supported for %s", t.Kind())
+	}
+}
+
 func (g Gen) emitDagJsonMarshalStringField(w io.Writer, f Field) error {
 	if f.Pointer {
 		return g.doTemplate(w, f, `
@@ -419,12 +1043,9 @@ func (g Gen) emitDagJsonMarshalStringField(w io.Writer, f Field) error {
 }
 
 func (g Gen) emitDagJsonMarshalStructField(w io.Writer, f Field) error {
-	switch f.Type {
-	case bigIntType:
+	switch {
+	case sameType(f.GoType(), bigIntType):
 		return g.doTemplate(w, f, `
-		if {{ .Name }} != nil && {{ .Name }}.Sign() < 0 {
-			return fmt.Errorf("Value in field {{ .Name | js }} was a negative big-integer (not supported)")
-		}
 		if {{ .Name }} == nil {
 			if err := jw.WriteUint8(0); err != nil {
 				return fmt.Errorf("{{ .Name }}: %w", err)
@@ -435,7 +1056,7 @@ func (g Gen) emitDagJsonMarshalStructField(w io.Writer, f Field) error {
 			}
 		}`)
 
-	case cidType:
+	case sameType(f.GoType(), cidType):
 		return g.doTemplate(w, f, `
 		{{ if .Pointer }}
 			if {{ .Name }} == nil {
@@ -460,7 +1081,9 @@ func (g Gen) emitDagJsonMarshalStructField(w io.Writer, f Field) error {
 	}
 }
 
-func (g Gen) emitDagJsonMarshalUint64Field(w io.Writer, f Field) error {
+// emitDagJsonMarshalUintField handles all unsigned integer kinds by widening
+// them to uint64.
+func (g Gen) emitDagJsonMarshalUintField(w io.Writer, f Field) error {
 	return g.doTemplate(w, f, `
 	{{ if .Pointer }}
 		if {{ .Name }} == nil {
@@ -479,17 +1102,28 @@ func (g Gen) emitDagJsonMarshalUint64Field(w io.Writer, f Field) error {
 	{{ end }}`)
 }
 
-func (g Gen) emitDagJsonMarshalUint8Field(w io.Writer, f Field) error {
-	if f.Pointer {
-		return fmt.Errorf("pointers to integers not supported")
-	}
+// emitDagJsonMarshalIntField handles all signed integer kinds by widening them
+// to int64.
+func (g Gen) emitDagJsonMarshalIntField(w io.Writer, f Field) error {
 	return g.doTemplate(w, f, `
-	if err := jw.WriteUint8(uint8({{ .Name }})); err != nil {
-		return fmt.Errorf("{{ .Name }}: %w", err)
-	}`)
+	{{ if .Pointer }}
+		if {{ .Name }} == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("{{ .Name }}: %w", err)
+			}
+		} else {
+			if err := jw.WriteInt64(int64(*{{ .Name }})); err != nil {
+				return fmt.Errorf("{{ .Name }}: %w", err)
+			}
+		}
+	{{ else }}
+		if err := jw.WriteInt64(int64({{ .Name }})); err != nil {
+			return fmt.Errorf("{{ .Name }}: %w", err)
+		}
+	{{ end }}`)
 }
 
-func (g Gen) emitDagJsonMarshalInt64Field(w io.Writer, f Field) error {
+func (g Gen) emitDagJsonMarshalFloatField(w io.Writer, f Field) error {
 	return g.doTemplate(w, f, `
 	{{ if .Pointer }}
 		if {{ .Name }} == nil {
@@ -497,12 +1131,12 @@ func (g Gen) emitDagJsonMarshalInt64Field(w io.Writer, f Field) error {
 				return fmt.Errorf("{{ .Name }}: %w", err)
 			}
 		} else {
-			if err := jw.WriteInt64(int64(*{{ .Name }})); err != nil {
+			if err := jw.WriteFloat{{ .GoType.Bits }}(float{{ .GoType.Bits }}(*{{ .Name }})); err != nil {
 				return fmt.Errorf("{{ .Name }}: %w", err)
 			}
 		}
 	{{ else }}
-		if err := jw.WriteInt64(int64({{ .Name }})); err != nil {
+		if err := jw.WriteFloat{{ .GoType.Bits }}(float{{ .GoType.Bits }}({{ .Name }})); err != nil {
 			return fmt.Errorf("{{ .Name }}: %w", err)
 		}
 	{{ end }}`)
@@ -528,8 +1162,74 @@ func (g Gen) emitDagJsonMarshalBoolField(w io.Writer, f Field) error {
 	}
 }
 
+// mapKeyKind classifies how keys of the given map key type are encoded as
+// DAG-JSON strings. Keys of string kind are used directly, keys implementing
+// encoding.TextMarshaler are marshaled and integer keys are written in
+// decimal, following encoding/json.
+func mapKeyKind(kt Type) (string, error) {
+	switch kt.Kind() {
+	case reflect.String:
+		return "string", nil
+	case reflect.Ptr, reflect.Interface:
+		return "", fmt.Errorf("unsupported map key type: %s", kt)
+	}
+	if marshaler, unmarshaler := kt.textMethods(); marshaler {
+		if !unmarshaler {
+			return "", fmt.Errorf("map key type %s implements encoding.TextMarshaler but not encoding.TextUnmarshaler", kt)
+		}
+		return "text", nil
+	}
+	switch kt.Kind() {
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		return "int", nil
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return "uint", nil
+	}
+	return "", fmt.Errorf("unsupported map key type: %s", kt)
+}
+
+// mapKeyField describes a map field along with how its keys are encoded.
+type mapKeyField struct {
+	Field
+	KeyKind     string
+	KeyTypeName string
+	KeyBitSize  string
+}
+
+func newMapKeyField(f Field) (mapKeyField, error) {
+	kt := f.GoType().Key()
+	kind, err := mapKeyKind(kt)
+	if err != nil {
+		return mapKeyField{}, err
+	}
+	var bitSize string
+	if kind == "int" || kind == "uint" {
+		bitSize = strconv.Itoa(kt.Bits())
+		if kt.Kind() == reflect.Int || kt.Kind() == reflect.Uint {
+			bitSize = "strconv.IntSize"
+		}
+	}
+	return mapKeyField{
+		Field:       f,
+		KeyKind:     kind,
+		KeyTypeName: typeName(f.scope, f.Pkg, kt),
+		KeyBitSize:  bitSize,
+	}, nil
+}
+
 func (g Gen) emitDagJsonMarshalMapField(w io.Writer, f Field) error {
-	err := g.doTemplate(w, f, `
+	if f.Pointer {
+		return fmt.Errorf("pointers to maps not supported")
+	}
+
+	mf, err := newMapKeyField(f)
+	if err != nil {
+		return err
+	}
+
+	// Keys are sorted by their encoded form, so non-string keys are encoded up
+	// front and mapped back to the original key to look up the value.
+	err = g.doTemplate(w, mf, `
 	{
 		if len({{ .Name }}) > 4096 {
 			return fmt.Errorf("cannot marshal {{ .Name }} map too large")
@@ -540,56 +1240,63 @@ func (g Gen) emitDagJsonMarshalMapField(w io.Writer, f Field) error {
 		}
 
 		keys := make([]string, 0, len({{ .Name }}))
-		for k := range {{ .Name }} {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for i, k := range keys {
-			if i > 0 {
+		{{ if eq .KeyKind "string" }}
+			for k := range {{ .Name }} {
+				keys = append(keys, string(k))
+			}
+		{{ else }}
+			mkeys := make(map[string]{{ .KeyTypeName }}, len({{ .Name }}))
+			for k := range {{ .Name }} {
+				{{ if eq .KeyKind "text" }}
+					kk := k
+					kb, err := kk.MarshalText()
+					if err != nil {
+						return fmt.Errorf("{{ .Name }}: %w", err)
+					}
+					ks := string(kb)
+					if _, ok := mkeys[ks]; ok {
+						return fmt.Errorf("{{ .Name }}: duplicate map key %q", ks)
+					}
+				{{ else if eq .KeyKind "int" }}
+					ks := strconv.FormatInt(int64(k), 10)
+				{{ else }}
+					ks := strconv.FormatUint(uint64(k), 10)
+				{{ end }}
+				keys = append(keys, ks)
+				mkeys[ks] = k
+			}
+		{{ end }}
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
 				if err := jw.WriteComma(); err != nil {
 					return fmt.Errorf("{{ .Name }}: %w", err)
 				}
 			}
-			v := {{ .Name }}[k]`)
+			{{ if eq .KeyKind "string" }}
+				v := {{ .Name }}[{{ .KeyTypeName }}(k)]
+			{{ else }}
+				v := {{ .Name }}[mkeys[k]]
+			{{ end }}`)
 	if err != nil {
 		return err
 	}
 
 	// Map key
-	switch f.Type.Key().Kind() {
-	case reflect.String:
-		if err := g.emitDagJsonMarshalStringField(w, Field{Name: "k"}); err != nil {
-			return err
-		}
-		if err := g.doTemplate(w, f, `
+	if err := g.emitDagJsonMarshalStringField(w, Field{Name: "k"}); err != nil {
+		return err
+	}
+	if err := g.doTemplate(w, f, `
 			if err := jw.WriteObjectColon(); err != nil {
 				return fmt.Errorf("{{ .Name }}: %w", err)
 			}
 		`); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("non-string map keys are not yet supported")
+		return err
 	}
 
 	// Map value
-	switch f.Type.Elem().Kind() {
-	case reflect.String:
-		if err := g.emitDagJsonMarshalStringField(w, Field{Name: "v"}); err != nil {
-			return err
-		}
-	case reflect.Ptr:
-		if f.Type.Elem().Elem().Kind() != reflect.Struct {
-			return fmt.Errorf("unsupported map elem ptr type: %s", f.Type.Elem())
-		}
-
-		fallthrough
-	case reflect.Struct:
-		if err := g.emitDagJsonMarshalStructField(w, Field{Name: "v", Type: f.Type.Elem(), Pkg: f.Pkg}); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("currently unsupported map elem type: %s", f.Type.Elem())
+	if err := g.emitDagJsonMarshalField(w, f.elemField("v")); err != nil {
+		return err
 	}
 
 	return g.doTemplate(w, f, `
@@ -605,7 +1312,7 @@ func (g Gen) emitDagJsonMarshalSliceField(w io.Writer, f Field) error {
 	if f.Pointer {
 		return fmt.Errorf("pointers to slices not supported")
 	}
-	e := f.Type.Elem()
+	e := f.GoType().Elem()
 
 	if e.Kind() == reflect.Uint8 {
 		return g.doTemplate(w, f, `
@@ -627,12 +1334,6 @@ func (g Gen) emitDagJsonMarshalSliceField(w io.Writer, f Field) error {
 		{{ end }}`)
 	}
 
-	var pointer bool
-	if e.Kind() == reflect.Ptr {
-		e = e.Elem()
-		pointer = true
-	}
-
 	err := g.doTemplate(w, f, `
 	if len({{ .Name }}) > {{ MaxLen .MaxLen "Array" }} {
 		return fmt.Errorf("Slice value in field {{ .Name }} was too long")
@@ -657,24 +1358,7 @@ func (g Gen) emitDagJsonMarshalSliceField(w io.Writer, f Field) error {
 		return err
 	}
 
-	subf := Field{Name: "v", Type: e, Pkg: f.Pkg, Pointer: pointer}
-	switch e.Kind() {
-	default:
-		err = fmt.Errorf("do not yet support slices of %s yet", e.Kind())
-	case reflect.Struct:
-		err = g.emitDagJsonMarshalStructField(w, subf)
-	case reflect.Uint64:
-		err = g.emitDagJsonMarshalUint64Field(w, subf)
-	case reflect.Uint8:
-		err = g.emitDagJsonMarshalUint8Field(w, subf)
-	case reflect.Int64:
-		err = g.emitDagJsonMarshalInt64Field(w, subf)
-	case reflect.Slice:
-		err = g.emitDagJsonMarshalSliceField(w, subf)
-	case reflect.String:
-		err = g.emitDagJsonMarshalStringField(w, subf)
-	}
-	if err != nil {
+	if err := g.emitDagJsonMarshalField(w, f.elemField("v")); err != nil {
 		return err
 	}
 
@@ -697,7 +1381,7 @@ func (g Gen) emitDagJsonMarshalArrayField(w io.Writer, f Field) error {
 	if f.Pointer {
 		return fmt.Errorf("pointers to arrays not supported")
 	}
-	e := f.Type.Elem()
+	e := f.GoType().Elem()
 
 	// Note: this re-slices the slice to deal with arrays.
 	if e.Kind() == reflect.Uint8 {
@@ -710,12 +1394,6 @@ func (g Gen) emitDagJsonMarshalArrayField(w io.Writer, f Field) error {
 		}`)
 	}
 
-	var pointer bool
-	if e.Kind() == reflect.Ptr {
-		e = e.Elem()
-		pointer = true
-	}
-
 	err := g.doTemplate(w, f, `
 	if len({{ .Name }}) > {{ MaxLen .MaxLen "Array" }} {
 		return fmt.Errorf("Slice value in field {{ .Name }} was too long")
@@ -733,24 +1411,7 @@ func (g Gen) emitDagJsonMarshalArrayField(w io.Writer, f Field) error {
 		return err
 	}
 
-	subf := Field{Name: "v", Type: e, Pkg: f.Pkg, Pointer: pointer}
-	switch e.Kind() {
-	default:
-		err = fmt.Errorf("do not yet support arrays of %s yet", e.Kind())
-	case reflect.Struct:
-		err = g.emitDagJsonMarshalStructField(w, subf)
-	case reflect.Uint64:
-		err = g.emitDagJsonMarshalUint64Field(w, subf)
-	case reflect.Uint8:
-		err = g.emitDagJsonMarshalUint8Field(w, subf)
-	case reflect.Int64:
-		err = g.emitDagJsonMarshalInt64Field(w, subf)
-	case reflect.Slice:
-		err = g.emitDagJsonMarshalSliceField(w, subf)
-	case reflect.String:
-		err = g.emitDagJsonMarshalStringField(w, subf)
-	}
-	if err != nil {
+	if err := g.emitDagJsonMarshalField(w, f.elemField("v")); err != nil {
 		return err
 	}
 
@@ -762,15 +1423,47 @@ func (g Gen) emitDagJsonMarshalArrayField(w io.Writer, f Field) error {
 	}`)
 }
 
+// emitDagJsonMarshalField emits the encoder for a value of any supported
+// type. It is used for struct fields as well as slice, array and map elements.
+func (g Gen) emitDagJsonMarshalField(w io.Writer, f Field) error {
+	switch f.GoType().Kind() {
+	case reflect.String:
+		return g.emitDagJsonMarshalStringField(w, f)
+	case reflect.Struct:
+		return g.emitDagJsonMarshalStructField(w, f)
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return g.emitDagJsonMarshalUintField(w, f)
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		return g.emitDagJsonMarshalIntField(w, f)
+	case reflect.Float32, reflect.Float64:
+		return g.emitDagJsonMarshalFloatField(w, f)
+	case reflect.Array:
+		return g.emitDagJsonMarshalArrayField(w, f)
+	case reflect.Slice:
+		return g.emitDagJsonMarshalSliceField(w, f)
+	case reflect.Bool:
+		return g.emitDagJsonMarshalBoolField(w, f)
+	case reflect.Map:
+		return g.emitDagJsonMarshalMapField(w, f)
+	default:
+		return fmt.Errorf("field %q has unsupported kind %q", f.Name, f.GoType().Kind())
+	}
+}
+
 func (g Gen) emitDagJsonMarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err error) {
+	if gti.Unknown != nil {
+		return fmt.Errorf("%s: unknown fields are only supported in map encoding", gti.Name)
+	}
 	if gti.Transparent {
 		err = g.doTemplate(w, gti, `
-		func (t *{{ .Name }}) MarshalDagJSON(w io.Writer) error {
-			jw := jsg.NewDagJsonWriter(w)`)
+		func {{ .Func "MarshalDagJSON" "w io.Writer" }} (err error) {
+			jw := jsg.NewBufferedDagJsonWriter(w)
+			defer jw.Release(w, &err)`)
 	} else {
 		err = g.doTemplate(w, gti, `
-		func (t *{{ .Name }}) MarshalDagJSON(w io.Writer) error {
-			jw := jsg.NewDagJsonWriter(w)
+		func {{ .Func "MarshalDagJSON" "w io.Writer" }} (err error) {
+			jw := jsg.NewBufferedDagJsonWriter(w)
+			defer jw.Release(w, &err)
 			if t == nil {
 				err := jw.WriteNull()
 				return err
@@ -797,49 +1490,12 @@ func (g Gen) emitDagJsonMarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err e
 		} else {
 			f.Name = "t." + f.Name
 		}
-		if _, err := fmt.Fprintf(w, "\n\n\t// %s (%s) (%s)", f.Name, f.Type, f.Type.Kind()); err != nil {
+		if _, err := fmt.Fprintf(w, "\n\n\t// %s (%s) (%s)", f.Name, f.GoType(), f.GoType().Kind()); err != nil {
 			return err
 		}
 
-		switch f.Type.Kind() {
-		case reflect.String:
-			if err := g.emitDagJsonMarshalStringField(w, f); err != nil {
-				return err
-			}
-		case reflect.Struct:
-			if err := g.emitDagJsonMarshalStructField(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint64:
-			if err := g.emitDagJsonMarshalUint64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint8:
-			if err := g.emitDagJsonMarshalUint8Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Int64:
-			if err := g.emitDagJsonMarshalInt64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Array:
-			if err := g.emitDagJsonMarshalArrayField(w, f); err != nil {
-				return err
-			}
-		case reflect.Slice:
-			if err := g.emitDagJsonMarshalSliceField(w, f); err != nil {
-				return err
-			}
-		case reflect.Bool:
-			if err := g.emitDagJsonMarshalBoolField(w, f); err != nil {
-				return err
-			}
-		case reflect.Map:
-			if err := g.emitDagJsonMarshalMapField(w, f); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("field %q of %q has unsupported kind %q", f.Name, gti.Name, f.Type.Kind())
+		if err := g.emitDagJsonMarshalField(w, f); err != nil {
+			return fmt.Errorf("%s: %w", gti.Name, err)
 		}
 	}
 
@@ -863,69 +1519,69 @@ func (g Gen) emitDagJsonUnmarshalStringField(w io.Writer, f Field) error {
 			sval, err := jr.ReadStringOrNull({{ MaxLen 0 "String" }})
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("{{ .Name }}: string too long")
+					return jr.PathError({{ .Path }}, errors.New("string too long"))
 				}
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if sval != nil {
 				{{ .Name }} = (*{{ .TypeName }})(sval)
 			}
 		}`)
 	}
-	if f.Type == nil {
-		f.Type = reflect.TypeOf("")
+	if f.GoType() == nil {
+		f.typ = ReflectType(reflect.TypeOf(""))
 	}
 	return g.doTemplate(w, f, `
 	{
 		sval, err := jr.ReadString({{ MaxLen 0 "String" }})
 		if err != nil {
 			if errors.Is(err, jsg.ErrLimitExceeded) {
-				return fmt.Errorf("{{ .Name }}: string too long")
+				return jr.PathError({{ .Path }}, errors.New("string too long"))
 			}
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}
 		{{ .Name }} = {{ .TypeName }}(sval)
 	}`)
 }
 
 func (g Gen) emitDagJsonUnmarshalStructField(w io.Writer, f Field) error {
-	switch f.Type {
-	case bigIntType:
+	switch {
+	case sameType(f.GoType(), bigIntType):
 		return g.doTemplate(w, f, `
 		{
-			nval, err := jr.ReadNumberAsBigInt(256)
+			nval, err := jr.ReadNumberAsBigInt({{ MaxLen .MaxLen "BigInt" }})
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("{{ .Name }}: number too large")
+					return jr.PathError({{ .Path }}, errors.New("number too large"))
 				}
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			{{ .Name }} = nval
 		}`)
-	case cidType:
+	case sameType(f.GoType(), cidType):
 		return g.doTemplate(w, f, `
 		{
 			{{ if .Pointer }}
 				c, err := jr.ReadCidOrNull()
 				if err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError({{ .Path }}, err)
 				}
 				{{ .Name }} = c
 			{{ else }}
 				c, err := jr.ReadCid()
 				if err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError({{ .Path }}, err)
 				}
 				{{ .Name }} = c
 			{{ end }}
 		}`)
-	case deferredType:
+	case sameType(f.GoType(), deferredType):
 		return g.doTemplate(w, f, `
 		{{ if .Pointer }}
 			{{ .Name }} = new(jsg.Deferred)
 		{{ end }}
 		if err := {{ .Name }}.UnmarshalDagJSON(jr); err != nil {
-			return fmt.Errorf("failed to read deferred field: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}`)
 	default:
 		return g.doTemplate(w, f, `
@@ -933,79 +1589,150 @@ func (g Gen) emitDagJsonUnmarshalStructField(w io.Writer, f Field) error {
 			{
 				null, err := jr.PeekNull()
 				if err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError({{ .Path }}, err)
 				}
 				if null {
 					if err := jr.ReadNull(); err != nil {
-						return fmt.Errorf("{{ .Name }}: %w", err)
+						return jr.PathError({{ .Path }}, err)
 					}
 				} else {
 					{{ .Name }} = new({{ .TypeName }})
 					if err := {{ .Name }}.UnmarshalDagJSON(jr); err != nil {
-						return fmt.Errorf("unmarshaling {{ .Name }} pointer: %w", err)
+						return jr.PathError({{ .Path }}, err)
 					}
 				}
 			}
 		{{ else }}
 			if err := {{ .Name }}.UnmarshalDagJSON(jr); err != nil {
-				return fmt.Errorf("unmarshaling {{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 		{{ end }}`)
 	}
 }
 
-func (g Gen) emitDagJsonUnmarshalInt64Field(w io.Writer, f Field) error {
-	return g.doTemplate(w, f, `
+// intBounds returns the names of the math package constants bounding the
+// given integer kind, or empty strings if values of the kind need no bounds
+// check after being read as a 64 bit integer.
+func intBounds(k reflect.Kind) (min, max string) {
+	switch k {
+	case reflect.Int:
+		return "math.MinInt", "math.MaxInt"
+	case reflect.Int8:
+		return "math.MinInt8", "math.MaxInt8"
+	case reflect.Int16:
+		return "math.MinInt16", "math.MaxInt16"
+	case reflect.Int32:
+		return "math.MinInt32", "math.MaxInt32"
+	case reflect.Uint:
+		return "", "math.MaxUint"
+	case reflect.Uint8:
+		return "", "math.MaxUint8"
+	case reflect.Uint16:
+		return "", "math.MaxUint16"
+	case reflect.Uint32:
+		return "", "math.MaxUint32"
+	default:
+		return "", ""
+	}
+}
+
+// emitDagJsonUnmarshalIntField handles all signed integer kinds. Values are
+// read as int64 and range checked against the target kind.
+func (g Gen) emitDagJsonUnmarshalIntField(w io.Writer, f Field) error {
+	min, max := intBounds(f.GoType().Kind())
+	data := struct {
+		Field
+		Min, Max string
+	}{f, min, max}
+	return g.doTemplate(w, data, `
 	{
 		{{ if .Pointer }}
 			nval, err := jr.ReadNumberAsInt64OrNull()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if nval != nil {
+				{{ if .Max }}
+				if *nval < {{ .Min }} || *nval > {{ .Max }} {
+					return jr.PathError({{ .Path }}, fmt.Errorf("value %d out of range for {{ .GoType.Kind }}", *nval))
+				}
+				{{ end }}
 				typed := {{ .TypeName }}(*nval)
 				{{ .Name }} = &typed
 			}
 		{{ else }}
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
+			{{ if .Max }}
+			if nval < {{ .Min }} || nval > {{ .Max }} {
+				return jr.PathError({{ .Path }}, fmt.Errorf("value %d out of range for {{ .GoType.Kind }}", nval))
+			}
+			{{ end }}
 			{{ .Name }} = {{ .TypeName }}(nval)
 		{{ end }}
 	}`)
 }
 
-func (g Gen) emitDagJsonUnmarshalUint64Field(w io.Writer, f Field) error {
-	return g.doTemplate(w, f, `
+// emitDagJsonUnmarshalUintField handles all unsigned integer kinds. Values are
+// read as uint64 and range checked against the target kind.
+func (g Gen) emitDagJsonUnmarshalUintField(w io.Writer, f Field) error {
+	_, max := intBounds(f.GoType().Kind())
+	data := struct {
+		Field
+		Max string
+	}{f, max}
+	return g.doTemplate(w, data, `
 	{
 		{{ if .Pointer }}
 			nval, err := jr.ReadNumberAsUint64OrNull()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if nval != nil {
+				{{ if .Max }}
+				if *nval > {{ .Max }} {
+					return jr.PathError({{ .Path }}, fmt.Errorf("value %d out of range for {{ .GoType.Kind }}", *nval))
+				}
+				{{ end }}
 				typed := {{ .TypeName }}(*nval)
 				{{ .Name }} = &typed
 			}
 		{{ else }}
 			nval, err := jr.ReadNumberAsUint64()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
+			}
+			{{ if .Max }}
+			if nval > {{ .Max }} {
+				return jr.PathError({{ .Path }}, fmt.Errorf("value %d out of range for {{ .GoType.Kind }}", nval))
 			}
+			{{ end }}
 			{{ .Name }} = {{ .TypeName }}(nval)
 		{{ end }}
 	}`)
 }
 
-func (g Gen) emitDagJsonUnmarshalUint8Field(w io.Writer, f Field) error {
+func (g Gen) emitDagJsonUnmarshalFloatField(w io.Writer, f Field) error {
 	return g.doTemplate(w, f, `
 	{
-		nval, err := jr.ReadNumberAsUint8()
-		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
-		}
-		{{ .Name }} = {{ .TypeName }}(nval)
+		{{ if .Pointer }}
+			nval, err := jr.ReadNumberAsFloat{{ .GoType.Bits }}OrNull()
+			if err != nil {
+				return jr.PathError({{ .Path }}, err)
+			}
+			if nval != nil {
+				typed := {{ .TypeName }}(*nval)
+				{{ .Name }} = &typed
+			}
+		{{ else }}
+			nval, err := jr.ReadNumberAsFloat{{ .GoType.Bits }}()
+			if err != nil {
+				return jr.PathError({{ .Path }}, err)
+			}
+			{{ .Name }} = {{ .TypeName }}(nval)
+		{{ end }}
 	}`)
 }
 
@@ -1015,7 +1742,7 @@ func (g Gen) emitDagJsonUnmarshalBoolField(w io.Writer, f Field) error {
 		{
 			bval, err := jr.ReadBoolOrNull()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if bval != nil {
 				{{ .Name }} = bval
@@ -1023,29 +1750,36 @@ func (g Gen) emitDagJsonUnmarshalBoolField(w io.Writer, f Field) error {
 		}`)
 	} else {
 		return g.doTemplate(w, f, `
-		bval, err := jr.ReadBool()
-		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
-		}
-		{{ .Name }} = bval`)
+		{
+			bval, err := jr.ReadBool()
+			if err != nil {
+				return jr.PathError({{ .Path }}, err)
+			}
+			{{ .Name }} = {{ .TypeName }}(bval)
+		}`)
 	}
 }
 
 func (g Gen) emitDagJsonUnmarshalMapField(w io.Writer, f Field) error {
-	err := g.doTemplate(w, f, `
-	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("{{ .Name }}: %w", err)
+	if f.Pointer {
+		return fmt.Errorf("pointers to maps not supported")
 	}
 
-	{{ .Name }} = {{ .TypeName }}{}
+	err := g.doTemplate(w, f, `
+	{
+		if err := jr.ReadObjectOpen(); err != nil {
+			return jr.PathError({{ .Path }}, err)
+		}
+
+		{{ .Name }} = {{ .TypeName }}{}
 
-	close, err := jr.PeekObjectClose()
+		close, err := jr.PeekObjectClose()
 		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}
 		if close {
 			if err := jr.ReadObjectClose(); err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 		} else {
 			for i, l := 0, {{ MaxLen .MaxLen "Array" }}; i < l; i++ {`)
@@ -1053,103 +1787,115 @@ func (g Gen) emitDagJsonUnmarshalMapField(w io.Writer, f Field) error {
 		return err
 	}
 
-	switch f.Type.Key().Kind() {
-	case reflect.String:
-		if err := g.doTemplate(w, f, `
-		var k string`); err != nil {
+	mf, err := newMapKeyField(f)
+	if err != nil {
+		return err
+	}
+	if mf.KeyKind == "string" {
+		keyf := Field{Name: f.Var("key"), typ: f.GoType().Key(), Pkg: f.Pkg, path: f.path, scope: f.scope}
+		if err := g.doTemplate(w, keyf, `
+		var {{ .Name }} {{ .TypeName }}`); err != nil {
 			return err
 		}
-		if err := g.emitDagJsonUnmarshalStringField(w, Field{Name: "k"}); err != nil {
+		if err := g.emitDagJsonUnmarshalStringField(w, keyf); err != nil {
+			return err
+		}
+	} else {
+		if err := g.doTemplate(w, mf, `
+		var {{ .Var "key" }} {{ .KeyTypeName }}
+		{{ .Var "ks" }}, err := jr.ReadString({{ MaxLen 0 "String" }})
+		if err != nil {
+			if errors.Is(err, jsg.ErrLimitExceeded) {
+				return jr.PathError({{ .Path }}, errors.New("map key too long"))
+			}
+			return jr.PathError({{ .Path }}, err)
+		}
+		{
+			{{ if eq .KeyKind "text" }}
+				if err := {{ .Var "key" }}.UnmarshalText([]byte({{ .Var "ks" }})); err != nil {
+					return jr.PathError({{ .Path }}, fmt.Errorf("map key %q: %w", {{ .Var "ks" }}, err))
+				}
+			{{ else }}
+				{{ if eq .KeyKind "int" }}
+					n, err := strconv.ParseInt({{ .Var "ks" }}, 10, {{ .KeyBitSize }})
+				{{ else }}
+					n, err := strconv.ParseUint({{ .Var "ks" }}, 10, {{ .KeyBitSize }})
+				{{ end }}
+				if err != nil {
+					return jr.PathError({{ .Path }}, fmt.Errorf("map key %q: %w", {{ .Var "ks" }}, err))
+				}
+				{{ if eq .KeyKind "int" }}
+					if strconv.FormatInt(n, 10) != {{ .Var "ks" }} {
+				{{ else }}
+					if strconv.FormatUint(n, 10) != {{ .Var "ks" }} {
+				{{ end }}
+					return jr.PathError({{ .Path }}, fmt.Errorf("non-canonical integer map key %q", {{ .Var "ks" }}))
+				}
+				{{ .Var "key" }} = {{ .KeyTypeName }}(n)
+			{{ end }}
+		}`); err != nil {
 			return err
 		}
-	default:
-		return fmt.Errorf("maps with non-string keys are not supported")
 	}
 
 	if err := g.doTemplate(w, f, `
 		if err := jr.ReadObjectColon(); err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}`); err != nil {
 		return err
 	}
 
-	var pointer bool
-	t := f.Type.Elem()
-	switch t.Kind() {
-	case reflect.String:
-		if err := g.doTemplate(w, f, `
-		var v string`); err != nil {
-			return err
-		}
-		if err := g.emitDagJsonUnmarshalStringField(w, Field{Name: "v"}); err != nil {
-			return err
-		}
-		if err := g.doTemplate(w, f, `
-		{{ .Name }}[k] = v`); err != nil {
-			return err
-		}
-	case reflect.Ptr:
-		if t.Elem().Kind() != reflect.Struct {
-			return fmt.Errorf("unsupported map elem ptr type: %s", t)
-		}
-
-		pointer = true
-		fallthrough
-	case reflect.Struct:
-		subf := Field{Name: "v", Pointer: pointer, Type: t, Pkg: f.Pkg}
-		if err := g.doTemplate(w, subf, `
-		var v {{ .TypeName }}`); err != nil {
-			return err
-		}
-
-		if pointer {
-			subf.Type = subf.Type.Elem()
-		}
-		if err := g.emitDagJsonUnmarshalStructField(w, subf); err != nil {
-			return err
-		}
-		if err := g.doTemplate(w, f, `
-		{{ .Name }}[k] = v`); err != nil {
-			return err
-		}
-	default:
-		return fmt.Errorf("currently only support maps of structs")
+	// Map value
+	vf := f.elemField(f.Var("v"))
+	if mf.KeyKind == "string" {
+		vf.path = f.subPath("jsg.KeyPath(string(" + f.Var("key") + "))")
+	} else {
+		vf.path = f.subPath("jsg.KeyPath(" + f.Var("ks") + ")")
+	}
+	if err := g.doTemplate(w, f, `
+		var {{ .Var "v" }} {{ .ElemName }}`); err != nil {
+		return err
+	}
+	if err := g.emitDagJsonUnmarshalField(w, vf); err != nil {
+		return err
 	}
 
 	return g.doTemplate(w, f, `
-			close, err := jr.ReadObjectCloseOrComma()
-			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
-			}
-			if close {
-				break
+				{{ .Name }}[{{ .Var "key" }}] = {{ .Var "v" }}
+
+				close, err := jr.ReadObjectCloseOrComma()
+				if err != nil {
+					return jr.PathError({{ .Path }}, err)
+				}
+				if close {
+					break
+				}
+				if i == l-1 {
+					return jr.PathError({{ .Path }}, errors.New("map too large"))
+				}
 			}
 		}
 	}`)
 }
 
 func (g Gen) emitDagJsonUnmarshalSliceField(w io.Writer, f Field) error {
+	if f.Pointer {
+		return fmt.Errorf("pointers to slices not supported")
+	}
 	if f.IterLabel == "" {
 		f.IterLabel = "i"
 	}
 
-	e := f.Type.Elem()
-	var pointer bool
-	if e.Kind() == reflect.Ptr {
-		pointer = true
-		e = e.Elem()
-	}
-
-	if e.Kind() == reflect.Uint8 {
+	if f.GoType().Elem().Kind() == reflect.Uint8 {
 		return g.doTemplate(w, f, `
 		{{ if .PreserveNil }}
 		{
 			bval, err := jr.ReadBytesOrNull({{ MaxLen .MaxLen "Bytes" }})
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("{{ .Name }}: byte array too large")
+					return jr.PathError({{ .Path }}, errors.New("byte array too large"))
 				}
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if bval != nil {
 				{{ .Name }} = {{ .TypeName }}(*bval)
@@ -1160,9 +1906,9 @@ func (g Gen) emitDagJsonUnmarshalSliceField(w io.Writer, f Field) error {
 			bval, err := jr.ReadBytes({{ MaxLen .MaxLen "Bytes" }})
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("{{ .Name }}: byte array too large")
+					return jr.PathError({{ .Path }}, errors.New("byte array too large"))
 				}
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if len(bval) > 0 {
 				{{ .Name }} = {{ .TypeName }}(bval)
@@ -1176,21 +1922,21 @@ func (g Gen) emitDagJsonUnmarshalSliceField(w io.Writer, f Field) error {
 		{{ if .PreserveNil }}
 			open, err := jr.ReadArrayOpenOrNull()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			if open {
 		{{ else }}
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}
 		{{ end }}
 		close, err := jr.PeekArrayClose()
 		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}
 		if close {
 			if err := jr.ReadArrayClose(); err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			{{ if .PreserveNil }}
 				{{ .Name }} = {{ .TypeName }}{}
@@ -1202,98 +1948,33 @@ func (g Gen) emitDagJsonUnmarshalSliceField(w io.Writer, f Field) error {
 
 	err = g.doTemplate(w, f, `
 	for {{ .IterLabel }} := 0; {{ .IterLabel }} < {{ MaxLen .MaxLen "Array" }}; {{ .IterLabel }}++ {
-		item := make({{ .TypeName }}, 1)`)
+		{{ .Var "item" }} := make({{ .TypeName }}, 1)`)
 	if err != nil {
 		return err
 	}
 
-	switch e.Kind() {
-	case reflect.Struct:
-		subf := Field{
-			Type:    e,
-			Pkg:     f.Pkg,
-			Pointer: pointer,
-			Name:    "item[0]",
+	ef := f.elemField(f.Var("item") + "[0]")
+	ef.path = f.subPath("jsg.IndexPath(" + f.IterLabel + ")")
+	if err := g.emitDagJsonUnmarshalField(w, ef); err != nil {
+		return err
+	}
+
+	if err := g.doTemplate(w, f, `
+				{{ .Name }} = append({{ .Name }}, {{ .Var "item" }}[0])
+
+				close, err := jr.ReadArrayCloseOrComma()
+				if err != nil {
+					return jr.PathError({{ .Path }}, err)
+				}
+				if close {
+					break
+				}
+				if {{ .IterLabel }} == {{ MaxLen .MaxLen "Array" }} - 1 {
+					return jr.PathError({{ .Path }}, errors.New("slice too large"))
+				}
+			}
 		}
-		err := g.emitDagJsonUnmarshalStructField(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Uint64:
-		subf := Field{
-			Type: e,
-			Pkg:  f.Pkg,
-			Name: "item[0]",
-		}
-		err := g.emitDagJsonUnmarshalUint64Field(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Int64:
-		subf := Field{
-			Type: e,
-			Pkg:  f.Pkg,
-			Name: "item[0]",
-		}
-		err := g.emitDagJsonUnmarshalInt64Field(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Array:
-		nextIter := string([]byte{f.IterLabel[0] + 1})
-		subf := Field{
-			Name:      "item[0]",
-			Type:      e,
-			IterLabel: nextIter,
-			Pkg:       f.Pkg,
-		}
-		if err := g.emitDagJsonUnmarshalArrayField(w, subf); err != nil {
-			return err
-		}
-	case reflect.Slice:
-		nextIter := string([]byte{f.IterLabel[0] + 1})
-		subf := Field{
-			Name:      "item[0]",
-			Type:      e,
-			IterLabel: nextIter,
-			Pkg:       f.Pkg,
-		}
-		if err := g.emitDagJsonUnmarshalSliceField(w, subf); err != nil {
-			return err
-		}
-
-	case reflect.String:
-		subf := Field{
-			Type:    e,
-			Pkg:     f.Pkg,
-			Pointer: pointer,
-			Name:    "item[0]",
-		}
-		err := g.emitDagJsonUnmarshalStringField(w, subf)
-		if err != nil {
-			return err
-		}
-
-	default:
-		return fmt.Errorf("do not yet support slices of %s yet", e.Elem())
-	}
-
-	if err := g.doTemplate(w, f, `
-				{{ .Name }} = append({{ .Name }}, item[0])
-
-				close, err := jr.ReadArrayCloseOrComma()
-				if err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
-				}
-				if close {
-					break
-				}
-				if {{ .IterLabel }} == {{ MaxLen .MaxLen "Array" }} - 1 {
-					return fmt.Errorf("{{ .Name }}: slice too large")
-				}
-			}
-		}
-		{{ if .PreserveNil }}
+		{{ if .PreserveNil }}
 		}
 		{{ end }}
 	}`); err != nil {
@@ -1304,23 +1985,19 @@ func (g Gen) emitDagJsonUnmarshalSliceField(w io.Writer, f Field) error {
 }
 
 func (g Gen) emitDagJsonUnmarshalArrayField(w io.Writer, f Field) error {
+	if f.Pointer {
+		return fmt.Errorf("pointers to arrays not supported")
+	}
 	if f.IterLabel == "" {
 		f.IterLabel = "i"
 	}
 
-	e := f.Type.Elem()
-	var pointer bool
-	if e.Kind() == reflect.Ptr {
-		pointer = true
-		e = e.Elem()
-	}
-
-	if e.Kind() == reflect.Uint8 {
+	if f.GoType().Elem().Kind() == reflect.Uint8 {
 		return g.doTemplate(w, f, `
 		{
 			bval, err := jr.ReadBytes({{ MaxLen .MaxLen "Bytes" }})
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError({{ .Path }}, err)
 			}
 			{{ .Name }} = {{ .TypeName }}(bval)
 		}`)
@@ -1328,7 +2005,7 @@ func (g Gen) emitDagJsonUnmarshalArrayField(w io.Writer, f Field) error {
 
 	err := g.doTemplate(w, f, `
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("{{ .Name }}: %w", err)
+		return jr.PathError({{ .Path }}, err)
 	}
 
 	{{ .Name }} = {{ .TypeName }}{}
@@ -1337,86 +2014,22 @@ func (g Gen) emitDagJsonUnmarshalArrayField(w io.Writer, f Field) error {
 		return err
 	}
 
-	switch e.Kind() {
-	case reflect.Struct:
-		subf := Field{
-			Type:    e,
-			Pkg:     f.Pkg,
-			Pointer: pointer,
-			Name:    f.Name + "[" + f.IterLabel + "]",
-		}
-		err := g.emitDagJsonUnmarshalStructField(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Uint64:
-		subf := Field{
-			Type: e,
-			Pkg:  f.Pkg,
-			Name: f.Name + "[" + f.IterLabel + "]",
-		}
-		err := g.emitDagJsonUnmarshalUint64Field(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Int64:
-		subf := Field{
-			Type: e,
-			Pkg:  f.Pkg,
-			Name: f.Name + "[" + f.IterLabel + "]",
-		}
-		err := g.emitDagJsonUnmarshalInt64Field(w, subf)
-		if err != nil {
-			return err
-		}
-	case reflect.Array:
-		nextIter := string([]byte{f.IterLabel[0] + 1})
-		subf := Field{
-			Name:      fmt.Sprintf("%s[%s]", f.Name, f.IterLabel),
-			Type:      e,
-			IterLabel: nextIter,
-			Pkg:       f.Pkg,
-		}
-		if err := g.emitDagJsonUnmarshalArrayField(w, subf); err != nil {
-			return err
-		}
-	case reflect.Slice:
-		nextIter := string([]byte{f.IterLabel[0] + 1})
-		subf := Field{
-			Name:      fmt.Sprintf("%s[%s]", f.Name, f.IterLabel),
-			Type:      e,
-			IterLabel: nextIter,
-			Pkg:       f.Pkg,
-		}
-		if err := g.emitDagJsonUnmarshalSliceField(w, subf); err != nil {
-			return err
-		}
-
-	case reflect.String:
-		subf := Field{
-			Type: e,
-			Pkg:  f.Pkg,
-			Name: f.Name + "[" + f.IterLabel + "]",
-		}
-		err := g.emitDagJsonUnmarshalStringField(w, subf)
-		if err != nil {
-			return err
-		}
-
-	default:
-		return fmt.Errorf("do not yet support slices of %s yet", e.Elem())
+	ef := f.elemField(f.Name + "[" + f.IterLabel + "]")
+	ef.path = f.subPath("jsg.IndexPath(" + f.IterLabel + ")")
+	if err := g.emitDagJsonUnmarshalField(w, ef); err != nil {
+		return err
 	}
 
 	if err := g.doTemplate(w, f, `
 		close, err := jr.ReadArrayCloseOrComma()
 		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError({{ .Path }}, err)
 		}
 		if close {
 			break
 		}
 		if {{ .IterLabel }} == {{ MaxLen .MaxLen "Array" }} - 1 {
-			return fmt.Errorf("{{ .Name }}: array too large")
+			return jr.PathError({{ .Path }}, errors.New("array too large"))
 		}
 	}`); err != nil {
 		return err
@@ -1425,34 +2038,68 @@ func (g Gen) emitDagJsonUnmarshalArrayField(w io.Writer, f Field) error {
 	return nil
 }
 
+// emitDagJsonUnmarshalField emits the decoder for a value of any supported
+// type. It is used for struct fields as well as slice, array and map elements.
+func (g Gen) emitDagJsonUnmarshalField(w io.Writer, f Field) error {
+	switch f.GoType().Kind() {
+	case reflect.String:
+		return g.emitDagJsonUnmarshalStringField(w, f)
+	case reflect.Struct:
+		return g.emitDagJsonUnmarshalStructField(w, f)
+	case reflect.Uint64, reflect.Uint32, reflect.Uint16, reflect.Uint8, reflect.Uint:
+		return g.emitDagJsonUnmarshalUintField(w, f)
+	case reflect.Int64, reflect.Int32, reflect.Int16, reflect.Int8, reflect.Int:
+		return g.emitDagJsonUnmarshalIntField(w, f)
+	case reflect.Float32, reflect.Float64:
+		return g.emitDagJsonUnmarshalFloatField(w, f)
+	case reflect.Array:
+		return g.emitDagJsonUnmarshalArrayField(w, f)
+	case reflect.Slice:
+		return g.emitDagJsonUnmarshalSliceField(w, f)
+	case reflect.Bool:
+		return g.emitDagJsonUnmarshalBoolField(w, f)
+	case reflect.Map:
+		return g.emitDagJsonUnmarshalMapField(w, f)
+	default:
+		return fmt.Errorf("field %q has unsupported kind %q", f.Name, f.GoType().Kind())
+	}
+}
+
 func (g Gen) emitDagJsonUnmarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err error) {
 	if gti.Transparent {
 		err = g.doTemplate(w, gti, `
-		func (t *{{ .Name }}) UnmarshalDagJSON(r io.Reader) (err error) {
+		func {{ .Func "UnmarshalDagJSON" "r io.Reader" }} (err error) {
 			*t = {{ .Name }}{}
 
-			jr := jsg.NewDagJsonReader(r)`)
+			jr := jsg.NewDagJsonReader(r)
+			defer jr.Release(r)`)
 	} else {
 		err = g.doTemplate(w, gti, `
-		func (t *{{ .Name }}) UnmarshalDagJSON(r io.Reader) (err error) {
+		func {{ .Func "UnmarshalDagJSON" "r io.Reader" }} (err error) {
 			*t = {{ .Name }}{}
+			{{ range .Fields }}
+				{{ if .Default }}
+					t.{{ .Name }} = {{ .Default }}
+				{{ end }}
+			{{ end }}
 
 			jr := jsg.NewDagJsonReader(r)
+			defer jr.Release(r)
 			defer func() {
 				if err == io.EOF {
 					err = io.ErrUnexpectedEOF
 				}
 			}()
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError("", err)
 			}
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError("", err)
 				}
 			} else {`)
 	}
@@ -1464,50 +2111,14 @@ func (g Gen) emitDagJsonUnmarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err
 		if f.Name == FieldNameSelf {
 			f.Name = "(*t)" // self
 		} else {
+			f.path = strconv.Quote("." + f.Name)
 			f.Name = "t." + f.Name
 		}
 
-		fmt.Fprintf(w, "\n\n\t// %s (%s) (%s)\n", f.Name, f.Type, f.Type.Kind())
+		fmt.Fprintf(w, "\n\n\t// %s (%s) (%s)\n", f.Name, f.GoType(), f.GoType().Kind())
 
-		switch f.Type.Kind() {
-		case reflect.String:
-			if err := g.emitDagJsonUnmarshalStringField(w, f); err != nil {
-				return err
-			}
-		case reflect.Struct:
-			if err := g.emitDagJsonUnmarshalStructField(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint64:
-			if err := g.emitDagJsonUnmarshalUint64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint8:
-			if err := g.emitDagJsonUnmarshalUint8Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Int64:
-			if err := g.emitDagJsonUnmarshalInt64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Array:
-			if err := g.emitDagJsonUnmarshalArrayField(w, f); err != nil {
-				return err
-			}
-		case reflect.Slice:
-			if err := g.emitDagJsonUnmarshalSliceField(w, f); err != nil {
-				return err
-			}
-		case reflect.Bool:
-			if err := g.emitDagJsonUnmarshalBoolField(w, f); err != nil {
-				return err
-			}
-		case reflect.Map:
-			if err := g.emitDagJsonUnmarshalMapField(w, f); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("field %q of %q has unsupported kind %q", f.Name, gti.Name, f.Type.Kind())
+		if err := g.emitDagJsonUnmarshalField(w, f); err != nil {
+			return fmt.Errorf("%s: %w", gti.Name, err)
 		}
 		if !gti.Transparent {
 			if fieldIndex < gti.MandatoryFieldCount-1 {
@@ -1515,13 +2126,13 @@ func (g Gen) emitDagJsonUnmarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err
 				{
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("{{ .Name }}: %w", err)
+						return jr.PathError("", err)
 					}`); err != nil {
 					return err
 				}
 				if _, err := fmt.Fprintf(w, `
 					if close {
-						return fmt.Errorf("json input has too few fields %d < %d")
+						return jr.PathError("", errors.New("json input has too few fields %d < %d"))
 					}
 				}`, fieldIndex+1, gti.MandatoryFieldCount); err != nil {
 					return err
@@ -1529,7 +2140,7 @@ func (g Gen) emitDagJsonUnmarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err
 			} else if fieldIndex == len(gti.Fields)-1 {
 				if err := g.doTemplate(w, gti, `
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError("", err)
 				}`); err != nil {
 					return err
 				}
@@ -1538,7 +2149,7 @@ func (g Gen) emitDagJsonUnmarshalStructTuple(w io.Writer, gti *GenTypeInfo) (err
 				{
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("{{ .Name }}: %w", err)
+						return jr.PathError("", err)
 					}
 					if close {
 						return nil
@@ -1568,6 +2179,23 @@ func GenTupleEncodersForType(gti *GenTypeInfo, w io.Writer) error {
 
 // Generates 'tuple representation' dag json encoders for the given type
 func (g Gen) GenTupleEncodersForType(gti *GenTypeInfo, w io.Writer) error {
+	gti, err := g.inScope(gti)
+	if err != nil {
+		return err
+	}
+	if gti.Representation == "map" {
+		return fmt.Errorf("%s is declared map encoded", gti.Name)
+	}
+	if gti.Union != "" {
+		return g.GenUnionEncodersForType(gti, w)
+	}
+	// Every field of a tuple is present unless it is optional.
+	for _, f := range gti.Fields {
+		if f.Required {
+			return fmt.Errorf("%s.%s: required fields are only supported on map encoded structs", gti.Name, f.Name)
+		}
+	}
+
 	if err := g.emitDagJsonMarshalStructTuple(w, gti); err != nil {
 		return err
 	}
@@ -1576,22 +2204,113 @@ func (g Gen) GenTupleEncodersForType(gti *GenTypeInfo, w io.Writer) error {
 		return err
 	}
 
+	if err := g.emitScanLinks(w, gti); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GenGenericMethods generates the codec methods of the generic types that the
+// given types instantiate, which call the codec functions of the instantiation
+// they are called on. Every instantiation of a generic type must be generated
+// into the same file.
+func (g Gen) GenGenericMethods(w io.Writer, typeInfos []*GenTypeInfo) error {
+	var generics []string
+	instances := make(map[string][]*GenTypeInfo)
+	for _, gti := range typeInfos {
+		if gti.Generic == "" {
+			continue
+		}
+		gti, err := g.inScope(gti)
+		if err != nil {
+			return err
+		}
+		if _, ok := instances[gti.Generic]; !ok {
+			generics = append(generics, gti.Generic)
+		}
+		instances[gti.Generic] = append(instances[gti.Generic], gti)
+	}
+
+	for _, generic := range generics {
+		recv := generic + "[_" + strings.Repeat(", _", instances[generic][0].TypeArgs-1) + "]"
+		for _, m := range []struct{ name, params, results, arg string }{
+			{"MarshalDagJSON", "w io.Writer", "error", "w"},
+			{"UnmarshalDagJSON", "r io.Reader", "error", "r"},
+			{"ScanDagJSONLinks", "cb func(cid.Cid)", "error", "cb"},
+		} {
+			if _, err := fmt.Fprintf(w, "\nfunc (t *%s) %s(%s) %s {\n\tswitch t := any(t).(type) {", recv, m.name, m.params, m.results); err != nil {
+				return err
+			}
+			for _, gti := range instances[generic] {
+				if _, err := fmt.Fprintf(w, "\n\tcase *%s:\n\t\treturn %s(t, %s)", gti.Name, instanceFuncName(gti.Name, m.name), m.arg); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintf(w, "\n\t}\n\treturn fmt.Errorf(\"no dag-json codec was generated for %%T\", t)\n}\n"); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
-func emptyValForField(f Field) (string, error) {
+// nonEmptyCond returns a condition that is true when the value of f, named by
+// f.Name, is not empty: not its default, nil, zero, false, an empty map,
+// cid.Undef or a zero array, or a struct whose IsZero method returns false.
+func nonEmptyCond(f Field) (string, error) {
+	if f.Default != "" {
+		return f.Name + " != " + f.Default, nil
+	}
 	if f.Pointer {
-		return "nil", nil
-	} else {
-		switch f.Type.Kind() {
-		case reflect.String:
-			return "\"\"", nil
-		case reflect.Slice:
-			return "nil", nil
-		default:
-			return "", fmt.Errorf("omit empty not supported for %s", f.Type.Kind())
+		return f.Name + " != nil", nil
+	}
+	switch f.GoType().Kind() {
+	case reflect.String:
+		return f.Name + ` != ""`, nil
+	case reflect.Slice:
+		return f.Name + " != nil", nil
+	case reflect.Bool:
+		return f.Name, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return f.Name + " != 0", nil
+	case reflect.Map:
+		return "len(" + f.Name + ") != 0", nil
+	case reflect.Array:
+		if !isComparable(f.GoType()) {
+			return "", fmt.Errorf("omit empty not supported for arrays of %s", f.GoType().Elem())
+		}
+		return f.Name + " != (" + f.TypeName() + "{})", nil
+	case reflect.Struct:
+		if sameType(f.GoType(), cidType) {
+			return f.Name + ".Defined()", nil
+		}
+		if f.GoType().hasIsZero() {
+			return "!" + f.Name + ".IsZero()", nil
+		}
+		return "", fmt.Errorf("omit empty not supported for %s, which has no IsZero() bool method", f.GoType())
+	default:
+		return "", fmt.Errorf("omit empty not supported for %s", f.GoType().Kind())
+	}
+}
+
+// isComparable reports whether values of t can be compared with ==.
+func isComparable(t Type) bool {
+	switch t.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Func:
+		return false
+	case reflect.Array:
+		return isComparable(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isComparable(t.Field(i).Type) {
+				return false
+			}
 		}
 	}
+	return true
 }
 
 func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
@@ -1600,8 +2319,9 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 	}
 
 	err := g.doTemplate(w, gti, `
-	func (t *{{ .Name }}) MarshalDagJSON(w io.Writer) error {
-		jw := jsg.NewDagJsonWriter(w)
+	func {{ .Func "MarshalDagJSON" "w io.Writer" }} (err error) {
+		jw := jsg.NewBufferedDagJsonWriter(w)
+		defer jw.Release(w, &err)
 		if t == nil {
 			err := jw.WriteNull()
 			return err
@@ -1621,18 +2341,43 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 		return bytes.Compare([]byte(a.MapKey), []byte(b.MapKey))
 	})
 
-	if len(gti.Fields) > 1 {
+	// Unknown fields are written in among the known ones to keep the keys
+	// sorted.
+	unknown := gti.Unknown != nil
+	if unknown {
+		if _, err := fmt.Fprintf(w, "\nunknown := jsg.NewUnknownFields(t.%s)", gti.Unknown.Name); err != nil {
+			return err
+		}
+	}
+	counted := len(gti.Fields) > 1 || unknown
+	if counted {
 		if _, err := fmt.Fprintf(w, "\nwritten := 0"); err != nil {
 			return err
 		}
 	}
 
 	for i, f := range gti.Fields {
-		if i > 0 {
+		var nonEmpty string
+		if f.OmitEmpty {
+			nf := f
+			nf.Name = "t." + f.Name
+			var err error
+			if nonEmpty, err = nf.NonEmpty(); err != nil {
+				return fmt.Errorf("%s.%s: %w", gti.Name, f.Name, err)
+			}
+		}
+		if unknown {
+			if _, err := fmt.Fprintf(w, `
+			if err := unknown.WriteBefore(jw, %q, &written); err != nil {
+				return fmt.Errorf("t.%s: %%w", err)
+			}`, f.MapKey, gti.Unknown.Name); err != nil {
+				return err
+			}
+		}
+		if i > 0 || unknown {
 			if f.OmitEmpty {
 				// write the comma if the current field is omitempty and not empty
-				if err := g.doTemplate(w, f, `
-				if t.{{ .Name }} != {{ .EmptyVal }} {`); err != nil {
+				if _, err := fmt.Fprintf(w, "\nif %s {", nonEmpty); err != nil {
 					return err
 				}
 			}
@@ -1652,10 +2397,10 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 			}
 		}
 
-		fmt.Fprintf(w, "\n\n\t// t.%s (%s) (%s)", f.Name, f.Type, f.Type.Kind())
+		fmt.Fprintf(w, "\n\n\t// t.%s (%s) (%s)", f.Name, f.GoType(), f.GoType().Kind())
 
 		if f.OmitEmpty {
-			if err := g.doTemplate(w, f, "\nif t.{{ .Name }} != {{ .EmptyVal }} {"); err != nil {
+			if _, err := fmt.Fprintf(w, "\nif %s {", nonEmpty); err != nil {
 				return err
 			}
 		}
@@ -1674,48 +2419,11 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 		}
 
 		f.Name = "t." + f.Name
-		switch f.Type.Kind() {
-		case reflect.String:
-			if err := g.emitDagJsonMarshalStringField(w, f); err != nil {
-				return err
-			}
-		case reflect.Struct:
-			if err := g.emitDagJsonMarshalStructField(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint64:
-			if err := g.emitDagJsonMarshalUint64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Int64:
-			if err := g.emitDagJsonMarshalInt64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint8:
-			if err := g.emitDagJsonMarshalUint8Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Array:
-			if err := g.emitDagJsonMarshalArrayField(w, f); err != nil {
-				return err
-			}
-		case reflect.Slice:
-			if err := g.emitDagJsonMarshalSliceField(w, f); err != nil {
-				return err
-			}
-		case reflect.Bool:
-			if err := g.emitDagJsonMarshalBoolField(w, f); err != nil {
-				return err
-			}
-		case reflect.Map:
-			if err := g.emitDagJsonMarshalMapField(w, f); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("field %q of %q has unsupported kind %q", f.Name, gti.Name, f.Type.Kind())
+		if err := g.emitDagJsonMarshalField(w, f); err != nil {
+			return fmt.Errorf("%s: %w", gti.Name, err)
 		}
 
-		if len(gti.Fields) > 1 {
+		if counted {
 			if err := g.doTemplate(w, f, `
 			written++`); err != nil {
 				return err
@@ -1729,6 +2437,15 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 		}
 	}
 
+	if unknown {
+		if _, err := fmt.Fprintf(w, `
+		if err := unknown.WriteRest(jw, &written); err != nil {
+			return fmt.Errorf("t.%s: %%w", err)
+		}`, gti.Unknown.Name); err != nil {
+			return err
+		}
+	}
+
 	_, err = fmt.Fprintf(w, `
 			if err := jw.WriteObjectClose(); err != nil {
 				return err
@@ -1739,38 +2456,53 @@ func (g Gen) emitDagJsonMarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
 }
 
 func (g Gen) emitDagJsonUnmarshalStructMap(w io.Writer, gti *GenTypeInfo) error {
+	if g.StrictFields && !gti.StrictFields {
+		strict := *gti
+		strict.StrictFields = true
+		gti = &strict
+	}
+
 	err := g.doTemplate(w, gti, `
-	func (t *{{ .Name }}) UnmarshalDagJSON(r io.Reader) (err error) {
+	func {{ .Func "UnmarshalDagJSON" "r io.Reader" }} (err error) {
 		*t = {{ .Name }}{}
+		{{ range .Fields }}
+				{{ if .Default }}
+					t.{{ .Name }} = {{ .Default }}
+				{{ end }}
+			{{ end }}
 
 		jr := jsg.NewDagJsonReader(r)
+		defer jr.Release(r)
 		defer func() {
 			if err == io.EOF {
 				err = io.ErrUnexpectedEOF
 			}
 		}()
 		if err := jr.ReadObjectOpen(); err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError("", err)
 		}
+		{{ if and .Fields (or .StrictFields .RequiredFields) }}
+			var seen [{{ len .Fields }}]bool
+		{{ end }}
 		close, err := jr.PeekObjectClose()
 		if err != nil {
-			return fmt.Errorf("{{ .Name }}: %w", err)
+			return jr.PathError("", err)
 		}
 		if close {
 			if err := jr.ReadObjectClose(); err != nil {
-				return fmt.Errorf("{{ .Name }}: %w", err)
+				return jr.PathError("", err)
 			}
 		} else {
 			for i := uint64(0); i < {{ MaxLen 0 "Array" }}; i++ {
 				name, err := jr.ReadString({{ MaxLen 0 "String" }})
 				if err != nil {
 					if errors.Is(err, jsg.ErrLimitExceeded) {
-						return fmt.Errorf("{{ .Name }}: string too large")
+						return jr.PathError("", errors.New("string too large"))
 					}
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError("", err)
 				}
 				if err := jr.ReadObjectColon(); err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError("", err)
 				}`)
 	if err != nil {
 		return err
@@ -1782,79 +2514,89 @@ func (g Gen) emitDagJsonUnmarshalStructMap(w io.Writer, gti *GenTypeInfo) error
 		return err
 	}
 
-	for _, f := range gti.Fields {
-		fmt.Fprintf(w, "\n\n// t.%s (%s) (%s)", f.Name, f.Type, f.Type.Kind())
+	for i, f := range gti.Fields {
+		fmt.Fprintf(w, "\n\n// t.%s (%s) (%s)", f.Name, f.GoType(), f.GoType().Kind())
 
 		err := g.doTemplate(w, f, `
 		case "{{ .MapKey }}":`)
 		if err != nil {
 			return err
 		}
-
-		f.Name = "t." + f.Name
-
-		switch f.Type.Kind() {
-		case reflect.String:
-			if err := g.emitDagJsonUnmarshalStringField(w, f); err != nil {
-				return err
-			}
-		case reflect.Struct:
-			if err := g.emitDagJsonUnmarshalStructField(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint64:
-			if err := g.emitDagJsonUnmarshalUint64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Int64:
-			if err := g.emitDagJsonUnmarshalInt64Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Uint8:
-			if err := g.emitDagJsonUnmarshalUint8Field(w, f); err != nil {
-				return err
-			}
-		case reflect.Array:
-			if err := g.emitDagJsonUnmarshalArrayField(w, f); err != nil {
-				return err
-			}
-		case reflect.Slice:
-			if err := g.emitDagJsonUnmarshalSliceField(w, f); err != nil {
-				return err
-			}
-		case reflect.Bool:
-			if err := g.emitDagJsonUnmarshalBoolField(w, f); err != nil {
+		if gti.StrictFields {
+			if _, err := fmt.Fprintf(w, `
+			if seen[%d] {
+				return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+			}`, i); err != nil {
 				return err
 			}
-		case reflect.Map:
-			if err := g.emitDagJsonUnmarshalMapField(w, f); err != nil {
+		}
+		if gti.StrictFields || f.Required {
+			if _, err := fmt.Fprintf(w, `
+			seen[%d] = true`, i); err != nil {
 				return err
 			}
-		default:
-			return fmt.Errorf("field %q of %q has unsupported kind %q", f.Name, gti.Name, f.Type.Kind())
+		}
+
+		f.path = strconv.Quote("." + f.Name)
+		f.Name = "t." + f.Name
+
+		if err := g.emitDagJsonUnmarshalField(w, f); err != nil {
+			return fmt.Errorf("%s: %w", gti.Name, err)
 		}
 	}
 
 	return g.doTemplate(w, gti, `
 				default:
-					// Field doesn't exist on this type, so ignore it
-					if err := jr.DiscardType(); err != nil {
-						return fmt.Errorf("{{ .Name }}: ignoring field %s: %w", name, err)
-					}
+					{{ if .Unknown }}
+						// Field doesn't exist on this type, so keep it as is
+						{{ if .StrictFields }}
+							if _, ok := t.{{ .Unknown.Name }}[name]; ok {
+								return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+							}
+						{{ end }}
+						var v jsg.Deferred
+						if err := v.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(jsg.KeyPath(name), err)
+						}
+						if t.{{ .Unknown.Name }} == nil {
+							t.{{ .Unknown.Name }} = make(map[string]jsg.Deferred)
+						}
+						t.{{ .Unknown.Name }}[name] = v
+					{{ else if .StrictFields }}
+						return jr.PathError(jsg.KeyPath(name), errors.New("unknown field"))
+					{{ else }}
+						// Field doesn't exist on this type, so ignore it
+						if err := jr.DiscardType(); err != nil {
+							return jr.PathError(jsg.KeyPath(name), err)
+						}
+					{{ end }}
 				}
 
 				close, err := jr.ReadObjectCloseOrComma()
 				if err != nil {
-					return fmt.Errorf("{{ .Name }}: %w", err)
+					return jr.PathError("", err)
 				}
 				if close {
 					break
 				}
 				if i == {{ MaxLen 0 "Array" }} - 1 {
-					return fmt.Errorf("{{ .Name }}: map too large")
+					return jr.PathError("", errors.New("map too large"))
 				}
 			}
 		}
+		{{ if .RequiredFields }}
+			var missing []string
+			{{ range $i, $f := .Fields }}
+				{{ if $f.Required }}
+					if !seen[{{ $i }}] {
+						missing = append(missing, {{ printf "%q" $f.MapKey }})
+					}
+				{{ end }}
+			{{ end }}
+			if len(missing) > 0 {
+				return jr.PathError("", fmt.Errorf("missing required fields %q", missing))
+			}
+		{{ end }}
 
 		return nil
 	}`)
@@ -1868,6 +2610,17 @@ func GenMapEncodersForType(gti *GenTypeInfo, w io.Writer) error {
 
 // Generates 'map representation' dag json encoders for the given type
 func (g Gen) GenMapEncodersForType(gti *GenTypeInfo, w io.Writer) error {
+	gti, err := g.inScope(gti)
+	if err != nil {
+		return err
+	}
+	if gti.Representation == "tuple" {
+		return fmt.Errorf("%s is declared tuple encoded", gti.Name)
+	}
+	if gti.Union != "" {
+		return g.GenUnionEncodersForType(gti, w)
+	}
+
 	if err := g.emitDagJsonMarshalStructMap(w, gti); err != nil {
 		return err
 	}
@@ -1876,5 +2629,453 @@ func (g Gen) GenMapEncodersForType(gti *GenTypeInfo, w io.Writer) error {
 		return err
 	}
 
+	if err := g.emitScanLinks(w, gti); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// GenEncodersForType is a convenience wrapper around Gen.GenEncodersForType
+// using default options.
+func GenEncodersForType(gti *GenTypeInfo, w io.Writer) error {
+	return Gen{}.GenEncodersForType(gti, w)
+}
+
+// Generates dag json encoders for the given type in the representation it
+// declares, which is map unless it is tagged "tuple".
+func (g Gen) GenEncodersForType(gti *GenTypeInfo, w io.Writer) error {
+	if gti.Representation == "tuple" {
+		return g.GenTupleEncodersForType(gti, w)
+	}
+	return g.GenMapEncodersForType(gti, w)
+}
+
+// Generates dag json encoders for the given union type, in the representation
+// chosen by its "union" tag. Unions are encoded the same way whether tuple or
+// map encoders were requested.
+func (g Gen) GenUnionEncodersForType(gti *GenTypeInfo, w io.Writer) error {
+	gti, err := g.inScope(gti)
+	if err != nil {
+		return err
+	}
+	if err := g.emitDagJsonMarshalUnion(w, gti); err != nil {
+		return err
+	}
+
+	if err := g.emitDagJsonUnmarshalUnion(w, gti); err != nil {
+		return err
+	}
+
+	if err := g.emitScanLinks(w, gti); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// unionMember is the template data for a single member of a union.
+type unionMember struct {
+	Field
+	Union *GenTypeInfo
+}
+
+// Value describes the member's value, which lives behind the member pointer.
+func (m unionMember) Value() Field {
+	name := "t." + m.Name
+	path := strconv.Quote("." + m.Name)
+	if sameType(m.GoType(), bigIntType) {
+		// big.Int values are always handled through pointers.
+		return Field{Name: name, typ: m.GoType(), Pkg: m.Pkg, Pointer: true, MaxLen: m.MaxLen, path: path, scope: m.scope}
+	}
+	return Field{Name: "(*" + name + ")", typ: m.GoType(), Pkg: m.Pkg, MaxLen: m.MaxLen, path: path, scope: m.scope}
+}
+
+// KindConst is the runtime constant for the kind of a kinded union member.
+func (m unionMember) KindConst() string {
+	return "jsg.Kind" + strings.ToUpper(m.UnionKind[:1]) + m.UnionKind[1:]
+}
+
+func (g Gen) emitDagJsonMarshalUnion(w io.Writer, gti *GenTypeInfo) error {
+	err := g.doTemplate(w, gti, `
+	func {{ .Func "MarshalDagJSON" "w io.Writer" }} (err error) {
+		jw := jsg.NewBufferedDagJsonWriter(w)
+		defer jw.Release(w, &err)
+		if t == nil {
+			err := jw.WriteNull()
+			return err
+		}
+
+		members := 0
+		{{ range .Fields }}
+			if t.{{ .Name }} != nil {
+				members++
+			}
+		{{ end }}
+		if members != 1 {
+			return fmt.Errorf("{{ .Name }}: union must have exactly one member set, found %d", members)
+		}
+
+		switch {`)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range gti.Fields {
+		m := unionMember{f, gti}
+		if _, err := fmt.Fprintf(w, "\n\n\t// t.%s (%s) (%s)", f.Name, f.GoType(), f.GoType().Kind()); err != nil {
+			return err
+		}
+		if err := g.doTemplate(w, m, `
+		case t.{{ .Name }} != nil:`); err != nil {
+			return err
+		}
+
+		switch gti.Union {
+		case "keyed":
+			if err := g.doTemplate(w, m, `
+			if err := jw.WriteObjectOpen(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}
+			if err := jw.WriteString({{ printf "%q" .MapKey }}); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}`); err != nil {
+				return err
+			}
+			if err := g.emitDagJsonMarshalField(w, m.Value()); err != nil {
+				return err
+			}
+			if err := g.doTemplate(w, m, `
+			if err := jw.WriteObjectClose(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}`); err != nil {
+				return err
+			}
+		case "envelope":
+			// Entries are written in canonical order, so the discriminant
+			// comes first only if its key sorts first.
+			if err := g.doTemplate(w, m, `
+			if err := jw.WriteObjectOpen(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}
+			{{ if lt .Union.DiscriminantKey .Union.ContentKey }}
+				if err := jw.WriteString({{ printf "%q" .Union.DiscriminantKey }}); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteObjectColon(); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteString({{ printf "%q" .MapKey }}); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+			{{ end }}
+			if err := jw.WriteString({{ printf "%q" .Union.ContentKey }}); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}`); err != nil {
+				return err
+			}
+			if err := g.emitDagJsonMarshalField(w, m.Value()); err != nil {
+				return err
+			}
+			if err := g.doTemplate(w, m, `
+			{{ if not (lt .Union.DiscriminantKey .Union.ContentKey) }}
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteString({{ printf "%q" .Union.DiscriminantKey }}); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteObjectColon(); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+				if err := jw.WriteString({{ printf "%q" .MapKey }}); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+			{{ end }}
+			if err := jw.WriteObjectClose(); err != nil {
+				return fmt.Errorf("{{ .Union.Name }}: %w", err)
+			}`); err != nil {
+				return err
+			}
+		case "inline":
+			if err := g.doTemplate(w, m, `
+			{
+				var buf bytes.Buffer
+				if err := t.{{ .Name }}.MarshalDagJSON(&buf); err != nil {
+					return fmt.Errorf("t.{{ .Name }}: %w", err)
+				}
+				if err := jsg.WriteInlineUnion(jw, {{ printf "%q" .Union.DiscriminantKey }}, {{ printf "%q" .MapKey }}, buf.Bytes()); err != nil {
+					return fmt.Errorf("{{ .Union.Name }}: %w", err)
+				}
+			}`); err != nil {
+				return err
+			}
+		case "kinded":
+			if err := g.emitDagJsonMarshalField(w, m.Value()); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n\t}\n\treturn nil\n}\n\n")
+	return err
+}
+
+func (g Gen) emitDagJsonUnmarshalUnion(w io.Writer, gti *GenTypeInfo) error {
+	err := g.doTemplate(w, gti, `
+	func {{ .Func "UnmarshalDagJSON" "r io.Reader" }} (err error) {
+		*t = {{ .Name }}{}
+
+		jr := jsg.NewDagJsonReader(r)
+		defer jr.Release(r)
+		defer func() {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+		}()
+		{{ if eq .Union "keyed" }}
+			if err := jr.ReadObjectOpen(); err != nil {
+				return jr.PathError("", err)
+			}
+			discriminant, err := jr.ReadString({{ MaxLen 0 "String" }})
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+		{{ else if eq .Union "kinded" }}
+			kind, mr, err := jsg.ReadKinded(jr)
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			defer mr.Release(nil)
+			jr = mr
+		{{ else }}
+			{{ if eq .Union "envelope" }}
+				discriminant, mr, err := jsg.ReadEnvelopeUnion(jr, {{ printf "%q" .DiscriminantKey }}, {{ printf "%q" .ContentKey }})
+			{{ else }}
+				discriminant, mr, err := jsg.ReadInlineUnion(jr, {{ printf "%q" .DiscriminantKey }})
+			{{ end }}
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			// The member is read from its own reader, which has the
+			// caller's options.
+			defer mr.Release(nil)
+			jr = mr
+		{{ end }}
+
+		{{ if eq .Union "kinded" }}
+			switch kind {
+		{{ else }}
+			switch discriminant {
+		{{ end }}`)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range gti.Fields {
+		m := unionMember{f, gti}
+		if _, err := fmt.Fprintf(w, "\n\n\t// t.%s (%s) (%s)", f.Name, f.GoType(), f.GoType().Kind()); err != nil {
+			return err
+		}
+		if err := g.doTemplate(w, m, `
+		{{ if eq .Union.Union "kinded" }}
+			case {{ .KindConst }}:
+		{{ else }}
+			case {{ printf "%q" .MapKey }}:
+		{{ end }}
+		{{ if not .Value.Pointer }}
+			t.{{ .Name }} = new({{ .TypeName }})
+		{{ end }}`); err != nil {
+			return err
+		}
+		if err := g.emitDagJsonUnmarshalField(w, m.Value()); err != nil {
+			return err
+		}
+	}
+
+	return g.doTemplate(w, gti, `
+		default:
+			{{ if eq .Union "kinded" }}
+				return jr.PathError("", fmt.Errorf("no union member for kind %s", kind))
+			{{ else }}
+				return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+			{{ end }}
+		}
+		{{ if eq .Union "keyed" }}
+			if err := jr.ReadObjectClose(); err != nil {
+				return jr.PathError("", err)
+			}
+		{{ end }}
+		return nil
+	}
+
+	`)
+}
+
+// mayContainLinks reports whether values of type t can hold links. Structs
+// other than big.Int are assumed to, since their encoding is up to them.
+func mayContainLinks(t Type) bool {
+	switch t.Kind() {
+	case reflect.Struct:
+		return !sameType(t, bigIntType)
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return mayContainLinks(t.Elem())
+	default:
+		return false
+	}
+}
+
+func (g Gen) emitScanLinks(w io.Writer, gti *GenTypeInfo) error {
+	err := g.doTemplate(w, gti, `
+	func {{ .Func "ScanDagJSONLinks" "cb func(cid.Cid)" }} error {
+		if t == nil {
+			return nil
+		}`)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range gti.Fields {
+		if f.Name == FieldNameSelf {
+			f.Name = "(*t)"
+		} else {
+			f.Name = "t." + f.Name
+		}
+		if err := g.emitScanLinksField(w, f); err != nil {
+			return err
+		}
+	}
+	if gti.Unknown != nil {
+		f := *gti.Unknown
+		f.Name = "t." + f.Name
+		if err := g.emitScanLinksField(w, f); err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n\treturn nil\n}\n\n")
+	return err
+}
+
+// emitScanLinksField emits code calling cb with the links held in a value,
+// skipping values whose type cannot hold any.
+func (g Gen) emitScanLinksField(w io.Writer, f Field) error {
+	if !mayContainLinks(f.GoType()) {
+		return nil
+	}
+
+	if f.Pointer && f.GoType().Kind() != reflect.Struct {
+		if err := g.doTemplate(w, f, `
+		if {{ .Name }} != nil {`); err != nil {
+			return err
+		}
+		inner := f
+		inner.Name = "(*" + f.Name + ")"
+		inner.Pointer = false
+		if err := g.emitScanLinksField(w, inner); err != nil {
+			return err
+		}
+		return g.doTemplate(w, f, `
+		}`)
+	}
+
+	switch f.GoType().Kind() {
+	case reflect.Struct:
+		if sameType(f.GoType(), cidType) {
+			return g.doTemplate(w, f, `
+			{{ if .Pointer }}
+				if {{ .Name }} != nil && {{ .Name }}.Defined() {
+					cb(*{{ .Name }})
+				}
+			{{ else }}
+				if {{ .Name }}.Defined() {
+					cb({{ .Name }})
+				}
+			{{ end }}`)
+		}
+		return g.doTemplate(w, f, `
+		{{ if .Pointer }}
+			if {{ .Name }} != nil {
+				if err := jsg.ScanLinks({{ .Name }}, cb); err != nil {
+					return fmt.Errorf("{{ .Name }}: %w", err)
+				}
+			}
+		{{ else }}
+			if err := jsg.ScanLinks(&{{ .Name }}, cb); err != nil {
+				return fmt.Errorf("{{ .Name }}: %w", err)
+			}
+		{{ end }}`)
+	case reflect.Slice, reflect.Array:
+		if err := g.doTemplate(w, f, `
+		for _, {{ .Var "v" }} := range {{ .Name }} {`); err != nil {
+			return err
+		}
+		if err := g.emitScanLinksField(w, f.elemField(f.Var("v"))); err != nil {
+			return err
+		}
+		return g.doTemplate(w, f, `
+		}`)
+	case reflect.Map:
+		mf, err := newMapKeyField(f)
+		if err != nil {
+			return err
+		}
+		// Entries are visited in the order they are encoded, so that links are
+		// reported in the same order every time.
+		if err := g.doTemplate(w, mf, `
+		{
+			{{ .Var "keys" }} := make([]string, 0, len({{ .Name }}))
+			{{ if eq .KeyKind "string" }}
+				for {{ .Var "k" }} := range {{ .Name }} {
+					{{ .Var "keys" }} = append({{ .Var "keys" }}, string({{ .Var "k" }}))
+				}
+			{{ else }}
+				{{ .Var "mkeys" }} := make(map[string]{{ .KeyTypeName }}, len({{ .Name }}))
+				for {{ .Var "k" }} := range {{ .Name }} {
+					{{ if eq .KeyKind "text" }}
+						kk := {{ .Var "k" }}
+						kb, err := kk.MarshalText()
+						if err != nil {
+							return fmt.Errorf("{{ .Name }}: %w", err)
+						}
+						ks := string(kb)
+					{{ else if eq .KeyKind "int" }}
+						ks := strconv.FormatInt(int64({{ .Var "k" }}), 10)
+					{{ else }}
+						ks := strconv.FormatUint(uint64({{ .Var "k" }}), 10)
+					{{ end }}
+					{{ .Var "keys" }} = append({{ .Var "keys" }}, ks)
+					{{ .Var "mkeys" }}[ks] = {{ .Var "k" }}
+				}
+			{{ end }}
+			sort.Strings({{ .Var "keys" }})
+			for _, {{ .Var "k" }} := range {{ .Var "keys" }} {
+				{{ if eq .KeyKind "string" }}
+					{{ .Var "v" }} := {{ .Name }}[{{ .KeyTypeName }}({{ .Var "k" }})]
+				{{ else }}
+					{{ .Var "v" }} := {{ .Name }}[{{ .Var "mkeys" }}[{{ .Var "k" }}]]
+				{{ end }}`); err != nil {
+			return err
+		}
+		if err := g.emitScanLinksField(w, f.elemField(f.Var("v"))); err != nil {
+			return err
+		}
+		return g.doTemplate(w, f, `
+			}
+		}`)
+	default:
+		return nil
+	}
+}
@@ -21,6 +21,7 @@ func (jt DagJsonTime) MarshalDagJSON(w io.Writer) error {
 func (jt *DagJsonTime) UnmarshalDagJSON(r io.Reader) error {
 	var nsecs int64
 	jr := NewDagJsonReader(r)
+	defer jr.Release(r)
 	nsecs, err := jr.ReadNumberAsInt64()
 	if err != nil {
 		return nil
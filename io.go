@@ -1,137 +1,514 @@
 package typegen
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/base32"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"strconv"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
 
 	cid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
 	"pitr.ca/jsontokenizer"
 )
 
 var _ io.Writer = (*DagJsonWriter)(nil)
 
+// writerBufferSize is the amount of output a DagJsonWriter buffers before
+// writing it to the underlying writer.
+const writerBufferSize = 4096
+
+// DagJsonWriter writes DAG-JSON tokens to an underlying writer. A writer
+// returned by NewBufferedDagJsonWriter buffers them, and writes the buffer when
+// it fills and by Flush.
 type DagJsonWriter struct {
-	w io.Writer
+	w   io.Writer
+	buf []byte
+	err error
+	// direct is set if each token is written as soon as it is complete.
+	direct bool
+}
+
+var writerPool = sync.Pool{
+	New: func() any {
+		return &DagJsonWriter{buf: make([]byte, 0, writerBufferSize)}
+	},
 }
 
+// Write buffers p as is. It is for writing values that are already encoded.
 func (d *DagJsonWriter) Write(p []byte) (n int, err error) {
-	return d.w.Write(p)
+	if len(p) >= writerBufferSize {
+		// Not worth copying.
+		if err := d.Flush(); err != nil {
+			return 0, err
+		}
+		n, err = d.w.Write(p)
+		d.err = err
+		return n, err
+	}
+	d.buf = append(d.buf, p...)
+	if err := d.flushFull(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
 }
 
+// NewDagJsonWriter returns a writer for DAG-JSON to w. If w is already a
+// DagJsonWriter it is returned as is. Otherwise each token is written to w as
+// soon as it is complete, so nothing needs flushing. Use
+// NewBufferedDagJsonWriter to write fewer, larger chunks.
 func NewDagJsonWriter(w io.Writer) *DagJsonWriter {
+	jw := NewBufferedDagJsonWriter(w)
+	if jw != w {
+		jw.direct = true
+	}
+	return jw
+}
+
+// NewBufferedDagJsonWriter is like NewDagJsonWriter, except that output is
+// buffered, so Release or Flush must be called once everything has been
+// written, or the output will be lost. Generated MarshalDagJSON methods use it.
+func NewBufferedDagJsonWriter(w io.Writer) *DagJsonWriter {
 	if jw, ok := w.(*DagJsonWriter); ok {
 		return jw
 	}
-	return &DagJsonWriter{w}
+	jw := writerPool.Get().(*DagJsonWriter)
+	jw.w = w
+	return jw
+}
+
+// Flush writes any buffered output to the underlying writer. Once writing to
+// it has failed, the error is returned from every later call.
+func (d *DagJsonWriter) Flush() error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.buf) == 0 {
+		return nil
+	}
+	_, d.err = d.w.Write(d.buf)
+	d.buf = d.buf[:0]
+	return d.err
+}
+
+// Release finishes with a writer returned by NewBufferedDagJsonWriter(w) or
+// NewDagJsonWriter(w). Unless w was already a DagJsonWriter, which is left for
+// its owner to flush, the output is flushed if *err is nil, any error is stored
+// in *err and the writer must not be used again. Generated MarshalDagJSON
+// methods defer it, so that output is written once, when the outermost value is
+// complete.
+func (d *DagJsonWriter) Release(w io.Writer, err *error) {
+	if w == io.Writer(d) {
+		return
+	}
+	if *err == nil {
+		*err = d.Flush()
+	}
+	if cap(d.buf) <= 16*writerBufferSize {
+		*d = DagJsonWriter{buf: d.buf[:0]}
+		writerPool.Put(d)
+	}
+}
+
+// flushFull flushes the buffer once it is full, or straight away if the
+// writer isn't buffered.
+func (d *DagJsonWriter) flushFull() error {
+	if len(d.buf) < writerBufferSize && !d.direct {
+		return d.err
+	}
+	return d.Flush()
+}
+
+func (d *DagJsonWriter) writeByte(c byte) error {
+	d.buf = append(d.buf, c)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteArrayClose() error {
-	_, err := fmt.Fprintf(d.w, "]")
-	return err
+	return d.writeByte(']')
 }
 
 func (d *DagJsonWriter) WriteArrayOpen() error {
-	_, err := fmt.Fprintf(d.w, "[")
-	return err
+	return d.writeByte('[')
 }
 
 func (d *DagJsonWriter) WriteBigInt(n *big.Int) error {
-	_, err := fmt.Fprintf(d.w, `%s`, n.String())
-	return err
+	d.buf = n.Append(d.buf, 10)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteBool(b bool) error {
-	_, err := fmt.Fprintf(d.w, `%t`, b)
-	return err
+	d.buf = strconv.AppendBool(d.buf, b)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteBytes(b []byte) error {
-	_, err := fmt.Fprintf(d.w, `{"/":{"bytes":"%s"}}`, base64.RawStdEncoding.EncodeToString(b))
-	return err
+	d.buf = append(d.buf, `{"/":{"bytes":"`...)
+	d.buf = base64.RawStdEncoding.AppendEncode(d.buf, b)
+	d.buf = append(d.buf, `"}}`...)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteCid(c cid.Cid) error {
-	_, err := fmt.Fprintf(d.w, `{"/":"%s"}`, c)
-	return err
+	d.buf = append(d.buf, `{"/":"`...)
+	d.buf = append(d.buf, c.String()...)
+	d.buf = append(d.buf, `"}`...)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteComma() error {
-	_, err := fmt.Fprintf(d.w, ",")
-	return err
+	return d.writeByte(',')
+}
+
+// WriteFloat32 writes n in the shortest form that round trips to the same
+// float32. See WriteFloat64 for details.
+func (d *DagJsonWriter) WriteFloat32(n float32) error {
+	return d.writeFloat(float64(n), 32)
+}
+
+// WriteFloat64 writes n in the shortest form that round trips to the same
+// float64. A decimal point is always included for values that would otherwise
+// be written using integer syntax, so they are not decoded as integers. NaN and
+// infinities cannot be represented in DAG-JSON and return an error.
+func (d *DagJsonWriter) WriteFloat64(n float64) error {
+	return d.writeFloat(n, 64)
+}
+
+func (d *DagJsonWriter) writeFloat(n float64, bitSize int) error {
+	b, err := appendFloat(d.buf, n, bitSize)
+	if err != nil {
+		return err
+	}
+	d.buf = b
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteInt64(n int64) error {
-	_, err := fmt.Fprintf(d.w, "%d", n)
-	return err
+	d.buf = strconv.AppendInt(d.buf, n, 10)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteNull() error {
-	_, err := fmt.Fprintf(d.w, "null")
-	return err
+	d.buf = append(d.buf, "null"...)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteObjectClose() error {
-	_, err := fmt.Fprintf(d.w, "}")
-	return err
+	return d.writeByte('}')
 }
 
 func (d *DagJsonWriter) WriteObjectColon() error {
-	_, err := fmt.Fprintf(d.w, ":")
-	return err
+	return d.writeByte(':')
 }
 
 func (d *DagJsonWriter) WriteObjectOpen() error {
-	_, err := fmt.Fprintf(d.w, "{")
-	return err
+	return d.writeByte('{')
 }
 
 func (d *DagJsonWriter) WriteString(s string) error {
-	buf, err := json.Marshal(s)
-	if err != nil {
-		return fmt.Errorf("writing JSON string: %w", err)
-	}
-	_, err = d.w.Write(buf)
-	return err
+	d.buf = appendString(d.buf, s)
+	return d.flushFull()
 }
 
 func (d *DagJsonWriter) WriteUint8(n uint8) error {
-	_, err := fmt.Fprintf(d.w, "%d", n)
-	return err
+	return d.WriteUint64(uint64(n))
 }
 
 func (d *DagJsonWriter) WriteUint64(n uint64) error {
-	_, err := fmt.Fprintf(d.w, "%d", n)
-	return err
+	d.buf = strconv.AppendUint(d.buf, n, 10)
+	return d.flushFull()
+}
+
+const hex = "0123456789abcdef"
+
+// appendString appends s as a JSON string, escaped as encoding/json does:
+// quotes, backslashes and control characters, HTML special characters, U+2028
+// and U+2029. Invalid UTF-8 is replaced by U+FFFD.
+func appendString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\b':
+				dst = append(dst, '\\', 'b')
+			case '\f':
+				dst = append(dst, '\\', 'f')
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = utf8.AppendRune(dst, utf8.RuneError)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
 }
 
 var _ io.Reader = (*DagJsonReader)(nil)
 
+// readerBufferSize is the amount of input a DagJsonReader buffers.
+const readerBufferSize = 4096
+
 type DagJsonReader struct {
 	r    io.Reader
+	sr   strictReader
+	in   countingReader
+	buf  *bufio.Reader
 	tk   jsontokenizer.Tokenizer
 	peek jsontokenizer.TokType
+	// scratch holds the raw contents of the string or number being read, and
+	// unquoted holds a string once escape sequences are decoded. Both are
+	// reused from one value to the next.
+	scratch  scratchWriter
+	unquoted []byte
+
+	strict           bool
+	frames           []strictFrame
+	next             strictValue
+	maxMultihashSize int
+
+	// base is the offset of the input in an enclosing input, for readers of
+	// union members, and skipLen bytes of the enclosing input were left out
+	// at skipAt.
+	base, skipAt, skipLen int64
+}
+
+// readerPool holds readers released by the code that created them.
+var readerPool = sync.Pool{
+	New: func() any {
+		return &DagJsonReader{buf: bufio.NewReaderSize(nil, readerBufferSize)}
+	},
 }
 
-func NewDagJsonReader(r io.Reader) *DagJsonReader {
+// ReaderOption configures a DagJsonReader.
+type ReaderOption func(*DagJsonReader)
+
+// DefaultMaxMultihashSize is the default limit on the digest size of links
+// read by a DagJsonReader. It allows for 512 bit hashes and identity CIDs with
+// a small amount of inline data.
+const DefaultMaxMultihashSize = 128
+
+// MaxMultihashSize sets the largest multihash digest, in bytes, accepted in
+// links. The length of link strings is limited accordingly.
+func MaxMultihashSize(n int) ReaderOption {
+	return func(d *DagJsonReader) {
+		d.maxMultihashSize = n
+	}
+}
+
+// NewDagJsonReader returns a reader for the DAG-JSON in r. If r is already a
+// DagJsonReader it is returned as is, and the options are ignored. Otherwise
+// the reader may be one that was released, so the caller should call Release
+// when done with it.
+func NewDagJsonReader(r io.Reader, opts ...ReaderOption) *DagJsonReader {
 	if jr, ok := r.(*DagJsonReader); ok {
 		return jr
 	}
-	return &DagJsonReader{r, jsontokenizer.New(r), -1}
+	d := readerPool.Get().(*DagJsonReader)
+	d.strict = false
+	d.maxMultihashSize = DefaultMaxMultihashSize
+	d.base, d.skipAt, d.skipLen = 0, 0, 0
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.Reset(r)
+	return d
+}
+
+// Reset discards the reader's state, including any buffered input, and makes
+// it read from r. The reader keeps its options, so it can be reused for many
+// values without allocating a new one each time.
+func (d *DagJsonReader) Reset(r io.Reader) {
+	d.r = r
+	if d.strict {
+		d.sr = strictReader{r: r}
+		d.r = &d.sr
+	}
+	// The tokenizer reads through buf rather than buffering the input itself,
+	// as bufio.NewReader returns a large enough *bufio.Reader as is, so the
+	// offset of the next byte to be tokenized is known.
+	d.in = countingReader{r: d.r}
+	d.buf.Reset(&d.in)
+	d.tk = jsontokenizer.New(d.buf)
+	d.peek = -1
+	d.frames = d.frames[:0]
+	d.next = strictAny
+}
+
+// Sub returns a reader for raw, a value already read from d, with the same
+// options as d. It is used to decode union members once the member is known.
+// Offsets are relative to raw. The caller should call Release(nil) when done
+// with it.
+func (d *DagJsonReader) Sub(raw []byte) *DagJsonReader {
+	return NewDagJsonReader(bytes.NewReader(raw), func(s *DagJsonReader) {
+		s.strict = d.strict
+		s.maxMultihashSize = d.maxMultihashSize
+	})
+}
+
+// Release returns a reader created by NewDagJsonReader(r) to be reused. It
+// does nothing if r is the reader itself, as it then belongs to the caller. The
+// reader must not be used after it is released.
+func (d *DagJsonReader) Release(r io.Reader) {
+	if r == io.Reader(d) {
+		return
+	}
+	d.r = nil
+	d.sr = strictReader{}
+	d.in = countingReader{}
+	d.buf.Reset(nil)
+	d.tk = nil
+	d.frames = d.frames[:0]
+	if cap(d.scratch.buf) > 16*readerBufferSize || cap(d.unquoted) > 16*readerBufferSize {
+		return
+	}
+	readerPool.Put(d)
+}
+
+// Offset returns the number of bytes of input consumed so far. Readers of
+// union members returned by ReadKinded, ReadEnvelopeUnion and ReadInlineUnion
+// report the offset in the input of the union.
+func (d *DagJsonReader) Offset() int64 {
+	n := d.in.n - int64(d.buf.Buffered())
+	if d.skipLen > 0 && n >= d.skipAt {
+		n += d.skipLen
+	}
+	return d.base + n
+}
+
+// valueOffset returns the offset at which the next value starts, allowing for
+// a token that has been peeked. Numbers are unread once peeked.
+func (d *DagJsonReader) valueOffset() int64 {
+	switch d.peek {
+	case -1, jsontokenizer.TokNumber:
+		return d.Offset()
+	case jsontokenizer.TokNull, jsontokenizer.TokTrue:
+		return d.Offset() - 4
+	case jsontokenizer.TokFalse:
+		return d.Offset() - 5
+	default:
+		return d.Offset() - 1
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// scratchWriter collects what the tokenizer writes, up to a limit, in a buffer
+// that is reused.
+type scratchWriter struct {
+	buf   []byte
+	limit int
+}
+
+func (s *scratchWriter) reset(limit int) {
+	s.buf = s.buf[:0]
+	s.limit = limit
+}
+
+func (s *scratchWriter) Write(p []byte) (int, error) {
+	if len(p) > s.limit-len(s.buf) {
+		return 0, ErrLimitExceeded
+	}
+	s.buf = append(s.buf, p...)
+	return len(p), nil
 }
 
 func (d *DagJsonReader) token() (jsontokenizer.TokType, error) {
-	if d.peek != -1 {
-		tok := d.peek
+	tok := d.peek
+	if tok != -1 {
 		d.peek = -1
-		return tok, nil
+	} else {
+		var err error
+		tok, err = d.tk.Token()
+		if err != nil {
+			return tok, d.fail(err)
+		}
+	}
+	if d.strict {
+		if err := d.track(tok); err != nil {
+			return tok, d.fail(err)
+		}
+	}
+	return tok, nil
+}
+
+// readNumber reads the digits of a number whose token has been read. The
+// result is only valid until the next value is read.
+func (d *DagJsonReader) readNumber(maxLength int) ([]byte, error) {
+	d.scratch.reset(maxLength)
+	if _, err := d.tk.ReadNumber(&d.scratch); err != nil {
+		return nil, d.fail(err)
+	}
+	if d.strict {
+		if err := checkNumber(string(d.scratch.buf)); err != nil {
+			return nil, d.fail(err)
+		}
 	}
-	return d.tk.Token()
+	return d.scratch.buf, nil
+}
+
+// numberToken reads the token of a number, or of null if orNull is set, in
+// which case it returns false.
+func (d *DagJsonReader) numberToken(orNull bool) (bool, error) {
+	tok, err := d.token()
+	if err != nil {
+		return false, err
+	}
+	if orNull && tok == jsontokenizer.TokNull {
+		return false, nil
+	}
+	if tok != jsontokenizer.TokNumber {
+		return false, d.unexpected("number", tokenName(tok))
+	}
+	return true, nil
 }
 
 func (d *DagJsonReader) peekToken() (jsontokenizer.TokType, error) {
@@ -140,7 +517,7 @@ func (d *DagJsonReader) peekToken() (jsontokenizer.TokType, error) {
 	}
 	tok, err := d.tk.Token()
 	if err != nil {
-		return tok, err
+		return tok, d.fail(err)
 	}
 	d.peek = tok
 	return tok, nil
@@ -170,7 +547,7 @@ func (d *DagJsonReader) PeekType() (string, error) {
 	case jsontokenizer.TokString:
 		return "string", nil
 	default:
-		return "", fmt.Errorf("unexpected state, wanted start of type but read: %s", tokenName(tok))
+		return "", d.unexpected("start of value", tokenName(tok))
 	}
 }
 
@@ -261,6 +638,18 @@ func (d *DagJsonReader) DiscardType() error {
 	return nil
 }
 
+// ReadEOF checks that nothing but whitespace follows the last value read.
+func (d *DagJsonReader) ReadEOF() error {
+	tok, err := d.peekToken()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return d.unexpected("end of input", tokenName(tok))
+}
+
 // Read from the underlying reader. You almost certainly don't want to do this.
 func (d *DagJsonReader) Read(p []byte) (int, error) {
 	return d.r.Read(p)
@@ -272,7 +661,7 @@ func (d *DagJsonReader) ReadNull() error {
 		return err
 	}
 	if tok != jsontokenizer.TokNull {
-		return fmt.Errorf("expected boolean but read %s", tokenName(tok))
+		return d.unexpected("null", tokenName(tok))
 	}
 	return nil
 }
@@ -292,7 +681,7 @@ func (d *DagJsonReader) ReadBool() (bool, error) {
 		return false, err
 	}
 	if value == nil {
-		return false, errors.New("expected boolean but read null")
+		return false, d.unexpected("boolean", "null")
 	}
 	return *value, nil
 }
@@ -313,7 +702,7 @@ func (d *DagJsonReader) ReadBoolOrNull() (*bool, error) {
 	case jsontokenizer.TokNull:
 		return nil, nil
 	default:
-		return nil, fmt.Errorf("expected boolean but read %s", tokenName(tok))
+		return nil, d.unexpected("boolean", tokenName(tok))
 	}
 }
 
@@ -323,7 +712,7 @@ func (d *DagJsonReader) ReadBytes(maxLength int) ([]byte, error) {
 		return nil, err
 	}
 	if b == nil {
-		return nil, errors.New("expected bytes but read null")
+		return nil, d.unexpected("bytes", "null")
 	}
 	return *b, nil
 }
@@ -337,14 +726,14 @@ func (d *DagJsonReader) ReadBytesOrNull(maxLength int) (*[]byte, error) {
 		return nil, nil
 	}
 	if tok != jsontokenizer.TokObjectOpen {
-		return nil, fmt.Errorf("expected object open but read %s", tokenName(tok))
+		return nil, d.unexpected("{", tokenName(tok))
 	}
 	slash, err := d.ReadString(1)
 	if err != nil {
 		return nil, err
 	}
 	if slash != "/" {
-		return nil, fmt.Errorf("expected / but read %s", slash)
+		return nil, d.unexpected(`"/"`, strconv.Quote(slash))
 	}
 	if err := d.ReadObjectColon(); err != nil {
 		return nil, err
@@ -357,7 +746,7 @@ func (d *DagJsonReader) ReadBytesOrNull(maxLength int) (*[]byte, error) {
 		return nil, err
 	}
 	if bytesKey != "bytes" {
-		return nil, fmt.Errorf("expected \"bytes\" but read %s", bytesKey)
+		return nil, d.unexpected(`"bytes"`, strconv.Quote(bytesKey))
 	}
 	if err := d.ReadObjectColon(); err != nil {
 		return nil, err
@@ -368,7 +757,7 @@ func (d *DagJsonReader) ReadBytesOrNull(maxLength int) (*[]byte, error) {
 	}
 	decoded, err := base64.RawStdEncoding.DecodeString(s)
 	if err != nil {
-		return nil, err
+		return nil, d.fail(err)
 	}
 	if err := d.ReadObjectClose(); err != nil {
 		return nil, err
@@ -385,7 +774,7 @@ func (d *DagJsonReader) ReadCid() (cid.Cid, error) {
 		return cid.Undef, err
 	}
 	if value == nil {
-		return cid.Undef, errors.New("expected CID but read null")
+		return cid.Undef, d.unexpected("CID", "null")
 	}
 	return *value, nil
 }
@@ -399,29 +788,28 @@ func (d *DagJsonReader) ReadCidOrNull() (*cid.Cid, error) {
 		return nil, nil
 	}
 	if tok != jsontokenizer.TokObjectOpen {
-		return nil, fmt.Errorf("expected object open but read %s", tokenName(tok))
+		return nil, d.unexpected("{", tokenName(tok))
 	}
 	slash, err := d.ReadString(1)
 	if err != nil {
 		return nil, err
 	}
 	if slash != "/" {
-		return nil, fmt.Errorf("expected / but read %s", slash)
+		return nil, d.unexpected(`"/"`, strconv.Quote(slash))
 	}
 	if err := d.ReadObjectColon(); err != nil {
 		return nil, err
 	}
-	s, err := d.ReadString(59)
+	s, err := d.ReadString(d.maxLinkLength())
 	if err != nil {
+		if errors.Is(err, ErrLimitExceeded) {
+			return nil, d.errorf("link longer than %d characters", d.maxLinkLength())
+		}
 		return nil, err
 	}
-	// TODO: spec compliance:
-	// decode multibase base32 then decode CIDv1
-	// or
-	// decode base58btc then decode CIDv0
-	parsed, err := cid.Parse(s)
+	parsed, err := d.parseLink(s)
 	if err != nil {
-		return nil, err
+		return nil, d.fail(err)
 	}
 	if err := d.ReadObjectClose(); err != nil {
 		return nil, err
@@ -429,134 +817,217 @@ func (d *DagJsonReader) ReadCidOrNull() (*cid.Cid, error) {
 	return &parsed, nil
 }
 
-func (d *DagJsonReader) ReadNumberAsString(maxLength int) (string, error) {
-	tok, err := d.token()
+// maxLinkLength is the length of the longest link string that can hold a
+// multihash of the maximum size: a base32 CIDv1 with four varints ahead of
+// the digest and the multibase prefix.
+func (d *DagJsonReader) maxLinkLength() int {
+	n := 4*binary.MaxVarintLen64 + d.maxMultihashSize
+	return 1 + base32.StdEncoding.WithPadding(base32.NoPadding).EncodedLen(n)
+}
+
+// parseLink decodes the string of a link, which the DAG-JSON spec requires to
+// be a CIDv1 in base32 multibase or a CIDv0 in bare base58btc. Other
+// multibases are only accepted when not strict.
+func (d *DagJsonReader) parseLink(s string) (cid.Cid, error) {
+	if len(s) > d.maxLinkLength() {
+		return cid.Undef, fmt.Errorf("link longer than %d characters", d.maxLinkLength())
+	}
+	c, err := cid.Decode(s)
 	if err != nil {
-		return "", err
+		return cid.Undef, err
 	}
-	if tok != jsontokenizer.TokNumber {
-		return "", fmt.Errorf("expected number but read %s", tokenName(tok))
+	if d.strict {
+		switch {
+		case c.Version() == 0 && s[0] == 'b':
+			return cid.Undef, fmt.Errorf("strict DAG-JSON: CIDv0 link %s must be bare base58btc", s)
+		case c.Version() == 1 && s[0] != 'b':
+			return cid.Undef, fmt.Errorf("strict DAG-JSON: CIDv1 link %s must be base32", s)
+		case c.String() != s:
+			return cid.Undef, fmt.Errorf("strict DAG-JSON: link %s must be written as %s", s, c)
+		}
+	}
+	dmh, err := mh.Decode(c.Hash())
+	if err != nil {
+		return cid.Undef, err
+	}
+	if dmh.Length > d.maxMultihashSize {
+		return cid.Undef, fmt.Errorf("link multihash of %d bytes is larger than the maximum of %d", dmh.Length, d.maxMultihashSize)
+	}
+	return c, nil
+}
+
+func (d *DagJsonReader) ReadNumberAsString(maxLength int) (string, error) {
+	if _, err := d.numberToken(false); err != nil {
+		return "", err
 	}
-	var buf bytes.Buffer
-	if _, err := d.tk.ReadNumber(NewLimitWriter(&buf, maxLength)); err != nil {
+	b, err := d.readNumber(maxLength)
+	if err != nil {
 		return "", err
 	}
-	return buf.String(), nil
+	return string(b), nil
 }
 
 func (d *DagJsonReader) ReadNumberAsUint8() (uint8, error) {
-	tok, err := d.token()
-	if err != nil {
+	if _, err := d.numberToken(false); err != nil {
 		return 0, err
 	}
-	if tok != jsontokenizer.TokNumber {
-		return 0, fmt.Errorf("expected number but read %s", tokenName(tok))
-	}
-	var buf bytes.Buffer
-	if _, err := d.tk.ReadNumber(NewLimitWriter(&buf, 3)); err != nil {
+	b, err := d.readNumber(3)
+	if err != nil {
 		return 0, err
 	}
-	n, err := strconv.ParseUint(buf.String(), 10, 8)
+	n, err := strconv.ParseUint(string(b), 10, 8)
 	if err != nil {
-		return 0, err
+		return 0, d.fail(err)
 	}
 	return uint8(n), nil
 }
 
 func (d *DagJsonReader) ReadNumberAsInt64() (int64, error) {
-	value, err := d.ReadNumberAsInt64OrNull()
-	if err != nil {
+	if _, err := d.numberToken(false); err != nil {
 		return 0, err
 	}
-	if value == nil {
-		return 0, errors.New("expected number but read null")
-	}
-	return *value, nil
+	return d.readInt64()
 }
 
 func (d *DagJsonReader) ReadNumberAsInt64OrNull() (*int64, error) {
-	tok, err := d.token()
+	ok, err := d.numberToken(true)
+	if err != nil || !ok {
+		return nil, err
+	}
+	n, err := d.readInt64()
 	if err != nil {
 		return nil, err
 	}
-	if tok == jsontokenizer.TokNull {
-		return nil, nil
+	return &n, nil
+}
+
+// readInt64 reads the digits of an integer whose token has been read.
+func (d *DagJsonReader) readInt64() (int64, error) {
+	b, err := d.readNumber(20)
+	if err != nil {
+		return 0, err
 	}
-	if tok != jsontokenizer.TokNumber {
-		return nil, fmt.Errorf("expected number but read %s", tokenName(tok))
+	// The conversion does not allocate, as the string does not escape.
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, d.fail(err)
+	}
+	return n, nil
+}
+
+func (d *DagJsonReader) ReadNumberAsUint64() (uint64, error) {
+	if _, err := d.numberToken(false); err != nil {
+		return 0, err
 	}
-	var buf bytes.Buffer
-	if _, err := d.tk.ReadNumber(NewLimitWriter(&buf, 20)); err != nil {
+	return d.readUint64()
+}
+
+func (d *DagJsonReader) ReadNumberAsUint64OrNull() (*uint64, error) {
+	ok, err := d.numberToken(true)
+	if err != nil || !ok {
 		return nil, err
 	}
-	n, err := strconv.ParseInt(buf.String(), 10, 64)
+	n, err := d.readUint64()
 	if err != nil {
 		return nil, err
 	}
 	return &n, nil
 }
 
-func (d *DagJsonReader) ReadNumberAsUint64() (uint64, error) {
-	value, err := d.ReadNumberAsUint64OrNull()
+// readUint64 reads the digits of an unsigned integer whose token has been read.
+func (d *DagJsonReader) readUint64() (uint64, error) {
+	b, err := d.readNumber(20)
 	if err != nil {
 		return 0, err
 	}
-	if value == nil {
-		return 0, errors.New("expected number but read null")
+	n, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0, d.fail(err)
 	}
-	return *value, nil
+	return n, nil
 }
 
-func (d *DagJsonReader) ReadNumberAsUint64OrNull() (*uint64, error) {
-	tok, err := d.token()
-	if err != nil {
+func (d *DagJsonReader) ReadNumberAsFloat32() (float32, error) {
+	if _, err := d.numberToken(false); err != nil {
+		return 0, err
+	}
+	n, err := d.readFloat(32)
+	return float32(n), err
+}
+
+func (d *DagJsonReader) ReadNumberAsFloat32OrNull() (*float32, error) {
+	ok, err := d.numberToken(true)
+	if err != nil || !ok {
 		return nil, err
 	}
-	if tok == jsontokenizer.TokNull {
-		return nil, nil
+	n, err := d.readFloat(32)
+	if err != nil {
+		return nil, err
 	}
-	if tok != jsontokenizer.TokNumber {
-		return nil, fmt.Errorf("expected number but read %s", tokenName(tok))
+	f := float32(n)
+	return &f, nil
+}
+
+func (d *DagJsonReader) ReadNumberAsFloat64() (float64, error) {
+	if _, err := d.numberToken(false); err != nil {
+		return 0, err
 	}
-	var buf bytes.Buffer
-	if _, err := d.tk.ReadNumber(NewLimitWriter(&buf, 20)); err != nil {
+	return d.readFloat(64)
+}
+
+func (d *DagJsonReader) ReadNumberAsFloat64OrNull() (*float64, error) {
+	ok, err := d.numberToken(true)
+	if err != nil || !ok {
 		return nil, err
 	}
-	n, err := strconv.ParseUint(buf.String(), 10, 64)
+	n, err := d.readFloat(64)
 	if err != nil {
 		return nil, err
 	}
 	return &n, nil
 }
 
-func (d *DagJsonReader) ReadNumberAsBigInt(maxLength int) (*big.Int, error) {
-	tok, err := d.token()
+// readFloat reads the digits of a float whose token has been read.
+func (d *DagJsonReader) readFloat(bitSize int) (float64, error) {
+	b, err := d.readNumber(maxFloatLength)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	if tok != jsontokenizer.TokNumber {
-		return nil, fmt.Errorf("expected number but read %s", tokenName(tok))
+	n, err := strconv.ParseFloat(string(b), bitSize)
+	if err != nil {
+		return 0, d.fail(err)
 	}
-	var buf bytes.Buffer
-	if _, err := d.tk.ReadNumber(NewLimitWriter(&buf, maxLength)); err != nil {
+	return n, nil
+}
+
+// ReadNumberAsBigInt reads an integer of any sign with up to maxDigits digits.
+func (d *DagJsonReader) ReadNumberAsBigInt(maxDigits int) (*big.Int, error) {
+	if _, err := d.numberToken(false); err != nil {
 		return nil, err
 	}
-	n, ok := big.NewInt(0).SetString(buf.String(), 10)
+	b, err := d.readNumber(maxDigits + 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxDigits && b[0] != '-' {
+		return nil, d.fail(ErrLimitExceeded)
+	}
+	n, ok := big.NewInt(0).SetString(string(b), 10)
 	if !ok {
-		return nil, errors.New("failed to set big int value")
+		return nil, d.errorf("invalid integer %s", b)
 	}
 	return n, nil
 }
 
 func (d *DagJsonReader) ReadString(maxLength int) (string, error) {
-	value, err := d.ReadStringOrNull(maxLength)
+	tok, err := d.token()
 	if err != nil {
 		return "", err
 	}
-	if value == nil {
-		return "", errors.New("expected string but read null")
+	if tok != jsontokenizer.TokString {
+		return "", d.unexpected("string", tokenName(tok))
 	}
-	return *value, nil
+	return d.readString(maxLength)
 }
 
 func (d *DagJsonReader) ReadStringOrNull(maxLength int) (*string, error) {
@@ -568,21 +1039,144 @@ func (d *DagJsonReader) ReadStringOrNull(maxLength int) (*string, error) {
 		return nil, nil
 	}
 	if tok != jsontokenizer.TokString {
-		return nil, fmt.Errorf("expected string but read %s", tokenName(tok))
+		return nil, d.unexpected("string", tokenName(tok))
 	}
-
-	var buf bytes.Buffer
-	buf.Write([]byte(`"`))
-	if _, err := d.tk.ReadString(NewLimitWriter(&buf, maxLength)); err != nil {
+	s, err := d.readString(maxLength)
+	if err != nil {
 		return nil, err
 	}
-	buf.Write([]byte(`"`))
+	return &s, nil
+}
+
+// readString reads the contents of a string whose token has been read. The
+// limit applies to the contents as written, before escape sequences are
+// decoded.
+func (d *DagJsonReader) readString(maxLength int) (string, error) {
+	d.scratch.reset(maxLength)
+	if _, err := d.tk.ReadString(&d.scratch); err != nil {
+		return "", d.fail(err)
+	}
+	raw := d.scratch.buf
 	var s string
-	err = json.Unmarshal(buf.Bytes(), &s)
-	if err != nil {
-		return nil, fmt.Errorf("reading JSON string: %w", err)
+	if needsUnquoting(raw) {
+		var err error
+		d.unquoted, err = appendUnquoted(d.unquoted[:0], raw)
+		if err != nil {
+			return "", d.errorf("reading JSON string: %w", err)
+		}
+		s = string(d.unquoted)
+	} else {
+		s = string(raw)
 	}
-	return &s, nil
+	if d.strict {
+		if err := checkStringEncoding(raw, s); err != nil {
+			return "", d.fail(err)
+		}
+		if err := d.checkString(s); err != nil {
+			return "", d.fail(err)
+		}
+	}
+	return s, nil
+}
+
+// needsUnquoting reports whether the contents of a string are anything other
+// than valid UTF-8 without escape sequences or control characters.
+func needsUnquoting(s []byte) bool {
+	ascii := true
+	for _, c := range s {
+		if c == '\\' || c < ' ' {
+			return true
+		}
+		if c >= utf8.RuneSelf {
+			ascii = false
+		}
+	}
+	return !ascii && !utf8.Valid(s)
+}
+
+// appendUnquoted appends the contents of a string with its escape sequences
+// decoded, as encoding/json does: invalid UTF-8 and unpaired surrogates become
+// U+FFFD, and control characters must be escaped.
+func appendUnquoted(dst, s []byte) ([]byte, error) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '\\':
+			if i+1 == len(s) {
+				return dst, errors.New("unexpected end of string in escape sequence")
+			}
+			switch e := s[i+1]; e {
+			case '"', '\\', '/':
+				dst = append(dst, e)
+			case 'b':
+				dst = append(dst, '\b')
+			case 'f':
+				dst = append(dst, '\f')
+			case 'n':
+				dst = append(dst, '\n')
+			case 'r':
+				dst = append(dst, '\r')
+			case 't':
+				dst = append(dst, '\t')
+			case 'u':
+				r := getu4(s[i:])
+				if r < 0 {
+					return dst, fmt.Errorf("invalid escape sequence %q", s[i:min(i+6, len(s))])
+				}
+				if utf16.IsSurrogate(r) {
+					if dec := utf16.DecodeRune(r, getu4(s[i+6:])); dec != utf8.RuneError {
+						dst = utf8.AppendRune(dst, dec)
+						i += 12
+						continue
+					}
+					r = utf8.RuneError
+				}
+				dst = utf8.AppendRune(dst, r)
+				i += 6
+				continue
+			default:
+				return dst, fmt.Errorf("invalid escape sequence %q", s[i:i+2])
+			}
+			i += 2
+		case c < ' ':
+			return dst, fmt.Errorf("invalid control character %q in string", c)
+		case c < utf8.RuneSelf:
+			dst = append(dst, c)
+			i++
+		default:
+			r, size := utf8.DecodeRune(s[i:])
+			if r == utf8.RuneError && size == 1 {
+				dst = utf8.AppendRune(dst, r)
+			} else {
+				dst = append(dst, s[i:i+size]...)
+			}
+			i += size
+		}
+	}
+	return dst, nil
+}
+
+// getu4 decodes the four hex digits of a \uXXXX escape at the start of s, or
+// returns -1.
+func getu4(s []byte) rune {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return -1
+	}
+	var r rune
+	for _, c := range s[2:6] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		r = r*16 + rune(c)
+	}
+	return r
 }
 
 func (d *DagJsonReader) ReadObjectColon() error {
@@ -591,7 +1185,7 @@ func (d *DagJsonReader) ReadObjectColon() error {
 		return err
 	}
 	if tok != jsontokenizer.TokObjectColon {
-		return fmt.Errorf("expected object colon but read %s", tokenName(tok))
+		return d.unexpected(":", tokenName(tok))
 	}
 	return nil
 }
@@ -602,7 +1196,7 @@ func (d *DagJsonReader) ReadObjectOpen() error {
 		return err
 	}
 	if tok != jsontokenizer.TokObjectOpen {
-		return fmt.Errorf("expected object open but read %s", tokenName(tok))
+		return d.unexpected("{", tokenName(tok))
 	}
 	return nil
 }
@@ -622,7 +1216,7 @@ func (d *DagJsonReader) ReadObjectClose() error {
 		return err
 	}
 	if tok != jsontokenizer.TokObjectClose {
-		return fmt.Errorf("expected object close but read %s", tokenName(tok))
+		return d.unexpected("}", tokenName(tok))
 	}
 	return nil
 }
@@ -639,7 +1233,7 @@ func (d *DagJsonReader) ReadObjectCloseOrComma() (bool, error) {
 	case jsontokenizer.TokComma:
 		return false, nil
 	default:
-		return false, fmt.Errorf("expected object close or comma but read %s", tokenName(tok))
+		return false, d.unexpected("} or ,", tokenName(tok))
 	}
 }
 
@@ -649,7 +1243,7 @@ func (d *DagJsonReader) ReadArrayOpen() error {
 		return err
 	}
 	if tok != jsontokenizer.TokArrayOpen {
-		return fmt.Errorf("expected array open but read %s", tokenName(tok))
+		return d.unexpected("[", tokenName(tok))
 	}
 	return nil
 }
@@ -663,7 +1257,7 @@ func (d *DagJsonReader) ReadArrayOpenOrNull() (bool, error) {
 		return false, nil
 	}
 	if tok != jsontokenizer.TokArrayOpen {
-		return false, fmt.Errorf("expected array open but read %s", tokenName(tok))
+		return false, d.unexpected("[ or null", tokenName(tok))
 	}
 	return true, nil
 }
@@ -683,7 +1277,7 @@ func (d *DagJsonReader) ReadArrayClose() error {
 		return err
 	}
 	if tok != jsontokenizer.TokArrayClose {
-		return fmt.Errorf("expected array close but read %s", tokenName(tok))
+		return d.unexpected("]", tokenName(tok))
 	}
 	return nil
 }
@@ -700,7 +1294,7 @@ func (d *DagJsonReader) ReadArrayCloseOrComma() (bool, error) {
 	case jsontokenizer.TokComma:
 		return false, nil
 	default:
-		return false, fmt.Errorf("expected array close or comma but read %s", tokenName(tok))
+		return false, d.unexpected("] or ,", tokenName(tok))
 	}
 }
 
@@ -731,6 +1325,47 @@ func tokenName(tok jsontokenizer.TokType) string {
 	}
 }
 
+// maxFloatLength is the maximum number of characters accepted for a float.
+// The shortest round trip form of a float64 is well under this, but it leaves
+// room for encoders that write a few more digits than necessary.
+const maxFloatLength = 64
+
+// formatFloat formats n for DAG-JSON. See appendFloat.
+func formatFloat(n float64, bitSize int) (string, error) {
+	b, err := appendFloat(nil, n, bitSize)
+	return string(b), err
+}
+
+// appendFloat appends n formatted for DAG-JSON. It uses the same rules as
+// encoding/json for choosing between decimal and exponent notation, and
+// additionally appends ".0" to integral values so they remain
+// distinguishable from integers.
+func appendFloat(dst []byte, n float64, bitSize int) ([]byte, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return dst, fmt.Errorf("unsupported float value: %v", n)
+	}
+	abs := math.Abs(n)
+	fmt := byte('f')
+	if abs != 0 {
+		if bitSize == 64 && (abs < 1e-6 || abs >= 1e21) || bitSize == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			fmt = 'e'
+		}
+	}
+	start := len(dst)
+	b := strconv.AppendFloat(dst, n, fmt, -1, bitSize)
+	if fmt == 'e' {
+		// clean up e-09 to e-9
+		l := len(b)
+		if l-start >= 4 && b[l-4] == 'e' && b[l-3] == '-' && b[l-2] == '0' {
+			b[l-2] = b[l-1]
+			b = b[:l-1]
+		}
+	} else if bytes.IndexByte(b[start:], '.') == -1 {
+		b = append(b, ".0"...)
+	}
+	return b, nil
+}
+
 var ErrLimitExceeded = errors.New("limit exceeded")
 
 func NewLimitWriter(w io.Writer, n int) io.Writer {
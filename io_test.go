@@ -0,0 +1,258 @@
+package typegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	cid "github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestReadCid(t *testing.T) {
+	data := bytes.Repeat([]byte("inline"), 20)
+	sum := func(code uint64, length int) cid.Cid {
+		c, err := cid.Prefix{Version: 1, Codec: cid.Raw, MhType: code, MhLength: length}.Sum(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	v0, err := cid.Prefix{Version: 0, Codec: cid.DagProtobuf, MhType: mh.SHA2_256, MhLength: -1}.Sum(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []cid.Cid{
+		v0,
+		sum(mh.SHA2_256, -1),
+		sum(mh.SHA2_512, -1),
+		sum(mh.BLAKE2B_MIN+63, -1),
+		sum(mh.IDENTITY, -1),
+	} {
+		var buf bytes.Buffer
+		if err := JsonCid(c).MarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range [][]ReaderOption{nil, {Strict()}} {
+			read, err := NewDagJsonReader(bytes.NewReader(buf.Bytes()), opts...).ReadCid()
+			if err != nil {
+				t.Fatalf("%s: %s", buf.String(), err)
+			}
+			if !read.Equals(c) {
+				t.Fatalf("expected %s, got %s", c, read)
+			}
+		}
+	}
+
+	t.Run("multihash size", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := JsonCid(sum(mh.IDENTITY, -1)).MarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		_, err := NewDagJsonReader(bytes.NewReader(buf.Bytes()), MaxMultihashSize(64)).ReadCid()
+		if err == nil || !strings.Contains(err.Error(), "longer than") {
+			t.Fatalf("expected a length error, got %v", err)
+		}
+
+		buf.Reset()
+		if err := JsonCid(sum(mh.SHA2_512, -1)).MarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		_, err = NewDagJsonReader(bytes.NewReader(buf.Bytes()), MaxMultihashSize(32)).ReadCid()
+		if err == nil || !strings.Contains(err.Error(), "larger than the maximum") {
+			t.Fatalf("expected an error for a digest over the maximum size, got %v", err)
+		}
+	})
+
+	t.Run("multibase", func(t *testing.T) {
+		c := sum(mh.SHA2_256, -1)
+		base36, _ := c.StringOfBase('k')
+		base58, _ := c.StringOfBase('z')
+		base32Upper, _ := c.StringOfBase('B')
+		for _, s := range []string{base36, base58, base32Upper} {
+			in := `{"/":"` + s + `"}`
+			if _, err := NewDagJsonReader(strings.NewReader(in)).ReadCid(); err != nil {
+				t.Errorf("%s: %s", in, err)
+			}
+			if _, err := NewDagJsonReader(strings.NewReader(in), Strict()).ReadCid(); err == nil {
+				t.Errorf("%s: expected strict decoding to fail", in)
+			}
+		}
+	})
+}
+
+func TestWriteString(t *testing.T) {
+	check := func(s string) bool {
+		var buf bytes.Buffer
+		jw := NewDagJsonWriter(&buf)
+		if err := jw.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+		if err := jw.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		expected, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("expected %s, got %s", expected, buf.Bytes())
+			return false
+		}
+		return true
+	}
+	for _, s := range []string{
+		"",
+		"plain",
+		"quote \" backslash \\ slash /",
+		"\x00\x01\b\f\n\r\t\x1f\x7f",
+		"<a href=\"x\">&amp;</a>",
+		"\u2028 \u2029 ü € 𝄞",
+	} {
+		check(s)
+	}
+	if err := quick.Check(check, nil); err != nil {
+		t.Error(err)
+	}
+
+	var buf bytes.Buffer
+	jw := NewDagJsonWriter(&buf)
+	if err := jw.WriteString("bad \xff utf8 \xe2\x82"); err != nil {
+		t.Fatal(err)
+	}
+	if err := jw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "\"bad \ufffd utf8 \ufffd\ufffd\"" {
+		t.Errorf("expected invalid UTF-8 to be replaced, got %s", buf.String())
+	}
+}
+
+func TestWriterFlush(t *testing.T) {
+	var buf bytes.Buffer
+	jw := NewBufferedDagJsonWriter(&buf)
+	big := strings.Repeat("a", writerBufferSize)
+	var expected strings.Builder
+	expected.WriteString("[")
+	jw.WriteArrayOpen()
+	for i := 0; i < 3; i++ {
+		jw.WriteString(big)
+		jw.WriteComma()
+		jw.Write([]byte(`"` + big + `"`))
+		jw.WriteComma()
+		expected.WriteString(`"` + big + `","` + big + `",`)
+	}
+	jw.WriteNull()
+	jw.WriteArrayClose()
+	expected.WriteString("null]")
+	if buf.Len() == 0 || buf.Len() == expected.Len() {
+		t.Fatalf("expected part of the output to be written before flushing, got %d bytes", buf.Len())
+	}
+	if err := jw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expected.String() {
+		t.Fatal("unexpected output")
+	}
+}
+
+func TestWriterUnbuffered(t *testing.T) {
+	var buf bytes.Buffer
+	jw := NewDagJsonWriter(&buf)
+	jw.WriteArrayOpen()
+	jw.WriteString("a")
+	if buf.String() != `["a"` {
+		t.Fatalf("expected each token to be written without flushing, got %s", buf.String())
+	}
+	if NewDagJsonWriter(jw) != jw || NewBufferedDagJsonWriter(jw) != jw {
+		t.Fatal("expected a DagJsonWriter to be used as is")
+	}
+
+	// A hand-written method that never flushes still writes its output.
+	var err error
+	func() {
+		jw := NewBufferedDagJsonWriter(&buf)
+		defer jw.Release(&buf, &err)
+		nested := NewDagJsonWriter(jw)
+		err = nested.WriteArrayClose()
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `["a"]` {
+		t.Fatalf("unexpected output %s", buf.String())
+	}
+}
+
+func TestReadString(t *testing.T) {
+	check := func(in string) bool {
+		var expected string
+		expectedErr := json.Unmarshal([]byte(in), &expected)
+		s, err := NewDagJsonReader(strings.NewReader(in)).ReadString(MaxLength)
+		if (err != nil) != (expectedErr != nil) {
+			t.Errorf("%q: expected error %v, got %v", in, expectedErr, err)
+			return false
+		}
+		if s != expected {
+			t.Errorf("%q: expected %q, got %q", in, expected, s)
+			return false
+		}
+		return true
+	}
+	for _, in := range []string{
+		`""`,
+		`"plain"`,
+		`"\" \\ \/ \b \f \n \r \t"`,
+		`"Aü€𝄞"`,
+		`"unpaired \ud834 surrogate \udd1e"`,
+		`"reversed \udd1e\ud834 pair"`,
+		`"surrogate then escape \ud834\n"`,
+		"\"bad \xff utf8 \xe2\x82\"",
+		"\"\\u0026 then bad \xff\"",
+		`"\x"`,
+		`"\u12"`,
+		`"\u12g4"`,
+		"\"raw \n newline\"",
+	} {
+		check(in)
+	}
+	if err := quick.Check(func(s string) bool {
+		b, err := json.Marshal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return check(string(b))
+	}, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReaderReset(t *testing.T) {
+	jr := NewDagJsonReader(strings.NewReader(`{"a":1,"b"`), Strict())
+	if err := jr.ReadObjectOpen(); err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range []string{`["x\n",-5,"` + strings.Repeat("y", 5000) + `"]`, `["ü",7,""]`} {
+		jr.Reset(strings.NewReader(in))
+		var n Node
+		if err := n.UnmarshalDagJSON(jr); err != nil {
+			t.Fatalf("%s: %s", in, err)
+		}
+		if err := jr.ReadEOF(); err != nil {
+			t.Fatal(err)
+		}
+		if jr.Offset() != int64(len(in)) {
+			t.Errorf("expected offset %d, got %d", len(in), jr.Offset())
+		}
+	}
+
+	// The reader stays strict.
+	jr.Reset(strings.NewReader(`[1, 2]`))
+	var n Node
+	if err := n.UnmarshalDagJSON(jr); err == nil {
+		t.Fatal("expected whitespace to be rejected after Reset")
+	}
+}
@@ -8,13 +8,15 @@ import (
 
 type JsonCid cid.Cid
 
-func (c JsonCid) MarshalDagJSON(w io.Writer) error {
-	jw := NewDagJsonWriter(w)
+func (c JsonCid) MarshalDagJSON(w io.Writer) (err error) {
+	jw := NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	return jw.WriteCid(cid.Cid(c))
 }
 
 func (c *JsonCid) UnmarshalDagJSON(r io.Reader) error {
 	jr := NewDagJsonReader(r)
+	defer jr.Release(r)
 	oc, err := jr.ReadCid()
 	if err != nil {
 		return err
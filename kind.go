@@ -0,0 +1,142 @@
+package typegen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Kind is the IPLD data model kind of a DAG-JSON value.
+type Kind uint8
+
+const (
+	KindNull Kind = iota
+	KindBool
+	KindInt
+	KindFloat
+	KindString
+	KindBytes
+	KindList
+	KindMap
+	KindLink
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindString:
+		return "string"
+	case KindBytes:
+		return "bytes"
+	case KindList:
+		return "list"
+	case KindMap:
+		return "map"
+	case KindLink:
+		return "link"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+// Kind returns the data model kind of the deferred value. Numbers with a
+// fraction or exponent are floats, and maps holding a single "/" entry are
+// links or bytes as described by the DAG-JSON spec.
+func (d *Deferred) Kind() (Kind, error) {
+	jr := NewDagJsonReader(bytes.NewReader(d.Raw))
+	defer jr.Release(nil)
+	typ, err := jr.PeekType()
+	if err != nil {
+		return 0, err
+	}
+	switch typ {
+	case "null":
+		return KindNull, nil
+	case "boolean":
+		return KindBool, nil
+	case "string":
+		return KindString, nil
+	case "array":
+		return KindList, nil
+	case "number":
+		n, err := jr.ReadNumberAsString(MaxLength)
+		if err != nil {
+			return 0, err
+		}
+		if strings.ContainsAny(n, ".eE") {
+			return KindFloat, nil
+		}
+		return KindInt, nil
+	case "object":
+		return objectKind(jr)
+	default:
+		return 0, fmt.Errorf("unknown JSON type: %s", typ)
+	}
+}
+
+// objectKind distinguishes links and bytes from plain maps.
+func objectKind(jr *DagJsonReader) (Kind, error) {
+	if err := jr.ReadObjectOpen(); err != nil {
+		return 0, err
+	}
+	if close, err := jr.PeekObjectClose(); err != nil || close {
+		return KindMap, err
+	}
+	k, err := jr.ReadString(MaxLength)
+	if err != nil {
+		return 0, err
+	}
+	if k != "/" {
+		return KindMap, nil
+	}
+	if err := jr.ReadObjectColon(); err != nil {
+		return 0, err
+	}
+	typ, err := jr.PeekType()
+	if err != nil {
+		return 0, err
+	}
+	kind := KindMap
+	switch typ {
+	case "string":
+		if _, err := jr.ReadString(MaxLength); err != nil {
+			return 0, err
+		}
+		kind = KindLink
+	case "object":
+		if err := jr.ReadObjectOpen(); err != nil {
+			return 0, err
+		}
+		if close, err := jr.PeekObjectClose(); err != nil || close {
+			return KindMap, err
+		}
+		k, err := jr.ReadString(MaxLength)
+		if err != nil || k != "bytes" {
+			return KindMap, err
+		}
+		if err := jr.ReadObjectColon(); err != nil {
+			return 0, err
+		}
+		if _, err := jr.ReadString(ByteArrayMaxLen * 2); err != nil {
+			return KindMap, nil
+		}
+		if close, err := jr.ReadObjectCloseOrComma(); err != nil || !close {
+			return KindMap, err
+		}
+		kind = KindBytes
+	default:
+		return KindMap, nil
+	}
+	// Anything other than a single "/" entry is a plain map.
+	if close, err := jr.ReadObjectCloseOrComma(); err != nil || !close {
+		return KindMap, err
+	}
+	return kind, nil
+}
@@ -0,0 +1,164 @@
+package typegen
+
+import (
+	"bytes"
+	"io"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+// ScanLinks calls cb with every link held in v. Types generated by this
+// package scan their fields directly; anything else is encoded and the
+// encoding scanned with ScanForLinks.
+func ScanLinks(v DagJsonMarshaler, cb func(cid.Cid)) error {
+	if s, ok := v.(DagJsonLinkScanner); ok {
+		return s.ScanDagJSONLinks(cb)
+	}
+	var buf bytes.Buffer
+	if err := v.MarshalDagJSON(&buf); err != nil {
+		return err
+	}
+	return ScanForLinks(&buf, cb)
+}
+
+// ScanForLinks reads a single DAG-JSON value from r and calls cb with every
+// link ({"/": "<cid>"}) found in it, without needing to know its type.
+func ScanForLinks(r io.Reader, cb func(cid.Cid)) error {
+	jr := NewDagJsonReader(r)
+	defer jr.Release(r)
+	return scanForLinks(jr, cb, 0)
+}
+
+func scanForLinks(jr *DagJsonReader, cb func(cid.Cid), depth int) error {
+	if depth > MaxNodeDepth {
+		return jr.errorf("value nested too deeply")
+	}
+
+	typ, err := jr.PeekType()
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case "object":
+		if err := jr.ReadObjectOpen(); err != nil {
+			return err
+		}
+		close, err := jr.PeekObjectClose()
+		if err != nil {
+			return err
+		}
+		if close {
+			return jr.ReadObjectClose()
+		}
+		for first := true; !close; first = false {
+			k, err := jr.ReadString(MaxLength)
+			if err != nil {
+				return err
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return err
+			}
+			if first && k == "/" {
+				typ, err := jr.PeekType()
+				if err != nil {
+					return err
+				}
+				if typ == "string" {
+					s, err := jr.ReadString(MaxLength)
+					if err != nil {
+						return err
+					}
+					close, err = jr.ReadObjectCloseOrComma()
+					if err != nil {
+						return err
+					}
+					if close {
+						c, err := jr.parseLink(s)
+						if err != nil {
+							return jr.fail(err)
+						}
+						cb(c)
+					}
+					continue
+				}
+			}
+			if err := scanForLinks(jr, cb, depth+1); err != nil {
+				return err
+			}
+			close, err = jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "array":
+		if err := jr.ReadArrayOpen(); err != nil {
+			return err
+		}
+		close, err := jr.PeekArrayClose()
+		if err != nil {
+			return err
+		}
+		if close {
+			return jr.ReadArrayClose()
+		}
+		for !close {
+			if err := scanForLinks(jr, cb, depth+1); err != nil {
+				return err
+			}
+			close, err = jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return jr.DiscardType()
+	}
+}
+
+// ScanDagJSONLinks calls cb with every link in the deferred value.
+func (d *Deferred) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if d == nil || d.Raw == nil {
+		return nil
+	}
+	return ScanForLinks(bytes.NewReader(d.Raw), cb)
+}
+
+// ScanDagJSONLinks calls cb with every link in the node.
+func (n *Node) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if n == nil {
+		return nil
+	}
+	switch n.kind {
+	case KindLink:
+		cb(n.link)
+	case KindList:
+		for i := range n.list {
+			if err := n.list[i].ScanDagJSONLinks(cb); err != nil {
+				return err
+			}
+		}
+	case KindMap:
+		for _, k := range n.Keys() {
+			v := n.m[k]
+			if err := v.ScanDagJSONLinks(cb); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ScanDagJSONLinks calls cb with the link.
+func (c *JsonCid) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if c != nil && cid.Cid(*c).Defined() {
+		cb(cid.Cid(*c))
+	}
+	return nil
+}
+
+// ScanDagJSONLinks does nothing, as times hold no links.
+func (jt *DagJsonTime) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	return nil
+}
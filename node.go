@@ -0,0 +1,445 @@
+package typegen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	cid "github.com/ipfs/go-cid"
+)
+
+// MaxNodeDepth is the maximum nesting of lists and maps accepted when decoding
+// a Node.
+const MaxNodeDepth = 1024
+
+// Node is a dynamically typed DAG-JSON value. The zero value is null.
+//
+// Nodes can be used as field types in generated structs to carry values whose
+// shape is not known ahead of time.
+type Node struct {
+	kind Kind
+
+	b bool
+	// Integers are held as a sign and magnitude so that the full range of
+	// both int64 and uint64 can be represented.
+	neg  bool
+	mag  uint64
+	f    float64
+	s    string
+	by   []byte
+	list []Node
+	m    map[string]Node
+	link cid.Cid
+}
+
+func NewNull() Node {
+	return Node{}
+}
+
+func NewBool(b bool) Node {
+	return Node{kind: KindBool, b: b}
+}
+
+func NewInt(i int64) Node {
+	if i < 0 {
+		return Node{kind: KindInt, neg: true, mag: uint64(-(i + 1)) + 1}
+	}
+	return Node{kind: KindInt, mag: uint64(i)}
+}
+
+func NewUint(u uint64) Node {
+	return Node{kind: KindInt, mag: u}
+}
+
+func NewFloat(f float64) Node {
+	return Node{kind: KindFloat, f: f}
+}
+
+func NewString(s string) Node {
+	return Node{kind: KindString, s: s}
+}
+
+func NewBytes(b []byte) Node {
+	return Node{kind: KindBytes, by: b}
+}
+
+func NewList(l []Node) Node {
+	return Node{kind: KindList, list: l}
+}
+
+func NewMap(m map[string]Node) Node {
+	return Node{kind: KindMap, m: m}
+}
+
+func NewLink(c cid.Cid) Node {
+	return Node{kind: KindLink, link: c}
+}
+
+// Kind returns the data model kind of the node.
+func (n Node) Kind() Kind {
+	return n.kind
+}
+
+func (n Node) IsNull() bool {
+	return n.kind == KindNull
+}
+
+func (n Node) kindError(want Kind) error {
+	return fmt.Errorf("expected %s node but found %s", want, n.kind)
+}
+
+func (n Node) AsBool() (bool, error) {
+	if n.kind != KindBool {
+		return false, n.kindError(KindBool)
+	}
+	return n.b, nil
+}
+
+// AsInt returns the value of an int node, failing if it does not fit in an
+// int64.
+func (n Node) AsInt() (int64, error) {
+	if n.kind != KindInt {
+		return 0, n.kindError(KindInt)
+	}
+	if n.neg {
+		if n.mag > 1<<63 {
+			return 0, fmt.Errorf("value -%d out of range for int64", n.mag)
+		}
+		return -int64(n.mag-1) - 1, nil
+	}
+	if n.mag > math.MaxInt64 {
+		return 0, fmt.Errorf("value %d out of range for int64", n.mag)
+	}
+	return int64(n.mag), nil
+}
+
+// AsUint returns the value of an int node, failing if it is negative.
+func (n Node) AsUint() (uint64, error) {
+	if n.kind != KindInt {
+		return 0, n.kindError(KindInt)
+	}
+	if n.neg {
+		return 0, fmt.Errorf("value -%d out of range for uint64", n.mag)
+	}
+	return n.mag, nil
+}
+
+func (n Node) AsFloat() (float64, error) {
+	if n.kind != KindFloat {
+		return 0, n.kindError(KindFloat)
+	}
+	return n.f, nil
+}
+
+func (n Node) AsString() (string, error) {
+	if n.kind != KindString {
+		return "", n.kindError(KindString)
+	}
+	return n.s, nil
+}
+
+func (n Node) AsBytes() ([]byte, error) {
+	if n.kind != KindBytes {
+		return nil, n.kindError(KindBytes)
+	}
+	return n.by, nil
+}
+
+func (n Node) AsList() ([]Node, error) {
+	if n.kind != KindList {
+		return nil, n.kindError(KindList)
+	}
+	return n.list, nil
+}
+
+func (n Node) AsMap() (map[string]Node, error) {
+	if n.kind != KindMap {
+		return nil, n.kindError(KindMap)
+	}
+	return n.m, nil
+}
+
+func (n Node) AsLink() (cid.Cid, error) {
+	if n.kind != KindLink {
+		return cid.Undef, n.kindError(KindLink)
+	}
+	return n.link, nil
+}
+
+// Length returns the number of entries in a list or map node, and -1 for
+// nodes of any other kind.
+func (n Node) Length() int {
+	switch n.kind {
+	case KindList:
+		return len(n.list)
+	case KindMap:
+		return len(n.m)
+	default:
+		return -1
+	}
+}
+
+// Keys returns the keys of a map node in canonical order.
+func (n Node) Keys() []string {
+	keys := make([]string, 0, len(n.m))
+	for k := range n.m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup returns the entry of a map node with the given key.
+func (n Node) Lookup(key string) (Node, bool) {
+	v, ok := n.m[key]
+	return v, ok
+}
+
+// Index returns the element of a list node at position i.
+func (n Node) Index(i int) (Node, bool) {
+	if i < 0 || i >= len(n.list) {
+		return Node{}, false
+	}
+	return n.list[i], true
+}
+
+func (n *Node) MarshalDagJSON(w io.Writer) (err error) {
+	jw := NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if n == nil {
+		return jw.WriteNull()
+	}
+	switch n.kind {
+	case KindNull:
+		return jw.WriteNull()
+	case KindBool:
+		return jw.WriteBool(n.b)
+	case KindInt:
+		if n.neg {
+			if err := jw.writeByte('-'); err != nil {
+				return err
+			}
+		}
+		return jw.WriteUint64(n.mag)
+	case KindFloat:
+		return jw.WriteFloat64(n.f)
+	case KindString:
+		return jw.WriteString(n.s)
+	case KindBytes:
+		return jw.WriteBytes(n.by)
+	case KindLink:
+		return jw.WriteCid(n.link)
+	case KindList:
+		if err := jw.WriteArrayOpen(); err != nil {
+			return err
+		}
+		for i := range n.list {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return err
+				}
+			}
+			if err := n.list[i].MarshalDagJSON(jw); err != nil {
+				return err
+			}
+		}
+		return jw.WriteArrayClose()
+	case KindMap:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return err
+		}
+		for i, k := range n.Keys() {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return err
+				}
+			}
+			if err := jw.WriteString(k); err != nil {
+				return err
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return err
+			}
+			v := n.m[k]
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return err
+			}
+		}
+		return jw.WriteObjectClose()
+	default:
+		return fmt.Errorf("cannot marshal node of unknown kind %s", n.kind)
+	}
+}
+
+func (n *Node) UnmarshalDagJSON(r io.Reader) error {
+	jr := NewDagJsonReader(r)
+	defer jr.Release(r)
+	return n.unmarshal(jr, 0)
+}
+
+func (n *Node) unmarshal(jr *DagJsonReader, depth int) error {
+	if depth > MaxNodeDepth {
+		return jr.errorf("node nested too deeply")
+	}
+
+	typ, err := jr.PeekType()
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case "null":
+		if err := jr.ReadNull(); err != nil {
+			return err
+		}
+		*n = NewNull()
+	case "boolean":
+		b, err := jr.ReadBool()
+		if err != nil {
+			return err
+		}
+		*n = NewBool(b)
+	case "string":
+		s, err := jr.ReadString(ByteArrayMaxLen)
+		if err != nil {
+			return err
+		}
+		*n = NewString(s)
+	case "number":
+		s, err := jr.ReadNumberAsString(MaxLength)
+		if err != nil {
+			return err
+		}
+		if strings.ContainsAny(s, ".eE") {
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return jr.fail(err)
+			}
+			*n = NewFloat(f)
+		} else if mag, ok := strings.CutPrefix(s, "-"); ok {
+			u, err := strconv.ParseUint(mag, 10, 64)
+			if err != nil {
+				return jr.fail(err)
+			}
+			*n = Node{kind: KindInt, neg: u != 0, mag: u}
+		} else {
+			u, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return jr.fail(err)
+			}
+			*n = NewUint(u)
+		}
+	case "array":
+		if err := jr.ReadArrayOpen(); err != nil {
+			return err
+		}
+		list := []Node{}
+		close, err := jr.PeekArrayClose()
+		if err != nil {
+			return err
+		}
+		if close {
+			if err := jr.ReadArrayClose(); err != nil {
+				return err
+			}
+		}
+		for i := 0; !close; i++ {
+			if i == MaxLength {
+				return jr.errorf("list too large")
+			}
+			var v Node
+			if err := v.unmarshal(jr, depth+1); err != nil {
+				return jr.PathError(IndexPath(i), err)
+			}
+			list = append(list, v)
+			close, err = jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return err
+			}
+		}
+		*n = NewList(list)
+	case "object":
+		return n.unmarshalObject(jr, depth)
+	default:
+		return jr.errorf("unknown JSON type: %s", typ)
+	}
+	return nil
+}
+
+// unmarshalObject decodes a map, or a link or bytes if the map holds nothing
+// but a "/" entry of the right shape.
+func (n *Node) unmarshalObject(jr *DagJsonReader, depth int) error {
+	if err := jr.ReadObjectOpen(); err != nil {
+		return err
+	}
+	m := map[string]Node{}
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return err
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return err
+		}
+	}
+	for i := 0; !close; i++ {
+		if i == MaxLength {
+			return jr.errorf("map too large")
+		}
+		k, err := jr.ReadString(MaxLength)
+		if err != nil {
+			return err
+		}
+		if _, ok := m[k]; ok {
+			return jr.errorf("duplicate map key %q", k)
+		}
+		if err := jr.ReadObjectColon(); err != nil {
+			return err
+		}
+		var v Node
+		if err := v.unmarshal(jr, depth+1); err != nil {
+			return jr.PathError(KeyPath(k), err)
+		}
+		m[k] = v
+		close, err = jr.ReadObjectCloseOrComma()
+		if err != nil {
+			return err
+		}
+	}
+
+	if v, ok := m["/"]; ok && len(m) == 1 {
+		switch v.kind {
+		case KindString:
+			c, err := jr.parseLink(v.s)
+			if err != nil {
+				return jr.fail(err)
+			}
+			*n = NewLink(c)
+			return nil
+		case KindMap:
+			if bv, ok := v.m["bytes"]; ok && len(v.m) == 1 && bv.kind == KindString {
+				b, err := base64.RawStdEncoding.DecodeString(bv.s)
+				if err != nil {
+					return jr.fail(err)
+				}
+				*n = NewBytes(b)
+				return nil
+			}
+		}
+	}
+	*n = NewMap(m)
+	return nil
+}
+
+// Node decodes the deferred value into a Node.
+func (d *Deferred) Node() (Node, error) {
+	var n Node
+	if err := n.UnmarshalDagJSON(bytes.NewReader(d.Raw)); err != nil {
+		return Node{}, err
+	}
+	return n, nil
+}
@@ -0,0 +1,166 @@
+package typegen
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestNodeRoundtrip(t *testing.T) {
+	for _, in := range []string{
+		`null`,
+		`true`,
+		`0`,
+		`-1`,
+		`18446744073709551615`,
+		`-9223372036854775808`,
+		`1.5`,
+		`1e+21`,
+		`"hello"`,
+		`{"/":{"bytes":"AQID"}}`,
+		`{"/":"bafkqaaa"}`,
+		`[]`,
+		`[1,"a",[null]]`,
+		`{}`,
+		`{"a":{"b":[1,2]},"b":false}`,
+		`{"/":"bafkqaaa","a":1}`,
+		`{"/":1}`,
+	} {
+		var n Node
+		if err := n.UnmarshalDagJSON(strings.NewReader(in)); err != nil {
+			t.Fatalf("%s: %s", in, err)
+		}
+		var buf bytes.Buffer
+		if err := n.MarshalDagJSON(&buf); err != nil {
+			t.Fatalf("%s: %s", in, err)
+		}
+		if buf.String() != in {
+			t.Errorf("expected %s, got %s", in, buf.String())
+		}
+	}
+}
+
+func TestNodeKinds(t *testing.T) {
+	var n Node
+	if err := n.UnmarshalDagJSON(strings.NewReader(`{"b":{"/":{"bytes":"AQID"}},"f":2.5,"i":-3,"l":[{"/":"bafkqaaa"}],"s":"x","u":18446744073709551615}`)); err != nil {
+		t.Fatal(err)
+	}
+	if n.Kind() != KindMap || n.Length() != 6 {
+		t.Fatalf("expected a map of 6 entries, got %s of %d", n.Kind(), n.Length())
+	}
+	if keys := n.Keys(); strings.Join(keys, ",") != "b,f,i,l,s,u" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	b, _ := n.Lookup("b")
+	if v, err := b.AsBytes(); err != nil || !bytes.Equal(v, []byte{1, 2, 3}) {
+		t.Errorf("unexpected bytes %v: %v", v, err)
+	}
+	f, _ := n.Lookup("f")
+	if v, err := f.AsFloat(); err != nil || v != 2.5 {
+		t.Errorf("unexpected float %v: %v", v, err)
+	}
+	i, _ := n.Lookup("i")
+	if v, err := i.AsInt(); err != nil || v != -3 {
+		t.Errorf("unexpected int %v: %v", v, err)
+	}
+	if _, err := i.AsUint(); err == nil {
+		t.Error("expected an error reading a negative int as uint")
+	}
+	u, _ := n.Lookup("u")
+	if v, err := u.AsUint(); err != nil || v != math.MaxUint64 {
+		t.Errorf("unexpected uint %v: %v", v, err)
+	}
+	if _, err := u.AsInt(); err == nil {
+		t.Error("expected an error reading a large uint as int")
+	}
+	l, _ := n.Lookup("l")
+	link, ok := l.Index(0)
+	if !ok || link.Kind() != KindLink {
+		t.Fatalf("expected a link in the list, got %s", link.Kind())
+	}
+	if c, err := link.AsLink(); err != nil || c.String() != "bafkqaaa" {
+		t.Errorf("unexpected link %v: %v", c, err)
+	}
+	s, _ := n.Lookup("s")
+	if _, err := s.AsInt(); err == nil {
+		t.Error("expected an error reading a string as int")
+	}
+	if _, ok := n.Lookup("missing"); ok {
+		t.Error("found a missing key")
+	}
+}
+
+func TestNodeConstructors(t *testing.T) {
+	n := NewMap(map[string]Node{
+		"z": NewList([]Node{NewInt(math.MinInt64), NewUint(7), NewNull()}),
+		"a": NewBool(true),
+		"m": NewString("s"),
+		"f": NewFloat(1),
+	})
+	var buf bytes.Buffer
+	if err := n.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"a":true,"f":1.0,"m":"s","z":[-9223372036854775808,7,null]}`
+	if buf.String() != expected {
+		t.Fatalf("expected %s, got %s", expected, buf.String())
+	}
+
+	z, _ := n.Lookup("z")
+	min, _ := z.Index(0)
+	if v, err := min.AsInt(); err != nil || v != math.MinInt64 {
+		t.Errorf("unexpected int %v: %v", v, err)
+	}
+}
+
+func TestNodeErrors(t *testing.T) {
+	for _, in := range []string{
+		`{"a":1,"a":2}`,
+		`{"/":"notacid"}`,
+		`-18446744073709551616`,
+		strings.Repeat("[", MaxNodeDepth+2) + strings.Repeat("]", MaxNodeDepth+2),
+	} {
+		var n Node
+		if err := n.UnmarshalDagJSON(strings.NewReader(in)); err == nil {
+			t.Errorf("expected an error decoding %.20s", in)
+		}
+	}
+}
+
+func TestNodeDecodeError(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		path   string
+		offset int64
+	}{
+		{`[0,{"a":[true,"x",nul]}]`, `[1]["a"][2]`, 22},
+		{`{"a":{"b":"c","b":1}}`, `["a"]`, 17},
+		{`[1] 2`, ``, 4},
+	} {
+		var n Node
+		jr := NewDagJsonReader(strings.NewReader(tc.in))
+		err := n.UnmarshalDagJSON(jr)
+		if err == nil {
+			err = jr.ReadEOF()
+		}
+		var de *DecodeError
+		if !errors.As(err, &de) || de.Path != tc.path || de.Offset != tc.offset {
+			t.Errorf("%s: unexpected error %v", tc.in, err)
+		}
+	}
+}
+
+func TestDeferredNode(t *testing.T) {
+	d := Deferred{Raw: []byte(`{"a":[1]}`)}
+	n, err := d.Node()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := n.Lookup("a")
+	if a.Kind() != KindList || a.Length() != 1 {
+		t.Fatalf("unexpected node %s", a.Kind())
+	}
+}
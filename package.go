@@ -2,48 +2,100 @@ package typegen
 
 import (
 	"fmt"
+	"go/token"
 	"reflect"
 	"sort"
 	"strings"
-	"sync"
+	"unicode"
 )
 
 var (
-	knownPackageNamesMu sync.Mutex
-	pkgNameToPkgPath    = make(map[string]string)
-	pkgPathToPkgName    = make(map[string]string)
-
 	defaultImports = []Import{
 		{Name: "jsg", PkgPath: "github.com/alanshaw/dag-json-gen"},
 		{Name: "cid", PkgPath: "github.com/ipfs/go-cid"},
 	}
+
+	// headerImports are the standard packages imported by every generated
+	// file under their own names.
+	headerImports = []string{"bytes", "errors", "fmt", "io", "math", "sort", "strconv"}
 )
 
-func init() {
+// importScope holds the names by which the packages imported by a generated
+// file are known, so that each file is generated independently of any other.
+// A nil scope knows only the default imports, and names other packages by
+// their default name.
+type importScope struct {
+	pathToName map[string]string
+	nameToPath map[string]string
+}
+
+// newImportScope returns a scope holding the default imports and the given
+// aliases, a map of import paths to names.
+func newImportScope(aliases map[string]string) (*importScope, error) {
+	s := &importScope{
+		pathToName: make(map[string]string),
+		nameToPath: make(map[string]string),
+	}
+	for _, path := range headerImports {
+		s.add(path, path)
+	}
 	for _, imp := range defaultImports {
-		if was, conflict := pkgNameToPkgPath[imp.Name]; conflict {
-			panic(fmt.Sprintf("reused pkg name %s for %s and %s", imp.Name, imp.PkgPath, was))
+		s.add(imp.PkgPath, imp.Name)
+	}
+	paths := make([]string, 0, len(aliases))
+	for path := range aliases {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		name := aliases[path]
+		if !token.IsIdentifier(name) || name == "_" {
+			return nil, fmt.Errorf("invalid import alias %q for %s", name, path)
 		}
-		if _, conflict := pkgPathToPkgName[imp.Name]; conflict {
-			panic(fmt.Sprintf("duplicate default import %s", imp.PkgPath))
+		if was, ok := s.pathToName[path]; ok && was != name {
+			return nil, fmt.Errorf("%s is always imported as %s", path, was)
 		}
-		pkgNameToPkgPath[imp.Name] = imp.PkgPath
-		pkgPathToPkgName[imp.PkgPath] = imp.Name
+		if was, ok := s.nameToPath[name]; ok && was != path {
+			return nil, fmt.Errorf("import alias %s for %s is already used for %s", name, path, was)
+		}
+		s.add(path, name)
 	}
+	return s, nil
 }
 
-func resolvePkgName(path, typeName string) string {
-	parts := strings.Split(typeName, ".")
-	if len(parts) != 2 {
-		panic(fmt.Sprintf("expected type to have a package name: %s", typeName))
+func (s *importScope) add(path, name string) {
+	s.pathToName[path] = name
+	s.nameToPath[name] = path
+}
+
+// reserve names the packages in defaults, a map of import paths to default
+// names, in order of path, so that the names chosen when two packages share a
+// default name don't depend on the order in which types are generated.
+func (s *importScope) reserve(defaults map[string]string) {
+	paths := make([]string, 0, len(defaults))
+	for path := range defaults {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		s.name(path, defaults[path])
 	}
-	defaultName := parts[0]
+}
 
-	knownPackageNamesMu.Lock()
-	defer knownPackageNamesMu.Unlock()
+// name returns the name to import the package at path by, which is
+// defaultName unless another package in the scope has that name.
+func (s *importScope) name(path, defaultName string) string {
+	if s == nil {
+		for _, imp := range defaultImports {
+			if imp.PkgPath == path {
+				return imp.Name
+			}
+		}
+		return defaultName
+	}
 
 	// Check for a known name and use it.
-	if name, ok := pkgPathToPkgName[path]; ok {
+	if name, ok := s.pathToName[path]; ok {
 		return name
 	}
 
@@ -53,34 +105,127 @@ func resolvePkgName(path, typeName string) string {
 		if i > 0 {
 			tryName = fmt.Sprintf("%s%d", defaultName, i)
 		}
-		if _, taken := pkgNameToPkgPath[tryName]; !taken {
-			pkgNameToPkgPath[tryName] = path
-			pkgPathToPkgName[path] = tryName
+		if _, taken := s.nameToPath[tryName]; !taken {
+			s.add(path, tryName)
 			return tryName
 		}
 	}
-
 }
 
 type Import struct {
 	Name, PkgPath string
 }
 
-func ImportsForType(currPkg string, t reflect.Type) []Import {
+// ImportsForType returns the imports needed to name t in the package currPkg,
+// by their default names.
+func ImportsForType(currPkg string, t Type) []Import {
+	return (*importScope)(nil).importsForType(currPkg, t)
+}
+
+func (s *importScope) importsForType(currPkg string, t Type) []Import {
+	var imports []Import
+	typePackages(currPkg, t, func(path, name string, _ bool) {
+		imports = append(imports, Import{PkgPath: path, Name: s.name(path, name)})
+	})
+	return dedupImports(imports)
+}
+
+// typePackages calls add with the import path and default name of each package
+// other than currPkg that is named in t. The name is exact for named types, and
+// guessed for type arguments, as reflect only gives the import path of their
+// packages.
+func typePackages(currPkg string, t Type, add func(path, name string, exact bool)) {
 	switch t.Kind() {
 	case reflect.Array, reflect.Slice, reflect.Ptr:
-		return ImportsForType(currPkg, t.Elem())
+		typePackages(currPkg, t.Elem(), add)
 	case reflect.Map:
-		return dedupImports(append(ImportsForType(currPkg, t.Key()), ImportsForType(currPkg, t.Elem())...))
+		typePackages(currPkg, t.Key(), add)
+		typePackages(currPkg, t.Elem(), add)
 	default:
-		path := t.PkgPath()
-		if path == "" || path == currPkg {
-			// built-in or in current package.
-			return nil
+		if i := strings.IndexByte(t.Name(), '['); i >= 0 {
+			qualifyTypeNames(t.Name()[i:], func(path, name string) string {
+				if path != currPkg {
+					add(path, defaultPkgName(path), false)
+				}
+				return name
+			})
+		}
+		if path := t.PkgPath(); path != "" && path != currPkg {
+			add(path, pkgNameOf(t), true)
+		}
+	}
+}
+
+// pkgNameOf returns the name of the package defining the named type t, from
+// its string form, which is like "pkg.Name" or "pkg.Name[path/to/arg.Type]".
+func pkgNameOf(t Type) string {
+	s := t.String()
+	i := strings.IndexByte(s, '.')
+	if i < 0 {
+		panic(fmt.Sprintf("expected type to have a package name: %s", s))
+	}
+	return s[:i]
+}
+
+// defaultPkgName guesses the name of the package at path, for type arguments,
+// which are only known by the import path of their package. It only has to be
+// a valid identifier, as imports are always given a name.
+func defaultPkgName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		// A major version suffix.
+		name = elems[len(elems)-2]
+	}
+	if i := strings.IndexByte(name, '.'); i > 0 {
+		// As in gopkg.in/yaml.v3.
+		name = name[:i]
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
 		}
+		return -1
+	}, name)
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		name = "pkg" + name
+	}
+	return name
+}
 
-		return []Import{{PkgPath: path, Name: resolvePkgName(path, t.String())}}
+// qualifyTypeNames rewrites the package qualified type names in s, the string
+// form of a type from reflect, where they are qualified by the package's full
+// import path, as in "map[string]*path/to/pkg.Type", with the result of
+// qualify.
+func qualifyTypeNames(s string, qualify func(path, name string) string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		end := strings.IndexAny(s, "[]*(), {};")
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			b.WriteByte(s[0])
+			if s[0] == ',' && !strings.HasPrefix(s[1:], " ") {
+				// reflect leaves out the space that gofmt adds.
+				b.WriteByte(' ')
+			}
+			s = s[1:]
+			continue
+		}
+		tok := s[:end]
+		s = s[end:]
+		if strings.HasPrefix(tok, "...") {
+			b.WriteString("...")
+			tok = tok[3:]
+		}
+		if i := strings.LastIndexByte(tok, '.'); i >= 0 {
+			tok = qualify(tok[:i], tok[i+1:])
+		}
+		b.WriteString(tok)
 	}
+	return b.String()
 }
 
 func dedupImports(imps []Import) []Import {
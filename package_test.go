@@ -0,0 +1,135 @@
+package typegen
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	texttemplate "text/template"
+)
+
+type textTemplateOnly struct {
+	T *texttemplate.Template
+}
+
+type bothTemplates struct {
+	T *texttemplate.Template
+	H *htmltemplate.Template
+}
+
+func generate(t *testing.T, g Gen, types ...interface{}) string {
+	t.Helper()
+	fname := filepath.Join(t.TempDir(), "gen.go")
+	if err := g.WriteMapEncodersToFile(fname, "typegen", types...); err != nil {
+		t.Fatal(err)
+	}
+	out, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestImportScope(t *testing.T) {
+	// The names chosen for one file don't depend on other files.
+	for i := 0; i < 2; i++ {
+		both := generate(t, Gen{}, textTemplateOnly{}, bothTemplates{})
+		if !strings.Contains(both, `template "html/template"`) || !strings.Contains(both, `template1 "text/template"`) {
+			t.Fatalf("expected packages to be named in order of path:\n%s", both)
+		}
+		text := generate(t, Gen{}, textTemplateOnly{})
+		if !strings.Contains(text, `template "text/template"`) {
+			t.Fatalf("expected text/template to be named template:\n%s", text)
+		}
+	}
+
+	// Nor on the order of types, or other generators running at the same time.
+	expected := generate(t, Gen{}, bothTemplates{}, textTemplateOnly{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fname := filepath.Join(t.TempDir(), "gen.go")
+			if err := (Gen{}).WriteMapEncodersToFile(fname, "typegen", bothTemplates{}, textTemplateOnly{}); err != nil {
+				t.Error(err)
+				return
+			}
+			if out, _ := os.ReadFile(fname); string(out) != expected {
+				t.Error("concurrent generation produced different output")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Type infos shared by files generated at the same time are left as is.
+	shared, err := ParseTypeInfo(bothTemplates{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := make([]string, 4)
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var buf bytes.Buffer
+			typeInfos, err := (Gen{}).PrintHeaderAndUtilityMethods(&buf, "typegen", []*GenTypeInfo{shared})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if err := (Gen{}).GenMapEncodersForType(typeInfos[0], &buf); err != nil {
+				t.Error(err)
+			}
+			outputs[i] = buf.String()
+		}()
+	}
+	wg.Wait()
+	if shared.scope != nil {
+		t.Fatal("expected the shared type info not to be given an import scope")
+	}
+	for _, out := range outputs[1:] {
+		if out != outputs[0] {
+			t.Fatal("generating from shared type infos produced different output")
+		}
+	}
+
+	// Encoders generated from the returned type infos use the header's names.
+	text, err := ParseTypeInfo(textTemplateOnly{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	scoped, err := (Gen{}).PrintHeaderAndUtilityMethods(&buf, "typegen", []*GenTypeInfo{shared, text})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := (Gen{}).GenMapEncodersForType(scoped[1], &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "new(template1.Template)") {
+		t.Fatalf("expected text/template to be named as in the header:\n%s", buf.String())
+	}
+
+	aliased := generate(t, Gen{ImportAliases: map[string]string{"text/template": "ttpl"}}, bothTemplates{})
+	if !strings.Contains(aliased, `ttpl "text/template"`) || !strings.Contains(aliased, "new(ttpl.Template)") || !strings.Contains(aliased, `template "html/template"`) {
+		t.Fatalf("expected the configured alias to be used:\n%s", aliased)
+	}
+
+	for _, aliases := range []map[string]string{
+		{"text/template": "1x"},
+		{"text/template": "jsg"},
+		{"text/template": "fmt"},
+		{"github.com/ipfs/go-cid": "gocid"},
+		{"text/template": "tpl", "html/template": "tpl"},
+	} {
+		fname := filepath.Join(t.TempDir(), "gen.go")
+		if err := (Gen{ImportAliases: aliases}).WriteMapEncodersToFile(fname, "typegen", bothTemplates{}); err == nil {
+			t.Errorf("%v: expected an error", aliases)
+		}
+	}
+}
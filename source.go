@@ -0,0 +1,491 @@
+package typegen
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MarkerComment selects a type for generation when it appears on a line of
+// its own in the type's doc comment.
+const MarkerComment = "//dagjsongen:generate"
+
+// generatedPrefix starts the header of every file written by the generator.
+const generatedPrefix = "// Code generated by github.com/alanshaw/dag-json-gen."
+
+// SourcePackage is a Go package loaded from source. Types are described from
+// their declarations, so codecs can be generated for a package that does not
+// currently compile, for instance because its generated code is stale.
+type SourcePackage struct {
+	// Name is the package name and Path its import path.
+	Name string
+	Path string
+	Dir  string
+
+	pkg    *types.Package
+	fset   *token.FileSet
+	files  []*ast.File
+	marked []string
+	errs   []error
+}
+
+// LoadPackage parses and type checks the package in dir. Files previously
+// written by the generator are skipped, and type errors are tolerated unless
+// they affect a type that is asked for.
+func LoadPackage(dir string) (*SourcePackage, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find package in %s: %w", dir, err)
+	}
+	pkgPath, err := importPathForDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &SourcePackage{Name: bp.Name, Path: pkgPath, Dir: dir}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		fname := filepath.Join(dir, name)
+		src, err := os.ReadFile(fname)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.HasPrefix(src, []byte(generatedPrefix)) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, fname, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+		p.marked = append(p.marked, markedTypes(f)...)
+	}
+
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			p.errs = append(p.errs, err)
+		},
+	}
+	// Errors are collected above and only reported for the types used.
+	p.pkg, _ = conf.Check(pkgPath, fset, files, nil)
+	p.fset, p.files = fset, files
+	return p, nil
+}
+
+// importPathForDir works out the import path of the package in dir from the
+// enclosing module.
+func importPathForDir(dir string) (string, error) {
+	for mdir := dir; ; {
+		data, err := os.ReadFile(filepath.Join(mdir, "go.mod"))
+		if err == nil {
+			modPath, err := modulePath(data)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", filepath.Join(mdir, "go.mod"), err)
+			}
+			rel, err := filepath.Rel(mdir, dir)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(modPath, filepath.ToSlash(rel)), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(mdir)
+		if parent == mdir {
+			return "", fmt.Errorf("no go.mod found for %s", dir)
+		}
+		mdir = parent
+	}
+}
+
+func modulePath(gomod []byte) (string, error) {
+	s := bufio.NewScanner(bytes.NewReader(gomod))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if rest, ok := strings.CutPrefix(line, "module"); ok {
+			rest = strings.TrimSpace(rest)
+			if unquoted, err := strconv.Unquote(rest); err == nil {
+				rest = unquoted
+			}
+			if rest != "" {
+				return rest, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("missing module declaration")
+}
+
+// markedTypes returns the names of the types declared in f whose doc comment
+// holds the marker comment.
+func markedTypes(f *ast.File) []string {
+	var names []string
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gd.Specs) == 1 {
+				doc = gd.Doc
+			}
+			if doc == nil {
+				continue
+			}
+			for _, c := range doc.List {
+				if strings.TrimSpace(c.Text) == MarkerComment {
+					names = append(names, ts.Name.Name)
+					break
+				}
+			}
+		}
+	}
+	return names
+}
+
+// MarkedTypes returns the names of the types marked for generation with
+// MarkerComment, in the order they are declared.
+func (p *SourcePackage) MarkedTypes() []string {
+	return p.marked
+}
+
+// TypeInfo describes the named type for generation. The result can be passed
+// to the WriteEncodersToFile functions in place of a value of the type. Generic
+// types must be instantiated, as in "Page[Item]" or "Page[*big.Int]", where
+// packages are named as they are imported by the package's files.
+func (p *SourcePackage) TypeInfo(name string) (*GenTypeInfo, error) {
+	t, err := p.lookupType(name)
+	if err != nil {
+		return nil, err
+	}
+	if n, ok := t.(*types.Named); ok && n.TypeParams().Len() > 0 && n.TypeArgs().Len() == 0 {
+		return nil, fmt.Errorf("generic type %s must be instantiated, as in %s[...]", name, name)
+	}
+	if err := p.checkValid(t, map[types.Type]bool{}); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return ParseType(SourceType(t))
+}
+
+// lookupType finds the type declared with the given name, or evaluates an
+// instantiation of a generic type in the scope of each file in turn.
+func (p *SourcePackage) lookupType(name string) (types.Type, error) {
+	if obj, ok := p.pkg.Scope().Lookup(name).(*types.TypeName); ok {
+		return obj.Type(), nil
+	}
+	if strings.Contains(name, "[") {
+		for _, f := range p.files {
+			tv, err := types.Eval(p.fset, p.pkg, f.Package, name)
+			if err == nil && tv.IsType() {
+				return tv.Type, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("type %s not found in package %s", name, p.Path)
+}
+
+// TypeInfos describes each of the named types, or the marked types if no
+// names are given.
+func (p *SourcePackage) TypeInfos(names ...string) ([]*GenTypeInfo, error) {
+	if len(names) == 0 {
+		names = p.marked
+	}
+	gtis := make([]*GenTypeInfo, 0, len(names))
+	for _, name := range names {
+		gti, err := p.TypeInfo(name)
+		if err != nil {
+			return nil, err
+		}
+		gtis = append(gtis, gti)
+	}
+	return gtis, nil
+}
+
+// checkValid fails if type checking could not work out part of t, reporting
+// the first type error in the package as the likely cause.
+func (p *SourcePackage) checkValid(t types.Type, seen map[types.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	switch u := types.Unalias(t).Underlying().(type) {
+	case *types.Basic:
+		if u.Kind() == types.Invalid {
+			if len(p.errs) > 0 {
+				return p.errs[0]
+			}
+			return fmt.Errorf("invalid type")
+		}
+	case *types.Pointer:
+		return p.checkValid(u.Elem(), seen)
+	case *types.Slice:
+		return p.checkValid(u.Elem(), seen)
+	case *types.Array:
+		return p.checkValid(u.Elem(), seen)
+	case *types.Map:
+		if err := p.checkValid(u.Key(), seen); err != nil {
+			return err
+		}
+		return p.checkValid(u.Elem(), seen)
+	case *types.Struct:
+		for i := 0; i < u.NumFields(); i++ {
+			if err := p.checkValid(u.Field(i).Type(), seen); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type sourceType struct {
+	t types.Type
+}
+
+// SourceType returns the Type for a type found by type checking source code.
+func SourceType(t types.Type) Type {
+	return sourceType{types.Unalias(t)}
+}
+
+var basicKinds = map[types.BasicKind]reflect.Kind{
+	types.Bool:          reflect.Bool,
+	types.Int:           reflect.Int,
+	types.Int8:          reflect.Int8,
+	types.Int16:         reflect.Int16,
+	types.Int32:         reflect.Int32,
+	types.Int64:         reflect.Int64,
+	types.Uint:          reflect.Uint,
+	types.Uint8:         reflect.Uint8,
+	types.Uint16:        reflect.Uint16,
+	types.Uint32:        reflect.Uint32,
+	types.Uint64:        reflect.Uint64,
+	types.Uintptr:       reflect.Uintptr,
+	types.Float32:       reflect.Float32,
+	types.Float64:       reflect.Float64,
+	types.Complex64:     reflect.Complex64,
+	types.Complex128:    reflect.Complex128,
+	types.String:        reflect.String,
+	types.UnsafePointer: reflect.UnsafePointer,
+}
+
+func (t sourceType) Kind() reflect.Kind {
+	switch u := t.t.Underlying().(type) {
+	case *types.Basic:
+		return basicKinds[u.Kind()]
+	case *types.Pointer:
+		return reflect.Ptr
+	case *types.Slice:
+		return reflect.Slice
+	case *types.Array:
+		return reflect.Array
+	case *types.Map:
+		return reflect.Map
+	case *types.Chan:
+		return reflect.Chan
+	case *types.Struct:
+		return reflect.Struct
+	case *types.Interface:
+		return reflect.Interface
+	case *types.Signature:
+		return reflect.Func
+	default:
+		return reflect.Invalid
+	}
+}
+
+func (t sourceType) Name() string {
+	switch tt := t.t.(type) {
+	case *types.Named:
+		if tt.TypeArgs().Len() > 0 {
+			// Like reflect, qualify type arguments by import path.
+			args := make([]string, tt.TypeArgs().Len())
+			for i := range args {
+				args[i] = typeArgString(tt.TypeArgs().At(i))
+			}
+			return tt.Obj().Name() + "[" + strings.Join(args, ",") + "]"
+		}
+		return tt.Obj().Name()
+	case *types.Basic:
+		// Use the canonical name for aliases such as byte and rune.
+		return types.Typ[tt.Kind()].Name()
+	default:
+		return ""
+	}
+}
+
+// typeArgString returns the string form of a type argument as reflect gives it.
+func typeArgString(t types.Type) string {
+	switch tt := types.Unalias(t).(type) {
+	case *types.Named:
+		if tt.Obj().Pkg() == nil {
+			return tt.Obj().Name()
+		}
+		return tt.Obj().Pkg().Path() + "." + SourceType(tt).Name()
+	case *types.Basic:
+		return SourceType(tt).Name()
+	case *types.Pointer:
+		return "*" + typeArgString(tt.Elem())
+	case *types.Slice:
+		return "[]" + typeArgString(tt.Elem())
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", tt.Len(), typeArgString(tt.Elem()))
+	case *types.Map:
+		return "map[" + typeArgString(tt.Key()) + "]" + typeArgString(tt.Elem())
+	default:
+		return types.TypeString(tt, func(p *types.Package) string {
+			return p.Path()
+		})
+	}
+}
+
+func (t sourceType) PkgPath() string {
+	if tt, ok := t.t.(*types.Named); ok && tt.Obj().Pkg() != nil {
+		return tt.Obj().Pkg().Path()
+	}
+	return ""
+}
+
+func (t sourceType) String() string {
+	switch tt := t.t.(type) {
+	case *types.Named:
+		if tt.Obj().Pkg() == nil {
+			return tt.Obj().Name()
+		}
+		return tt.Obj().Pkg().Name() + "." + t.Name()
+	case *types.Basic:
+		return t.Name()
+	case *types.Pointer:
+		return "*" + t.Elem().String()
+	case *types.Slice:
+		return "[]" + t.Elem().String()
+	case *types.Array:
+		return fmt.Sprintf("[%d]%s", tt.Len(), t.Elem())
+	case *types.Map:
+		return "map[" + t.Key().String() + "]" + t.Elem().String()
+	default:
+		return types.TypeString(t.t, func(p *types.Package) string {
+			return p.Name()
+		})
+	}
+}
+
+func (t sourceType) Elem() Type {
+	switch u := t.t.Underlying().(type) {
+	case *types.Pointer:
+		return SourceType(u.Elem())
+	case *types.Slice:
+		return SourceType(u.Elem())
+	case *types.Array:
+		return SourceType(u.Elem())
+	case *types.Map:
+		return SourceType(u.Elem())
+	case *types.Chan:
+		return SourceType(u.Elem())
+	default:
+		panic(fmt.Sprintf("Elem of invalid type %s", t))
+	}
+}
+
+func (t sourceType) Key() Type {
+	u, ok := t.t.Underlying().(*types.Map)
+	if !ok {
+		panic(fmt.Sprintf("Key of non-map type %s", t))
+	}
+	return SourceType(u.Key())
+}
+
+func (t sourceType) Len() int {
+	u, ok := t.t.Underlying().(*types.Array)
+	if !ok {
+		panic(fmt.Sprintf("Len of non-array type %s", t))
+	}
+	return int(u.Len())
+}
+
+func (t sourceType) Bits() int {
+	switch t.Kind() {
+	case reflect.Int8, reflect.Uint8:
+		return 8
+	case reflect.Int16, reflect.Uint16:
+		return 16
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 32
+	case reflect.Int64, reflect.Uint64, reflect.Float64, reflect.Complex64:
+		return 64
+	case reflect.Int, reflect.Uint, reflect.Uintptr:
+		return strconv.IntSize
+	case reflect.Complex128:
+		return 128
+	default:
+		panic(fmt.Sprintf("Bits of non-arithmetic type %s", t))
+	}
+}
+
+func (t sourceType) NumField() int {
+	u, ok := t.t.Underlying().(*types.Struct)
+	if !ok {
+		panic(fmt.Sprintf("NumField of non-struct type %s", t))
+	}
+	return u.NumFields()
+}
+
+func (t sourceType) Field(i int) StructField {
+	u, ok := t.t.Underlying().(*types.Struct)
+	if !ok {
+		panic(fmt.Sprintf("Field of non-struct type %s", t))
+	}
+	f := u.Field(i)
+	return StructField{Name: f.Name(), Type: SourceType(f.Type()), Tag: reflect.StructTag(u.Tag(i)), Anonymous: f.Embedded()}
+}
+
+var (
+	sourceTextMarshaler   = methodInterface("MarshalText", types.NewTuple(), textResults())
+	sourceTextUnmarshaler = methodInterface("UnmarshalText", types.NewTuple(bytesVar()), textResults()[1:])
+	sourceIsZeroer        = methodInterface("IsZero", types.NewTuple(), []*types.Var{types.NewVar(token.NoPos, nil, "", types.Typ[types.Bool])})
+)
+
+func bytesVar() *types.Var {
+	return types.NewVar(token.NoPos, nil, "", types.NewSlice(types.Typ[types.Byte]))
+}
+
+func textResults() []*types.Var {
+	return []*types.Var{bytesVar(), types.NewVar(token.NoPos, nil, "", types.Universe.Lookup("error").Type())}
+}
+
+// methodInterface builds the interface type holding the single given method.
+func methodInterface(name string, params *types.Tuple, results []*types.Var) *types.Interface {
+	sig := types.NewSignatureType(nil, nil, nil, params, types.NewTuple(results...), false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, name, sig)}, nil).Complete()
+}
+
+func (t sourceType) hasIsZero() bool {
+	return types.Implements(types.NewPointer(t.t), sourceIsZeroer)
+}
+
+func (t sourceType) textMethods() (bool, bool) {
+	pt := types.NewPointer(t.t)
+	marshaler := types.Implements(t.t, sourceTextMarshaler) || types.Implements(pt, sourceTextMarshaler)
+	return marshaler, types.Implements(pt, sourceTextUnmarshaler)
+}
@@ -0,0 +1,140 @@
+package typegen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSourceMatchesReflection checks that generating from source produces the
+// checked in test codecs, which were generated from runtime types.
+func TestSourceMatchesReflection(t *testing.T) {
+	p, err := LoadPackage("testing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "testing" || p.Path != "github.com/alanshaw/dag-json-gen/testing" {
+		t.Fatalf("unexpected package %s %s", p.Name, p.Path)
+	}
+
+	for _, tc := range []struct {
+		file  string
+		write func(g Gen, fname, pkg string, types ...interface{}) error
+		names []string
+	}{
+		{"dag_json_gen.go", Gen.WriteTupleEncodersToFile, []string{
+			"SignedArray", "SimpleTypeOne", "SimpleTypeTwo", "DeferredContainer", "FixedArrays",
+			"ThingWithSomeTime", "BigField", "IntArray", "IntAliasArray", "TupleIntArray",
+			"TupleIntArrayOptionals", "IntArrayNewType", "IntArrayAliasNewType", "MapTransparentType",
+			"BigIntContainer", "TupleWithOptionalFields", "FloatContainer", "IntWidths",
+			"TupleWithDefaults", "TupleEnvelope",
+		}},
+		{"dag_json_map_gen.go", Gen.WriteMapEncodersToFile, []string{
+			"SimpleTypeTree", "NeedScratchForMap", "SimpleStructV1", "SimpleStructV1Lossless", "SimpleStructV2", "RenamedFields",
+			"TestEmpty", "TestConstField", "TestCanonicalFieldOrder", "MapStringString",
+			"TestSliceNilPreserve", "StringPtrSlices", "FieldNameOverlap", "MapKeys", "MapValues",
+			"Circle", "Square", "KeyedUnion", "EnvelopeUnion", "InlineUnion", "KindedUnion",
+			"UnionContainer", "NodeContainer", "StrictFields", "StrictFieldsLossless",
+			"StrictInlineUnion", "LosslessInlineUnion", "UnionHolder",
+			"RequiredFields", "DefaultFields",
+			"OmitEmptyKinds", "Window", "Envelope", "Stamp",
+			"Page[SimpleTypeOne]", "Page[int64]", "Pair[string, *big.Int]", "GenericFields",
+		}},
+		{"dag_json_mixed_gen.go", Gen.WriteEncodersToFile, []string{
+			"MixedTuple", "MixedMap", "MixedDefault",
+		}},
+	} {
+		t.Run(tc.file, func(t *testing.T) {
+			gtis, err := p.TypeInfos(tc.names...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			types := make([]interface{}, len(gtis))
+			for i, gti := range gtis {
+				types[i] = gti
+			}
+			fname := filepath.Join(t.TempDir(), tc.file)
+			if err := tc.write(Gen{}, fname, p.Name, types...); err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := os.ReadFile(filepath.Join("testing", tc.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := os.ReadFile(fname)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(actual) != string(expected) {
+				t.Fatal("generating from source did not match generating from runtime types")
+			}
+		})
+	}
+}
+
+// TestSourceBrokenPackage generates for marked types in a package whose
+// generated file no longer compiles.
+func TestSourceBrokenPackage(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shapes\n",
+		"shapes.go": `package shapes
+
+//dagjsongen:generate
+type Point struct {
+	X, Y int64
+}
+
+// Path is a series of points.
+//
+//dagjsongen:generate
+type Path struct {
+	Points []Point
+	Names  map[string]byte
+}
+
+type Unmarked struct{}
+
+func (p *Path) Len() int {
+	return len(p.Points) + p.NoSuchMethod()
+}
+`,
+		"dag_json_gen.go": generatedPrefix + " DO NOT EDIT.\n\npackage shapes\n\nfunc (t *Point) {\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := LoadPackage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Path != "example.com/shapes" {
+		t.Fatalf("unexpected import path %s", p.Path)
+	}
+	if marked := p.MarkedTypes(); len(marked) != 2 || marked[0] != "Point" || marked[1] != "Path" {
+		t.Fatalf("unexpected marked types %v", marked)
+	}
+
+	gtis, err := p.TypeInfos()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := gtis[1]
+	if path.Name != "Path" || len(path.Fields) != 2 {
+		t.Fatalf("unexpected type info %+v", path)
+	}
+	if f := path.Fields[0]; f.TypeName() != "[]Point" || f.ElemName() != "Point" {
+		t.Errorf("unexpected field type %s", f.TypeName())
+	}
+	if f := path.Fields[1]; f.TypeName() != "map[string]uint8" {
+		t.Errorf("unexpected field type %s", f.TypeName())
+	}
+
+	if _, err := p.TypeInfo("Missing"); err == nil {
+		t.Error("expected an error for a missing type")
+	}
+}
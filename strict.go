@@ -0,0 +1,249 @@
+package typegen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"pitr.ca/jsontokenizer"
+)
+
+// Strict makes a reader reject input that is valid JSON but not the single
+// canonical DAG-JSON encoding of its value:
+//
+//   - whitespace between tokens
+//   - map keys that are duplicated or not sorted by their bytes
+//   - integers with a sign or leading zeros other than a single "-", and "-0"
+//   - floats not in the shortest form written by DagJsonWriter, including
+//     exponents where a plain decimal is used
+//   - maps with a "/" key that are not exactly a link or bytes
+//   - strings not escaped as DagJsonWriter escapes them, such as "\u0061" for
+//     "a", "\/" for "/" or "\u000a" for "\n", or a literal "<" for "\u003c"
+//
+// Generated UnmarshalDagJSON methods and Deferred decode strictly when given a
+// strict reader.
+func Strict() ReaderOption {
+	return func(d *DagJsonReader) {
+		d.strict = true
+	}
+}
+
+// strictReader fails on whitespace outside of strings, which the tokenizer
+// would otherwise skip.
+type strictReader struct {
+	r        io.Reader
+	inString bool
+	escaped  bool
+}
+
+func (s *strictReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	for i, b := range p[:n] {
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case s.inString && b == '\\':
+			s.escaped = true
+		case b == '"':
+			s.inString = !s.inString
+		case !s.inString && (b == ' ' || b == '\t' || b == '\n' || b == '\r'):
+			return i, fmt.Errorf("strict DAG-JSON: unexpected whitespace")
+		}
+	}
+	return n, err
+}
+
+// reserved tracks the "/" entry of a map, which must make the map a link or
+// bytes.
+type reserved uint8
+
+const (
+	reservedNone reserved = iota
+	// reservedKey means the "/" key has been read but not its value.
+	reservedKey
+	reservedLink
+	reservedBytes
+)
+
+// strictFrame is the state of a list or map being read by a strict reader.
+type strictFrame struct {
+	object    bool
+	expectKey bool
+	keys      int
+	lastKey   string
+	reserved  reserved
+	// bytes is set for the map under a "/" key, which must hold nothing but a
+	// "bytes" entry.
+	bytes bool
+}
+
+// strictValue is what must be checked about the contents of the next string.
+type strictValue uint8
+
+const (
+	strictAny strictValue = iota
+	strictKey
+	strictLink
+	strictBytes
+)
+
+func (d *DagJsonReader) top() *strictFrame {
+	if len(d.frames) == 0 {
+		return nil
+	}
+	return &d.frames[len(d.frames)-1]
+}
+
+// track updates the strict state with a token that has just been read.
+func (d *DagJsonReader) track(tok jsontokenizer.TokType) error {
+	top := d.top()
+	switch tok {
+	case jsontokenizer.TokObjectClose, jsontokenizer.TokArrayClose:
+		if top == nil {
+			return nil
+		}
+		d.frames = d.frames[:len(d.frames)-1]
+		if top.bytes && top.keys != 1 {
+			return fmt.Errorf("strict DAG-JSON: bytes must be a map with a single \"bytes\" entry")
+		}
+		if top.reserved != reservedNone && top.keys != 1 {
+			return fmt.Errorf("strict DAG-JSON: map with a \"/\" key must be a link or bytes")
+		}
+		return nil
+	case jsontokenizer.TokComma:
+		if top != nil && top.object {
+			top.expectKey = true
+		}
+		return nil
+	case jsontokenizer.TokObjectColon:
+		return nil
+	case jsontokenizer.TokString:
+		if top != nil && top.expectKey {
+			top.expectKey = false
+			d.next = strictKey
+			return nil
+		}
+	}
+
+	// The token starts a value.
+	var bytes bool
+	if top != nil {
+		switch {
+		case top.reserved == reservedKey:
+			switch tok {
+			case jsontokenizer.TokString:
+				top.reserved = reservedLink
+				d.next = strictLink
+			case jsontokenizer.TokObjectOpen:
+				top.reserved = reservedBytes
+				bytes = true
+			default:
+				return fmt.Errorf("strict DAG-JSON: \"/\" must hold a link string or a bytes map, not %s", tokenName(tok))
+			}
+		case top.bytes:
+			if tok != jsontokenizer.TokString {
+				return fmt.Errorf("strict DAG-JSON: \"bytes\" must hold a string, not %s", tokenName(tok))
+			}
+			d.next = strictBytes
+		}
+	}
+	switch tok {
+	case jsontokenizer.TokObjectOpen:
+		d.frames = append(d.frames, strictFrame{object: true, expectKey: true, bytes: bytes})
+	case jsontokenizer.TokArrayOpen:
+		d.frames = append(d.frames, strictFrame{})
+	}
+	return nil
+}
+
+// checkString checks the contents of a string that has just been read.
+func (d *DagJsonReader) checkString(s string) error {
+	next := d.next
+	d.next = strictAny
+	switch next {
+	case strictKey:
+		top := d.top()
+		if top.keys > 0 {
+			if s == top.lastKey {
+				return fmt.Errorf("strict DAG-JSON: duplicate map key %q", s)
+			}
+			if s < top.lastKey {
+				return fmt.Errorf("strict DAG-JSON: map key %q must come before %q", s, top.lastKey)
+			}
+			if top.reserved != reservedNone {
+				return fmt.Errorf("strict DAG-JSON: map with a \"/\" key must be a link or bytes")
+			}
+		}
+		if s == "/" {
+			if top.keys > 0 {
+				return fmt.Errorf("strict DAG-JSON: map with a \"/\" key must be a link or bytes")
+			}
+			top.reserved = reservedKey
+		}
+		if top.bytes && (top.keys > 0 || s != "bytes") {
+			return fmt.Errorf("strict DAG-JSON: bytes must be a map with a single \"bytes\" entry")
+		}
+		top.keys++
+		top.lastKey = s
+	case strictLink:
+		if _, err := d.parseLink(s); err != nil {
+			return fmt.Errorf("strict DAG-JSON: invalid link: %w", err)
+		}
+	case strictBytes:
+		if _, err := base64.RawStdEncoding.DecodeString(s); err != nil {
+			return fmt.Errorf("strict DAG-JSON: invalid bytes: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkStringEncoding fails if raw, the contents of a string as read, is not
+// how DagJsonWriter writes s, the string it decodes to.
+func checkStringEncoding(raw []byte, s string) error {
+	if !bytes.ContainsAny(raw, `\<>&`) && string(raw) == s && !strings.ContainsAny(s, "\u2028\u2029") {
+		return nil
+	}
+	enc := appendString(nil, s)
+	enc = enc[1 : len(enc)-1]
+	if bytes.Equal(raw, enc) {
+		return nil
+	}
+	i := 0
+	for i < len(raw) && i < len(enc) && raw[i] == enc[i] {
+		i++
+	}
+	return fmt.Errorf("strict DAG-JSON: string is not escaped canonically from byte %d of its contents", i)
+}
+
+// checkNumber fails if s is not the canonical encoding of its value.
+func checkNumber(s string) error {
+	if !strings.ContainsAny(s, ".eE") {
+		digits := strings.TrimPrefix(s, "-")
+		if digits == "" || strings.Trim(digits, "0123456789") != "" {
+			return fmt.Errorf("strict DAG-JSON: invalid integer %s", s)
+		}
+		if len(digits) > 1 && digits[0] == '0' {
+			return fmt.Errorf("strict DAG-JSON: integer %s has leading zeros", s)
+		}
+		if s == "-0" {
+			return fmt.Errorf("strict DAG-JSON: integer %s must be written as 0", s)
+		}
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	canonical, err := formatFloat(f, 64)
+	if err != nil {
+		return err
+	}
+	if s != canonical {
+		return fmt.Errorf("strict DAG-JSON: float %s must be written as %s", s, canonical)
+	}
+	return nil
+}
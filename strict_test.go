@@ -0,0 +1,106 @@
+package typegen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStrictAccepts(t *testing.T) {
+	for _, in := range []string{
+		`null`,
+		`0`,
+		`-12`,
+		`1.5`,
+		`-0.0`,
+		`1e+21`,
+		`1e-7`,
+		`"a b\"  c"`,
+		`"\n\t\b\f\r\\\u0000\u001f\u003c\u003e\u0026\u2028\u2029é😀"`,
+		`[1,[2,{}],"x"]`,
+		`{"":1,"a":{"/":"bafkqaaa"},"b":{"/":{"bytes":"AQID"}},"ba":[]}`,
+		`{"!":1,"a":2}`,
+	} {
+		var n Node
+		if err := n.UnmarshalDagJSON(NewDagJsonReader(strings.NewReader(in), Strict())); err != nil {
+			t.Errorf("%s: %s", in, err)
+		}
+	}
+}
+
+func TestStrictRejects(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{`{"a": 1}`, "whitespace"},
+		{"[1,\n2]", "whitespace"},
+		{`{"b":1,"a":2}`, `map key "a" must come before "b"`},
+		{`{"a":1,"a":2}`, `duplicate map key "a"`},
+		{`{"a":{"y":1,"x":2}}`, `map key "x" must come before "y"`},
+		{`-0`, "must be written as 0"},
+		{`007`, "leading zeros"},
+		{`1E3`, "must be written as 1000.0"},
+		{`1e2`, "must be written as 100.0"},
+		{`1.50`, "must be written as 1.5"},
+		{`1.`, "must be written as 1.0"},
+		{`{"/":1}`, `"/" must hold a link string or a bytes map`},
+		{`{"/":"bafkqaaa","a":1}`, `must be a link or bytes`},
+		{`{"!":1,"/":"bafkqaaa"}`, `must be a link or bytes`},
+		{`{"/":"notacid"}`, "invalid link"},
+		{`{"/":{"bytes":"AQID","x":1}}`, `single "bytes" entry`},
+		{`{"/":{"byte":"AQID"}}`, `single "bytes" entry`},
+		{`{"/":{}}`, `single "bytes" entry`},
+		{`{"/":{"bytes":1}}`, `"bytes" must hold a string`},
+		{`{"/":{"bytes":"!!"}}`, "invalid bytes"},
+		{`"\u0061"`, "not escaped canonically from byte 0"},
+		{`"a\/b"`, "not escaped canonically from byte 1"},
+		{`"\u000a"`, "not escaped canonically"},
+		{`"\u0009"`, "not escaped canonically"},
+		{`"\u0022"`, "not escaped canonically"},
+		{`"\u005c"`, "not escaped canonically"},
+		{`"\u003C"`, "not escaped canonically"},
+		{`"\u00e9"`, "not escaped canonically"},
+		{`"\ud83d\ude00"`, "not escaped canonically"},
+		{`"<"`, "not escaped canonically"},
+		{"\"\u2028\"", "not escaped canonically"},
+		{"\"\xff\"", "not escaped canonically"},
+		{`{"\u0061":1}`, "not escaped canonically"},
+	} {
+		var n Node
+		err := n.UnmarshalDagJSON(NewDagJsonReader(strings.NewReader(tc.in), Strict()))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.in, tc.err, err)
+		}
+
+		// The same documents are accepted, or at least fail differently, when
+		// not strict.
+		var lax Node
+		if err := lax.UnmarshalDagJSON(strings.NewReader(tc.in)); err != nil && strings.Contains(err.Error(), "strict") {
+			t.Errorf("%s: lax reader failed strictly: %s", tc.in, err)
+		}
+	}
+}
+
+func TestStrictDeferred(t *testing.T) {
+	var d Deferred
+	if err := d.UnmarshalDagJSON(NewDagJsonReader(strings.NewReader(`{"b":[1,2],"a":null}`), Strict())); err == nil {
+		t.Fatal("expected deferred to reject unsorted keys")
+	}
+
+	in := `{"a":[{"/":"bafkqaaa"}],"b":-1}`
+	jr := NewDagJsonReader(strings.NewReader(in), Strict())
+	if err := d.UnmarshalDagJSON(jr); err != nil {
+		t.Fatal(err)
+	}
+	if err := jr.ReadEOF(); err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := d.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != in {
+		t.Fatalf("expected %s, got %s", in, buf.String())
+	}
+}
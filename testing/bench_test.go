@@ -9,6 +9,7 @@ import (
 	"testing/quick"
 
 	jsg "github.com/alanshaw/dag-json-gen"
+	"github.com/ipfs/go-cid"
 )
 
 func BenchmarkMarshaling(b *testing.B) {
@@ -74,6 +75,12 @@ func BenchmarkLinkScan(b *testing.B) {
 
 	b.ReportAllocs()
 	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := jsg.ScanForLinks(bytes.NewReader(buf.Bytes()), func(cid.Cid) {}); err != nil {
+			b.Fatal(err)
+		}
+	}
 }
 
 func BenchmarkDeferred(b *testing.B) {
@@ -3,23 +3,28 @@
 package testing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"strconv"
 
 	jsg "github.com/alanshaw/dag-json-gen"
 	cid "github.com/ipfs/go-cid"
 )
 
 var _ = cid.Undef
+var _ = bytes.NewReader
 var _ = math.E
 var _ = sort.Sort
+var _ = strconv.Itoa
 var _ = errors.Is
 
-func (t *SignedArray) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SignedArray) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -62,21 +67,22 @@ func (t *SignedArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = SignedArray{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("SignedArray: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("SignedArray: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SignedArray: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -85,16 +91,16 @@ func (t *SignedArray) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Signed: %w", err)
+				return jr.PathError(".Signed", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Signed: %w", err)
+				return jr.PathError(".Signed", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Signed: %w", err)
+					return jr.PathError(".Signed", err)
 				}
 
 			} else {
@@ -104,8 +110,9 @@ func (t *SignedArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 						nval, err := jr.ReadNumberAsUint64()
 						if err != nil {
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Signed"+jsg.IndexPath(i), err)
 						}
+
 						item[0] = uint64(nval)
 
 					}
@@ -113,27 +120,35 @@ func (t *SignedArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Signed: %w", err)
+						return jr.PathError(".Signed", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.Signed: slice too large")
+						return jr.PathError(".Signed", errors.New("slice too large"))
 					}
 				}
 			}
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SignedArray: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *SimpleTypeOne) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SignedArray) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *SimpleTypeOne) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -232,21 +247,22 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = SimpleTypeOne{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("SimpleTypeOne: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("SimpleTypeOne: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SimpleTypeOne: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -256,19 +272,19 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Foo: string too long")
+					return jr.PathError(".Foo", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.Foo: %w", err)
+				return jr.PathError(".Foo", err)
 			}
 			t.Foo = string(sval)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeOne: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 6")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 6"))
 			}
 		}
 
@@ -278,18 +294,19 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64()
 			if err != nil {
-				return fmt.Errorf("t.Value: %w", err)
+				return jr.PathError(".Value", err)
 			}
+
 			t.Value = uint64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeOne: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 6")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 6"))
 			}
 		}
 
@@ -299,9 +316,9 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 			bval, err := jr.ReadBytes(2097152)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Binary: byte array too large")
+					return jr.PathError(".Binary", errors.New("byte array too large"))
 				}
-				return fmt.Errorf("t.Binary: %w", err)
+				return jr.PathError(".Binary", err)
 			}
 			if len(bval) > 0 {
 				t.Binary = []uint8(bval)
@@ -311,10 +328,10 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeOne: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 3 < 6")
+				return jr.PathError("", errors.New("json input has too few fields 3 < 6"))
 			}
 		}
 
@@ -324,18 +341,19 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Signed: %w", err)
+				return jr.PathError(".Signed", err)
 			}
+
 			t.Signed = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeOne: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 4 < 6")
+				return jr.PathError("", errors.New("json input has too few fields 4 < 6"))
 			}
 		}
 
@@ -345,19 +363,19 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.NString: string too long")
+					return jr.PathError(".NString", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.NString: %w", err)
+				return jr.PathError(".NString", err)
 			}
 			t.NString = NamedString(sval)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeOne: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 5 < 6")
+				return jr.PathError("", errors.New("json input has too few fields 5 < 6"))
 			}
 		}
 
@@ -366,16 +384,16 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Strings: %w", err)
+				return jr.PathError(".Strings", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Strings: %w", err)
+				return jr.PathError(".Strings", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Strings: %w", err)
+					return jr.PathError(".Strings", err)
 				}
 
 			} else {
@@ -385,9 +403,9 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 						sval, err := jr.ReadString(8192)
 						if err != nil {
 							if errors.Is(err, jsg.ErrLimitExceeded) {
-								return fmt.Errorf("item[0]: string too long")
+								return jr.PathError(".Strings"+jsg.IndexPath(i), errors.New("string too long"))
 							}
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Strings"+jsg.IndexPath(i), err)
 						}
 						item[0] = string(sval)
 					}
@@ -395,27 +413,35 @@ func (t *SimpleTypeOne) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Strings: %w", err)
+						return jr.PathError(".Strings", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.Strings: slice too large")
+						return jr.PathError(".Strings", errors.New("slice too large"))
 					}
 				}
 			}
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SimpleTypeOne: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *SimpleTypeTwo) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SimpleTypeOne) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *SimpleTypeTwo) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -620,21 +646,22 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = SimpleTypeTwo{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("SimpleTypeTwo: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("SimpleTypeTwo: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SimpleTypeTwo: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -643,16 +670,16 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			null, err := jr.PeekNull()
 			if err != nil {
-				return fmt.Errorf("t.Stuff: %w", err)
+				return jr.PathError(".Stuff", err)
 			}
 			if null {
 				if err := jr.ReadNull(); err != nil {
-					return fmt.Errorf("t.Stuff: %w", err)
+					return jr.PathError(".Stuff", err)
 				}
 			} else {
 				t.Stuff = new(SimpleTypeTwo)
 				if err := t.Stuff.UnmarshalDagJSON(jr); err != nil {
-					return fmt.Errorf("unmarshaling t.Stuff pointer: %w", err)
+					return jr.PathError(".Stuff", err)
 				}
 			}
 		}
@@ -660,10 +687,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 9"))
 			}
 		}
 
@@ -672,16 +699,16 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Others: %w", err)
+				return jr.PathError(".Others", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Others: %w", err)
+				return jr.PathError(".Others", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Others: %w", err)
+					return jr.PathError(".Others", err)
 				}
 
 			} else {
@@ -691,8 +718,9 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 						nval, err := jr.ReadNumberAsUint64()
 						if err != nil {
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Others"+jsg.IndexPath(i), err)
 						}
+
 						item[0] = uint64(nval)
 
 					}
@@ -700,13 +728,13 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Others: %w", err)
+						return jr.PathError(".Others", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.Others: slice too large")
+						return jr.PathError(".Others", errors.New("slice too large"))
 					}
 				}
 			}
@@ -715,10 +743,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 9"))
 			}
 		}
 
@@ -727,16 +755,16 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.SignedOthers: %w", err)
+				return jr.PathError(".SignedOthers", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.SignedOthers: %w", err)
+				return jr.PathError(".SignedOthers", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.SignedOthers: %w", err)
+					return jr.PathError(".SignedOthers", err)
 				}
 
 			} else {
@@ -746,8 +774,9 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 						nval, err := jr.ReadNumberAsInt64()
 						if err != nil {
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".SignedOthers"+jsg.IndexPath(i), err)
 						}
+
 						item[0] = int64(nval)
 
 					}
@@ -755,13 +784,13 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.SignedOthers: %w", err)
+						return jr.PathError(".SignedOthers", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.SignedOthers: slice too large")
+						return jr.PathError(".SignedOthers", errors.New("slice too large"))
 					}
 				}
 			}
@@ -770,10 +799,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 3 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 3 < 9"))
 			}
 		}
 
@@ -782,16 +811,16 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Test: %w", err)
+				return jr.PathError(".Test", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Test: %w", err)
+				return jr.PathError(".Test", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Test: %w", err)
+					return jr.PathError(".Test", err)
 				}
 
 			} else {
@@ -802,9 +831,9 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 						bval, err := jr.ReadBytes(2097152)
 						if err != nil {
 							if errors.Is(err, jsg.ErrLimitExceeded) {
-								return fmt.Errorf("item[0]: byte array too large")
+								return jr.PathError(".Test"+jsg.IndexPath(i), errors.New("byte array too large"))
 							}
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Test"+jsg.IndexPath(i), err)
 						}
 						if len(bval) > 0 {
 							item[0] = []uint8(bval)
@@ -815,13 +844,13 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Test: %w", err)
+						return jr.PathError(".Test", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.Test: slice too large")
+						return jr.PathError(".Test", errors.New("slice too large"))
 					}
 				}
 			}
@@ -830,10 +859,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 4 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 4 < 9"))
 			}
 		}
 
@@ -843,19 +872,19 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Dog: string too long")
+					return jr.PathError(".Dog", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.Dog: %w", err)
+				return jr.PathError(".Dog", err)
 			}
 			t.Dog = string(sval)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 5 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 5 < 9"))
 			}
 		}
 
@@ -864,16 +893,16 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Numbers: %w", err)
+				return jr.PathError(".Numbers", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Numbers: %w", err)
+				return jr.PathError(".Numbers", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Numbers: %w", err)
+					return jr.PathError(".Numbers", err)
 				}
 
 			} else {
@@ -883,8 +912,9 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 						nval, err := jr.ReadNumberAsUint64()
 						if err != nil {
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Numbers"+jsg.IndexPath(i), err)
 						}
+
 						item[0] = NamedNumber(nval)
 
 					}
@@ -892,13 +922,13 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Numbers: %w", err)
+						return jr.PathError(".Numbers", err)
 					}
 					if close {
 						break
 					}
 					if i == 8192-1 {
-						return fmt.Errorf("t.Numbers: slice too large")
+						return jr.PathError(".Numbers", errors.New("slice too large"))
 					}
 				}
 			}
@@ -907,10 +937,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 6 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 6 < 9"))
 			}
 		}
 
@@ -920,9 +950,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64OrNull()
 			if err != nil {
-				return fmt.Errorf("t.Pizza: %w", err)
+				return jr.PathError(".Pizza", err)
 			}
 			if nval != nil {
+
 				typed := uint64(*nval)
 				t.Pizza = &typed
 			}
@@ -931,10 +962,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 7 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 7 < 9"))
 			}
 		}
 
@@ -944,9 +975,10 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64OrNull()
 			if err != nil {
-				return fmt.Errorf("t.PointyPizza: %w", err)
+				return jr.PathError(".PointyPizza", err)
 			}
 			if nval != nil {
+
 				typed := NamedNumber(*nval)
 				t.PointyPizza = &typed
 			}
@@ -955,46 +987,68 @@ func (t *SimpleTypeTwo) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTwo: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 8 < 9")
+				return jr.PathError("", errors.New("json input has too few fields 8 < 9"))
 			}
 		}
 
 		// t.Arrrrrghay ([3]testing.SimpleTypeOne) (array)
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("t.Arrrrrghay: %w", err)
+			return jr.PathError(".Arrrrrghay", err)
 		}
 
 		t.Arrrrrghay = [3]SimpleTypeOne{}
 		for i := 0; i < 8192; i++ {
 
 			if err := t.Arrrrrghay[i].UnmarshalDagJSON(jr); err != nil {
-				return fmt.Errorf("unmarshaling t.Arrrrrghay[i]: %w", err)
+				return jr.PathError(".Arrrrrghay"+jsg.IndexPath(i), err)
 			}
 
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("t.Arrrrrghay: %w", err)
+				return jr.PathError(".Arrrrrghay", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("t.Arrrrrghay: array too large")
+				return jr.PathError(".Arrrrrghay", errors.New("array too large"))
 			}
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("SimpleTypeTwo: %w", err)
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *SimpleTypeTwo) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Stuff != nil {
+		if err := jsg.ScanLinks(t.Stuff, cb); err != nil {
+			return fmt.Errorf("t.Stuff: %w", err)
+		}
+	}
+
+	for _, v := range t.Arrrrrghay {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
 		}
+
 	}
 	return nil
 }
 
-func (t *DeferredContainer) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *DeferredContainer) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1035,21 +1089,22 @@ func (t *DeferredContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = DeferredContainer{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("DeferredContainer: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("DeferredContainer: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("DeferredContainer: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -1058,16 +1113,16 @@ func (t *DeferredContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			null, err := jr.PeekNull()
 			if err != nil {
-				return fmt.Errorf("t.Stuff: %w", err)
+				return jr.PathError(".Stuff", err)
 			}
 			if null {
 				if err := jr.ReadNull(); err != nil {
-					return fmt.Errorf("t.Stuff: %w", err)
+					return jr.PathError(".Stuff", err)
 				}
 			} else {
 				t.Stuff = new(SimpleTypeOne)
 				if err := t.Stuff.UnmarshalDagJSON(jr); err != nil {
-					return fmt.Errorf("unmarshaling t.Stuff pointer: %w", err)
+					return jr.PathError(".Stuff", err)
 				}
 			}
 		}
@@ -1075,10 +1130,10 @@ func (t *DeferredContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("DeferredContainer: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
 			}
 		}
 
@@ -1087,15 +1142,15 @@ func (t *DeferredContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 		t.Deferred = new(jsg.Deferred)
 
 		if err := t.Deferred.UnmarshalDagJSON(jr); err != nil {
-			return fmt.Errorf("failed to read deferred field: %w", err)
+			return jr.PathError(".Deferred", err)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("DeferredContainer: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
 			}
 		}
 
@@ -1105,20 +1160,42 @@ func (t *DeferredContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64()
 			if err != nil {
-				return fmt.Errorf("t.Value: %w", err)
+				return jr.PathError(".Value", err)
 			}
+
 			t.Value = uint64(nval)
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("DeferredContainer: %w", err)
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *DeferredContainer) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Stuff != nil {
+		if err := jsg.ScanLinks(t.Stuff, cb); err != nil {
+			return fmt.Errorf("t.Stuff: %w", err)
+		}
+	}
+
+	if t.Deferred != nil {
+		if err := jsg.ScanLinks(t.Deferred, cb); err != nil {
+			return fmt.Errorf("t.Deferred: %w", err)
 		}
 	}
+
 	return nil
 }
 
-func (t *FixedArrays) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *FixedArrays) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1181,21 +1258,22 @@ func (t *FixedArrays) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = FixedArrays{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("FixedArrays: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("FixedArrays: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("FixedArrays: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -1204,17 +1282,17 @@ func (t *FixedArrays) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			bval, err := jr.ReadBytes(2097152)
 			if err != nil {
-				return fmt.Errorf("t.Bytes: %w", err)
+				return jr.PathError(".Bytes", err)
 			}
 			t.Bytes = [20]uint8(bval)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("FixedArrays: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
 			}
 		}
 
@@ -1223,24 +1301,24 @@ func (t *FixedArrays) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			bval, err := jr.ReadBytes(2097152)
 			if err != nil {
-				return fmt.Errorf("t.Uint8: %w", err)
+				return jr.PathError(".Uint8", err)
 			}
 			t.Uint8 = [20]uint8(bval)
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("FixedArrays: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
 			}
 		}
 
 		// t.Uint64 ([20]uint64) (array)
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("t.Uint64: %w", err)
+			return jr.PathError(".Uint64", err)
 		}
 
 		t.Uint64 = [20]uint64{}
@@ -1249,31 +1327,40 @@ func (t *FixedArrays) UnmarshalDagJSON(r io.Reader) (err error) {
 
 				nval, err := jr.ReadNumberAsUint64()
 				if err != nil {
-					return fmt.Errorf("t.Uint64[i]: %w", err)
+					return jr.PathError(".Uint64"+jsg.IndexPath(i), err)
 				}
+
 				t.Uint64[i] = uint64(nval)
 
 			}
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("t.Uint64: %w", err)
+				return jr.PathError(".Uint64", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("t.Uint64: array too large")
+				return jr.PathError(".Uint64", errors.New("array too large"))
 			}
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("FixedArrays: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *ThingWithSomeTime) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *FixedArrays) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *ThingWithSomeTime) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1317,37 +1404,38 @@ func (t *ThingWithSomeTime) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = ThingWithSomeTime{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("ThingWithSomeTime: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("ThingWithSomeTime: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("ThingWithSomeTime: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
 		// t.When (typegen.DagJsonTime) (struct)
 
 		if err := t.When.UnmarshalDagJSON(jr); err != nil {
-			return fmt.Errorf("unmarshaling t.When: %w", err)
+			return jr.PathError(".When", err)
 		}
 
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("ThingWithSomeTime: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
 			}
 		}
 
@@ -1357,18 +1445,19 @@ func (t *ThingWithSomeTime) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Stuff: %w", err)
+				return jr.PathError(".Stuff", err)
 			}
+
 			t.Stuff = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("ThingWithSomeTime: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
 			}
 		}
 
@@ -1378,21 +1467,34 @@ func (t *ThingWithSomeTime) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.CatName: string too long")
+					return jr.PathError(".CatName", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.CatName: %w", err)
+				return jr.PathError(".CatName", err)
 			}
 			t.CatName = string(sval)
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("ThingWithSomeTime: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *BigField) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *ThingWithSomeTime) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.When, cb); err != nil {
+		return fmt.Errorf("t.When: %w", err)
+	}
+
+	return nil
+}
+
+func (t *BigField) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1420,21 +1522,22 @@ func (t *BigField) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = BigField{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("BigField: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("BigField: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("BigField: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -1444,9 +1547,9 @@ func (t *BigField) UnmarshalDagJSON(r io.Reader) (err error) {
 			bval, err := jr.ReadBytes(10000000)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.LargeBytes: byte array too large")
+					return jr.PathError(".LargeBytes", errors.New("byte array too large"))
 				}
-				return fmt.Errorf("t.LargeBytes: %w", err)
+				return jr.PathError(".LargeBytes", err)
 			}
 			if len(bval) > 0 {
 				t.LargeBytes = []uint8(bval)
@@ -1454,14 +1557,22 @@ func (t *BigField) UnmarshalDagJSON(r io.Reader) (err error) {
 		}
 
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("BigField: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *IntArray) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *BigField) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *IntArray) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 
 	// t.Ints ([]int64) (slice)
 	if len(t.Ints) > 8192 {
@@ -1494,22 +1605,23 @@ func (t *IntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = IntArray{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 
 	// t.Ints ([]int64) (slice)
 
 	{
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("t.Ints: %w", err)
+			return jr.PathError(".Ints", err)
 		}
 
 		close, err := jr.PeekArrayClose()
 		if err != nil {
-			return fmt.Errorf("t.Ints: %w", err)
+			return jr.PathError(".Ints", err)
 		}
 		if close {
 			if err := jr.ReadArrayClose(); err != nil {
-				return fmt.Errorf("t.Ints: %w", err)
+				return jr.PathError(".Ints", err)
 			}
 
 		} else {
@@ -1519,8 +1631,9 @@ func (t *IntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("item[0]: %w", err)
+						return jr.PathError(".Ints"+jsg.IndexPath(i), err)
 					}
+
 					item[0] = int64(nval)
 
 				}
@@ -1528,13 +1641,13 @@ func (t *IntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 				close, err := jr.ReadArrayCloseOrComma()
 				if err != nil {
-					return fmt.Errorf("t.Ints: %w", err)
+					return jr.PathError(".Ints", err)
 				}
 				if close {
 					break
 				}
 				if i == 8192-1 {
-					return fmt.Errorf("t.Ints: slice too large")
+					return jr.PathError(".Ints", errors.New("slice too large"))
 				}
 			}
 		}
@@ -1543,8 +1656,16 @@ func (t *IntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	return nil
 }
 
-func (t *IntAliasArray) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *IntArray) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *IntAliasArray) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 
 	// t.Ints ([]testing.IntAlias) (slice)
 	if len(t.Ints) > 8192 {
@@ -1577,22 +1698,23 @@ func (t *IntAliasArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = IntAliasArray{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 
 	// t.Ints ([]testing.IntAlias) (slice)
 
 	{
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("t.Ints: %w", err)
+			return jr.PathError(".Ints", err)
 		}
 
 		close, err := jr.PeekArrayClose()
 		if err != nil {
-			return fmt.Errorf("t.Ints: %w", err)
+			return jr.PathError(".Ints", err)
 		}
 		if close {
 			if err := jr.ReadArrayClose(); err != nil {
-				return fmt.Errorf("t.Ints: %w", err)
+				return jr.PathError(".Ints", err)
 			}
 
 		} else {
@@ -1602,8 +1724,9 @@ func (t *IntAliasArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("item[0]: %w", err)
+						return jr.PathError(".Ints"+jsg.IndexPath(i), err)
 					}
+
 					item[0] = IntAlias(nval)
 
 				}
@@ -1611,13 +1734,13 @@ func (t *IntAliasArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 				close, err := jr.ReadArrayCloseOrComma()
 				if err != nil {
-					return fmt.Errorf("t.Ints: %w", err)
+					return jr.PathError(".Ints", err)
 				}
 				if close {
 					break
 				}
 				if i == 8192-1 {
-					return fmt.Errorf("t.Ints: slice too large")
+					return jr.PathError(".Ints", errors.New("slice too large"))
 				}
 			}
 		}
@@ -1626,8 +1749,16 @@ func (t *IntAliasArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	return nil
 }
 
-func (t *TupleIntArray) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *IntAliasArray) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TupleIntArray) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1672,21 +1803,22 @@ func (t *TupleIntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = TupleIntArray{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("TupleIntArray: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("TupleIntArray: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleIntArray: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -1696,18 +1828,19 @@ func (t *TupleIntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int1: %w", err)
+				return jr.PathError(".Int1", err)
 			}
+
 			t.Int1 = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleIntArray: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
 			}
 		}
 
@@ -1717,18 +1850,19 @@ func (t *TupleIntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int2: %w", err)
+				return jr.PathError(".Int2", err)
 			}
+
 			t.Int2 = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleIntArray: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
 			}
 		}
 
@@ -1738,20 +1872,29 @@ func (t *TupleIntArray) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int3: %w", err)
+				return jr.PathError(".Int3", err)
 			}
+
 			t.Int3 = int64(nval)
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleIntArray: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *TupleIntArrayOptionals) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *TupleIntArray) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TupleIntArrayOptionals) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -1818,21 +1961,22 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = TupleIntArrayOptionals{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -1842,9 +1986,10 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64OrNull()
 			if err != nil {
-				return fmt.Errorf("t.Int1: %w", err)
+				return jr.PathError(".Int1", err)
 			}
 			if nval != nil {
+
 				typed := int64(*nval)
 				t.Int1 = &typed
 			}
@@ -1853,10 +1998,10 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 4")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 4"))
 			}
 		}
 
@@ -1866,18 +2011,19 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int2: %w", err)
+				return jr.PathError(".Int2", err)
 			}
+
 			t.Int2 = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 4")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 4"))
 			}
 		}
 
@@ -1887,18 +2033,19 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64()
 			if err != nil {
-				return fmt.Errorf("t.Int3: %w", err)
+				return jr.PathError(".Int3", err)
 			}
+
 			t.Int3 = uint64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 3 < 4")
+				return jr.PathError("", errors.New("json input has too few fields 3 < 4"))
 			}
 		}
 
@@ -1908,23 +2055,32 @@ func (t *TupleIntArrayOptionals) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64OrNull()
 			if err != nil {
-				return fmt.Errorf("t.Int4: %w", err)
+				return jr.PathError(".Int4", err)
 			}
 			if nval != nil {
+
 				typed := uint64(*nval)
 				t.Int4 = &typed
 			}
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleIntArrayOptionals: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *IntArrayNewType) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *TupleIntArrayOptionals) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *IntArrayNewType) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 
 	// (*t) (testing.IntArrayNewType) (slice)
 	if len((*t)) > 8192 {
@@ -1957,22 +2113,23 @@ func (t *IntArrayNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = IntArrayNewType{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 
 	// (*t) (testing.IntArrayNewType) (slice)
 
 	{
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("(*t): %w", err)
+			return jr.PathError("", err)
 		}
 
 		close, err := jr.PeekArrayClose()
 		if err != nil {
-			return fmt.Errorf("(*t): %w", err)
+			return jr.PathError("", err)
 		}
 		if close {
 			if err := jr.ReadArrayClose(); err != nil {
-				return fmt.Errorf("(*t): %w", err)
+				return jr.PathError("", err)
 			}
 
 		} else {
@@ -1982,8 +2139,9 @@ func (t *IntArrayNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("item[0]: %w", err)
+						return jr.PathError(jsg.IndexPath(i), err)
 					}
+
 					item[0] = int64(nval)
 
 				}
@@ -1991,13 +2149,13 @@ func (t *IntArrayNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 
 				close, err := jr.ReadArrayCloseOrComma()
 				if err != nil {
-					return fmt.Errorf("(*t): %w", err)
+					return jr.PathError("", err)
 				}
 				if close {
 					break
 				}
 				if i == 8192-1 {
-					return fmt.Errorf("(*t): slice too large")
+					return jr.PathError("", errors.New("slice too large"))
 				}
 			}
 		}
@@ -2006,8 +2164,16 @@ func (t *IntArrayNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 	return nil
 }
 
-func (t *IntArrayAliasNewType) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *IntArrayNewType) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *IntArrayAliasNewType) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 
 	// (*t) (testing.IntArrayAliasNewType) (slice)
 	if len((*t)) > 8192 {
@@ -2040,22 +2206,23 @@ func (t *IntArrayAliasNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = IntArrayAliasNewType{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 
 	// (*t) (testing.IntArrayAliasNewType) (slice)
 
 	{
 
 		if err := jr.ReadArrayOpen(); err != nil {
-			return fmt.Errorf("(*t): %w", err)
+			return jr.PathError("", err)
 		}
 
 		close, err := jr.PeekArrayClose()
 		if err != nil {
-			return fmt.Errorf("(*t): %w", err)
+			return jr.PathError("", err)
 		}
 		if close {
 			if err := jr.ReadArrayClose(); err != nil {
-				return fmt.Errorf("(*t): %w", err)
+				return jr.PathError("", err)
 			}
 
 		} else {
@@ -2065,8 +2232,9 @@ func (t *IntArrayAliasNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("item[0]: %w", err)
+						return jr.PathError(jsg.IndexPath(i), err)
 					}
+
 					item[0] = IntAlias(nval)
 
 				}
@@ -2074,13 +2242,13 @@ func (t *IntArrayAliasNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 
 				close, err := jr.ReadArrayCloseOrComma()
 				if err != nil {
-					return fmt.Errorf("(*t): %w", err)
+					return jr.PathError("", err)
 				}
 				if close {
 					break
 				}
 				if i == 8192-1 {
-					return fmt.Errorf("(*t): slice too large")
+					return jr.PathError("", errors.New("slice too large"))
 				}
 			}
 		}
@@ -2089,8 +2257,16 @@ func (t *IntArrayAliasNewType) UnmarshalDagJSON(r io.Reader) (err error) {
 	return nil
 }
 
-func (t *MapTransparentType) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *IntArrayAliasNewType) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *MapTransparentType) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 
 	// (*t) (testing.MapTransparentType) (map)
 	{
@@ -2103,9 +2279,11 @@ func (t *MapTransparentType) MarshalDagJSON(w io.Writer) error {
 		}
 
 		keys := make([]string, 0, len((*t)))
+
 		for k := range *t {
-			keys = append(keys, k)
+			keys = append(keys, string(k))
 		}
+
 		sort.Strings(keys)
 		for i, k := range keys {
 			if i > 0 {
@@ -2113,7 +2291,9 @@ func (t *MapTransparentType) MarshalDagJSON(w io.Writer) error {
 					return fmt.Errorf("(*t): %w", err)
 				}
 			}
-			v := (*t)[k]
+
+			v := (*t)[string(k)]
+
 			if len(k) > 8192 {
 				return fmt.Errorf("String in field k was too long")
 			}
@@ -2143,65 +2323,80 @@ func (t *MapTransparentType) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = MapTransparentType{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 
 	// (*t) (testing.MapTransparentType) (map)
 
-	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("(*t): %w", err)
-	}
+	{
+		if err := jr.ReadObjectOpen(); err != nil {
+			return jr.PathError("", err)
+		}
 
-	(*t) = map[string]string{}
+		(*t) = map[string]string{}
 
-	close, err := jr.PeekObjectClose()
-	if err != nil {
-		return fmt.Errorf("(*t): %w", err)
-	}
-	if close {
-		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("(*t): %w", err)
+		close, err := jr.PeekObjectClose()
+		if err != nil {
+			return jr.PathError("", err)
 		}
-	} else {
-		for i, l := 0, 8192; i < l; i++ {
-			var k string
-			{
-				sval, err := jr.ReadString(8192)
-				if err != nil {
-					if errors.Is(err, jsg.ErrLimitExceeded) {
-						return fmt.Errorf("k: string too long")
+		if close {
+			if err := jr.ReadObjectClose(); err != nil {
+				return jr.PathError("", err)
+			}
+		} else {
+			for i, l := 0, 8192; i < l; i++ {
+				var key string
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError("", errors.New("string too long"))
+						}
+						return jr.PathError("", err)
 					}
-					return fmt.Errorf("k: %w", err)
+					key = string(sval)
 				}
-				k = string(sval)
-			}
-			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("(*t): %w", err)
-			}
-			var v string
-			{
-				sval, err := jr.ReadString(8192)
-				if err != nil {
-					if errors.Is(err, jsg.ErrLimitExceeded) {
-						return fmt.Errorf("v: string too long")
+				if err := jr.ReadObjectColon(); err != nil {
+					return jr.PathError("", err)
+				}
+				var v string
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(jsg.KeyPath(string(key)), errors.New("string too long"))
+						}
+						return jr.PathError(jsg.KeyPath(string(key)), err)
 					}
-					return fmt.Errorf("v: %w", err)
+					v = string(sval)
+				}
+				(*t)[key] = v
+
+				close, err := jr.ReadObjectCloseOrComma()
+				if err != nil {
+					return jr.PathError("", err)
+				}
+				if close {
+					break
+				}
+				if i == l-1 {
+					return jr.PathError("", errors.New("map too large"))
 				}
-				v = string(sval)
-			}
-			(*t)[k] = v
-			close, err := jr.ReadObjectCloseOrComma()
-			if err != nil {
-				return fmt.Errorf("(*t): %w", err)
-			}
-			if close {
-				break
 			}
 		}
 	}
 	return nil
 }
 
-func (t *BigIntContainer) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *MapTransparentType) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *BigIntContainer) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2211,9 +2406,6 @@ func (t *BigIntContainer) MarshalDagJSON(w io.Writer) error {
 	}
 
 	// t.Int (big.Int) (struct)
-	if t.Int != nil && t.Int.Sign() < 0 {
-		return fmt.Errorf("Value in field t.Int was a negative big-integer (not supported)")
-	}
 	if t.Int == nil {
 		if err := jw.WriteUint8(0); err != nil {
 			return fmt.Errorf("t.Int: %w", err)
@@ -2223,6 +2415,20 @@ func (t *BigIntContainer) MarshalDagJSON(w io.Writer) error {
 			return fmt.Errorf("t.Int: %w", err)
 		}
 	}
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Bounded: %w", err)
+	}
+
+	// t.Bounded (big.Int) (struct)
+	if t.Bounded == nil {
+		if err := jw.WriteUint8(0); err != nil {
+			return fmt.Errorf("t.Bounded: %w", err)
+		}
+	} else {
+		if err := jw.WriteBigInt(t.Bounded); err != nil {
+			return fmt.Errorf("t.Bounded: %w", err)
+		}
+	}
 	if err := jw.WriteArrayClose(); err != nil {
 		return fmt.Errorf("BigIntContainer: %w", err)
 	}
@@ -2233,45 +2439,76 @@ func (t *BigIntContainer) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = BigIntContainer{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("BigIntContainer: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("BigIntContainer: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("BigIntContainer: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
 		// t.Int (big.Int) (struct)
 
 		{
-			nval, err := jr.ReadNumberAsBigInt(256)
+			nval, err := jr.ReadNumberAsBigInt(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Int: number too large")
+					return jr.PathError(".Int", errors.New("number too large"))
 				}
-				return fmt.Errorf("t.Int: %w", err)
+				return jr.PathError(".Int", err)
 			}
 			t.Int = nval
 		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 1 < 2"))
+			}
+		}
+
+		// t.Bounded (big.Int) (struct)
+
+		{
+			nval, err := jr.ReadNumberAsBigInt(10)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Bounded", errors.New("number too large"))
+				}
+				return jr.PathError(".Bounded", err)
+			}
+			t.Bounded = nval
+		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("BigIntContainer: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
 
-func (t *TupleWithOptionalFields) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *BigIntContainer) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TupleWithOptionalFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2326,21 +2563,22 @@ func (t *TupleWithOptionalFields) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = TupleWithOptionalFields{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("TupleWithOptionalFields: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("TupleWithOptionalFields: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleWithOptionalFields: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -2350,18 +2588,19 @@ func (t *TupleWithOptionalFields) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int1: %w", err)
+				return jr.PathError(".Int1", err)
 			}
+
 			t.Int1 = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleWithOptionalFields: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 2")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 2"))
 			}
 		}
 
@@ -2371,15 +2610,16 @@ func (t *TupleWithOptionalFields) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsUint64()
 			if err != nil {
-				return fmt.Errorf("t.Uint2: %w", err)
+				return jr.PathError(".Uint2", err)
 			}
+
 			t.Uint2 = uint64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleWithOptionalFields: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				return nil
@@ -2392,15 +2632,16 @@ func (t *TupleWithOptionalFields) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int3: %w", err)
+				return jr.PathError(".Int3", err)
 			}
+
 			t.Int3 = int64(nval)
 
 		}
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TupleWithOptionalFields: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				return nil
@@ -2413,14 +2654,1958 @@ func (t *TupleWithOptionalFields) UnmarshalDagJSON(r io.Reader) (err error) {
 
 			nval, err := jr.ReadNumberAsInt64()
 			if err != nil {
-				return fmt.Errorf("t.Int4: %w", err)
+				return jr.PathError(".Int4", err)
 			}
+
 			t.Int4 = int64(nval)
 
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("TupleWithOptionalFields: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
+
+func (t *TupleWithOptionalFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *FloatContainer) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("FloatContainer: %w", err)
+	}
+
+	// t.F32 (float32) (float32)
+
+	if err := jw.WriteFloat32(float32(t.F32)); err != nil {
+		return fmt.Errorf("t.F32: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("F64: %w", err)
+	}
+
+	// t.F64 (float64) (float64)
+
+	if err := jw.WriteFloat64(float64(t.F64)); err != nil {
+		return fmt.Errorf("t.F64: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("F32Ptr: %w", err)
+	}
+
+	// t.F32Ptr (float32) (float32)
+
+	if t.F32Ptr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.F32Ptr: %w", err)
+		}
+	} else {
+		if err := jw.WriteFloat32(float32(*t.F32Ptr)); err != nil {
+			return fmt.Errorf("t.F32Ptr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("F64Ptr: %w", err)
+	}
+
+	// t.F64Ptr (float64) (float64)
+
+	if t.F64Ptr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.F64Ptr: %w", err)
+		}
+	} else {
+		if err := jw.WriteFloat64(float64(*t.F64Ptr)); err != nil {
+			return fmt.Errorf("t.F64Ptr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Named: %w", err)
+	}
+
+	// t.Named (testing.NamedFloat) (float64)
+
+	if err := jw.WriteFloat64(float64(t.Named)); err != nil {
+		return fmt.Errorf("t.Named: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Floats: %w", err)
+	}
+
+	// t.Floats ([]float64) (slice)
+	if len(t.Floats) > 8192 {
+		return fmt.Errorf("Slice value in field t.Floats was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Floats: %w", err)
+	}
+	for i, v := range t.Floats {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Floats: %w", err)
+			}
+		}
+
+		if err := jw.WriteFloat64(float64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Floats: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Float32: %w", err)
+	}
+
+	// t.Float32 ([]float32) (slice)
+	if len(t.Float32) > 8192 {
+		return fmt.Errorf("Slice value in field t.Float32 was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Float32: %w", err)
+	}
+	for i, v := range t.Float32 {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Float32: %w", err)
+			}
+		}
+
+		if err := jw.WriteFloat32(float32(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Float32: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Fixed: %w", err)
+	}
+
+	// t.Fixed ([3]float64) (array)
+	if len(t.Fixed) > 8192 {
+		return fmt.Errorf("Slice value in field t.Fixed was too long")
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Fixed: %w", err)
+	}
+	for i, v := range t.Fixed {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Fixed: %w", err)
+			}
+		}
+
+		if err := jw.WriteFloat64(float64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Fixed: %w", err)
+	}
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Map: %w", err)
+	}
+
+	// t.Map (map[string]float64) (map)
+	{
+		if len(t.Map) > 4096 {
+			return fmt.Errorf("cannot marshal t.Map map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Map))
+
+		for k := range t.Map {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Map: %w", err)
+				}
+			}
+
+			v := t.Map[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Map: %w", err)
+			}
+
+			if err := jw.WriteFloat64(float64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+	}
+
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("FloatContainer: %w", err)
+	}
+	return nil
+}
+
+func (t *FloatContainer) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = FloatContainer{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadArrayOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	close, err := jr.PeekArrayClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+
+		// t.F32 (float32) (float32)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat32()
+			if err != nil {
+				return jr.PathError(".F32", err)
+			}
+			t.F32 = float32(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 1 < 9"))
+			}
+		}
+
+		// t.F64 (float64) (float64)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat64()
+			if err != nil {
+				return jr.PathError(".F64", err)
+			}
+			t.F64 = float64(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 2 < 9"))
+			}
+		}
+
+		// t.F32Ptr (float32) (float32)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat32OrNull()
+			if err != nil {
+				return jr.PathError(".F32Ptr", err)
+			}
+			if nval != nil {
+				typed := float32(*nval)
+				t.F32Ptr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 3 < 9"))
+			}
+		}
+
+		// t.F64Ptr (float64) (float64)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat64OrNull()
+			if err != nil {
+				return jr.PathError(".F64Ptr", err)
+			}
+			if nval != nil {
+				typed := float64(*nval)
+				t.F64Ptr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 4 < 9"))
+			}
+		}
+
+		// t.Named (testing.NamedFloat) (float64)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat64()
+			if err != nil {
+				return jr.PathError(".Named", err)
+			}
+			t.Named = NamedFloat(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 5 < 9"))
+			}
+		}
+
+		// t.Floats ([]float64) (slice)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".Floats", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".Floats", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".Floats", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]float64, 1)
+					{
+
+						nval, err := jr.ReadNumberAsFloat64()
+						if err != nil {
+							return jr.PathError(".Floats"+jsg.IndexPath(i), err)
+						}
+						item[0] = float64(nval)
+
+					}
+					t.Floats = append(t.Floats, item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Floats", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Floats", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 6 < 9"))
+			}
+		}
+
+		// t.Float32 ([]float32) (slice)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".Float32", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".Float32", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".Float32", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]float32, 1)
+					{
+
+						nval, err := jr.ReadNumberAsFloat32()
+						if err != nil {
+							return jr.PathError(".Float32"+jsg.IndexPath(i), err)
+						}
+						item[0] = float32(nval)
+
+					}
+					t.Float32 = append(t.Float32, item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Float32", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Float32", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 7 < 9"))
+			}
+		}
+
+		// t.Fixed ([3]float64) (array)
+
+		if err := jr.ReadArrayOpen(); err != nil {
+			return jr.PathError(".Fixed", err)
+		}
+
+		t.Fixed = [3]float64{}
+		for i := 0; i < 8192; i++ {
+			{
+
+				nval, err := jr.ReadNumberAsFloat64()
+				if err != nil {
+					return jr.PathError(".Fixed"+jsg.IndexPath(i), err)
+				}
+				t.Fixed[i] = float64(nval)
+
+			}
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError(".Fixed", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError(".Fixed", errors.New("array too large"))
+			}
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 8 < 9"))
+			}
+		}
+
+		// t.Map (map[string]float64) (map)
+
+		{
+			if err := jr.ReadObjectOpen(); err != nil {
+				return jr.PathError(".Map", err)
+			}
+
+			t.Map = map[string]float64{}
+
+			close, err := jr.PeekObjectClose()
+			if err != nil {
+				return jr.PathError(".Map", err)
+			}
+			if close {
+				if err := jr.ReadObjectClose(); err != nil {
+					return jr.PathError(".Map", err)
+				}
+			} else {
+				for i, l := 0, 8192; i < l; i++ {
+					var key string
+					{
+						sval, err := jr.ReadString(8192)
+						if err != nil {
+							if errors.Is(err, jsg.ErrLimitExceeded) {
+								return jr.PathError(".Map", errors.New("string too long"))
+							}
+							return jr.PathError(".Map", err)
+						}
+						key = string(sval)
+					}
+					if err := jr.ReadObjectColon(); err != nil {
+						return jr.PathError(".Map", err)
+					}
+					var v float64
+					{
+
+						nval, err := jr.ReadNumberAsFloat64()
+						if err != nil {
+							return jr.PathError(".Map"+jsg.KeyPath(string(key)), err)
+						}
+						v = float64(nval)
+
+					}
+					t.Map[key] = v
+
+					close, err := jr.ReadObjectCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Map", err)
+					}
+					if close {
+						break
+					}
+					if i == l-1 {
+						return jr.PathError(".Map", errors.New("map too large"))
+					}
+				}
+			}
+		}
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *FloatContainer) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *IntWidths) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("IntWidths: %w", err)
+	}
+
+	// t.Int (int) (int)
+
+	if err := jw.WriteInt64(int64(t.Int)); err != nil {
+		return fmt.Errorf("t.Int: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int8: %w", err)
+	}
+
+	// t.Int8 (int8) (int8)
+
+	if err := jw.WriteInt64(int64(t.Int8)); err != nil {
+		return fmt.Errorf("t.Int8: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int16: %w", err)
+	}
+
+	// t.Int16 (int16) (int16)
+
+	if err := jw.WriteInt64(int64(t.Int16)); err != nil {
+		return fmt.Errorf("t.Int16: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int32: %w", err)
+	}
+
+	// t.Int32 (int32) (int32)
+
+	if err := jw.WriteInt64(int64(t.Int32)); err != nil {
+		return fmt.Errorf("t.Int32: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint: %w", err)
+	}
+
+	// t.Uint (uint) (uint)
+
+	if err := jw.WriteUint64(uint64(t.Uint)); err != nil {
+		return fmt.Errorf("t.Uint: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint8: %w", err)
+	}
+
+	// t.Uint8 (uint8) (uint8)
+
+	if err := jw.WriteUint64(uint64(t.Uint8)); err != nil {
+		return fmt.Errorf("t.Uint8: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint16: %w", err)
+	}
+
+	// t.Uint16 (uint16) (uint16)
+
+	if err := jw.WriteUint64(uint64(t.Uint16)); err != nil {
+		return fmt.Errorf("t.Uint16: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint32: %w", err)
+	}
+
+	// t.Uint32 (uint32) (uint32)
+
+	if err := jw.WriteUint64(uint64(t.Uint32)); err != nil {
+		return fmt.Errorf("t.Uint32: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("IntPtr: %w", err)
+	}
+
+	// t.IntPtr (int) (int)
+
+	if t.IntPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.IntPtr: %w", err)
+		}
+	} else {
+		if err := jw.WriteInt64(int64(*t.IntPtr)); err != nil {
+			return fmt.Errorf("t.IntPtr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int8Ptr: %w", err)
+	}
+
+	// t.Int8Ptr (int8) (int8)
+
+	if t.Int8Ptr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.Int8Ptr: %w", err)
+		}
+	} else {
+		if err := jw.WriteInt64(int64(*t.Int8Ptr)); err != nil {
+			return fmt.Errorf("t.Int8Ptr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("UintPtr: %w", err)
+	}
+
+	// t.UintPtr (uint) (uint)
+
+	if t.UintPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.UintPtr: %w", err)
+		}
+	} else {
+		if err := jw.WriteUint64(uint64(*t.UintPtr)); err != nil {
+			return fmt.Errorf("t.UintPtr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint8Ptr: %w", err)
+	}
+
+	// t.Uint8Ptr (uint8) (uint8)
+
+	if t.Uint8Ptr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.Uint8Ptr: %w", err)
+		}
+	} else {
+		if err := jw.WriteUint64(uint64(*t.Uint8Ptr)); err != nil {
+			return fmt.Errorf("t.Uint8Ptr: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int32s: %w", err)
+	}
+
+	// t.Int32s ([]int32) (slice)
+	if len(t.Int32s) > 8192 {
+		return fmt.Errorf("Slice value in field t.Int32s was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Int32s: %w", err)
+	}
+	for i, v := range t.Int32s {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Int32s: %w", err)
+			}
+		}
+
+		if err := jw.WriteInt64(int64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Int32s: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Uint16s: %w", err)
+	}
+
+	// t.Uint16s ([]uint16) (slice)
+	if len(t.Uint16s) > 8192 {
+		return fmt.Errorf("Slice value in field t.Uint16s was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Uint16s: %w", err)
+	}
+	for i, v := range t.Uint16s {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Uint16s: %w", err)
+			}
+		}
+
+		if err := jw.WriteUint64(uint64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Uint16s: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int8Ptrs: %w", err)
+	}
+
+	// t.Int8Ptrs ([]*int8) (slice)
+	if len(t.Int8Ptrs) > 8192 {
+		return fmt.Errorf("Slice value in field t.Int8Ptrs was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Int8Ptrs: %w", err)
+	}
+	for i, v := range t.Int8Ptrs {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Int8Ptrs: %w", err)
+			}
+		}
+
+		if v == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		} else {
+			if err := jw.WriteInt64(int64(*v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Int8Ptrs: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Fixed: %w", err)
+	}
+
+	// t.Fixed ([2]int16) (array)
+	if len(t.Fixed) > 8192 {
+		return fmt.Errorf("Slice value in field t.Fixed was too long")
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Fixed: %w", err)
+	}
+	for i, v := range t.Fixed {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Fixed: %w", err)
+			}
+		}
+
+		if err := jw.WriteInt64(int64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Fixed: %w", err)
+	}
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Map: %w", err)
+	}
+
+	// t.Map (map[string]uint32) (map)
+	{
+		if len(t.Map) > 4096 {
+			return fmt.Errorf("cannot marshal t.Map map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Map))
+
+		for k := range t.Map {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Map: %w", err)
+				}
+			}
+
+			v := t.Map[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Map: %w", err)
+			}
+
+			if err := jw.WriteUint64(uint64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("MapInt8: %w", err)
+	}
+
+	// t.MapInt8 (map[string]int8) (map)
+	{
+		if len(t.MapInt8) > 4096 {
+			return fmt.Errorf("cannot marshal t.MapInt8 map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.MapInt8: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.MapInt8))
+
+		for k := range t.MapInt8 {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.MapInt8: %w", err)
+				}
+			}
+
+			v := t.MapInt8[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.MapInt8: %w", err)
+			}
+
+			if err := jw.WriteInt64(int64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.MapInt8: %w", err)
+		}
+	}
+
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("IntWidths: %w", err)
+	}
+	return nil
+}
+
+func (t *IntWidths) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = IntWidths{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadArrayOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	close, err := jr.PeekArrayClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+
+		// t.Int (int) (int)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int", err)
+			}
+
+			if nval < math.MinInt || nval > math.MaxInt {
+				return jr.PathError(".Int", fmt.Errorf("value %d out of range for int", nval))
+			}
+
+			t.Int = int(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 1 < 18"))
+			}
+		}
+
+		// t.Int8 (int8) (int8)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int8", err)
+			}
+
+			if nval < math.MinInt8 || nval > math.MaxInt8 {
+				return jr.PathError(".Int8", fmt.Errorf("value %d out of range for int8", nval))
+			}
+
+			t.Int8 = int8(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 2 < 18"))
+			}
+		}
+
+		// t.Int16 (int16) (int16)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int16", err)
+			}
+
+			if nval < math.MinInt16 || nval > math.MaxInt16 {
+				return jr.PathError(".Int16", fmt.Errorf("value %d out of range for int16", nval))
+			}
+
+			t.Int16 = int16(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 3 < 18"))
+			}
+		}
+
+		// t.Int32 (int32) (int32)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int32", err)
+			}
+
+			if nval < math.MinInt32 || nval > math.MaxInt32 {
+				return jr.PathError(".Int32", fmt.Errorf("value %d out of range for int32", nval))
+			}
+
+			t.Int32 = int32(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 4 < 18"))
+			}
+		}
+
+		// t.Uint (uint) (uint)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Uint", err)
+			}
+
+			if nval > math.MaxUint {
+				return jr.PathError(".Uint", fmt.Errorf("value %d out of range for uint", nval))
+			}
+
+			t.Uint = uint(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 5 < 18"))
+			}
+		}
+
+		// t.Uint8 (uint8) (uint8)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Uint8", err)
+			}
+
+			if nval > math.MaxUint8 {
+				return jr.PathError(".Uint8", fmt.Errorf("value %d out of range for uint8", nval))
+			}
+
+			t.Uint8 = uint8(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 6 < 18"))
+			}
+		}
+
+		// t.Uint16 (uint16) (uint16)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Uint16", err)
+			}
+
+			if nval > math.MaxUint16 {
+				return jr.PathError(".Uint16", fmt.Errorf("value %d out of range for uint16", nval))
+			}
+
+			t.Uint16 = uint16(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 7 < 18"))
+			}
+		}
+
+		// t.Uint32 (uint32) (uint32)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Uint32", err)
+			}
+
+			if nval > math.MaxUint32 {
+				return jr.PathError(".Uint32", fmt.Errorf("value %d out of range for uint32", nval))
+			}
+
+			t.Uint32 = uint32(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 8 < 18"))
+			}
+		}
+
+		// t.IntPtr (int) (int)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64OrNull()
+			if err != nil {
+				return jr.PathError(".IntPtr", err)
+			}
+			if nval != nil {
+
+				if *nval < math.MinInt || *nval > math.MaxInt {
+					return jr.PathError(".IntPtr", fmt.Errorf("value %d out of range for int", *nval))
+				}
+
+				typed := int(*nval)
+				t.IntPtr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 9 < 18"))
+			}
+		}
+
+		// t.Int8Ptr (int8) (int8)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64OrNull()
+			if err != nil {
+				return jr.PathError(".Int8Ptr", err)
+			}
+			if nval != nil {
+
+				if *nval < math.MinInt8 || *nval > math.MaxInt8 {
+					return jr.PathError(".Int8Ptr", fmt.Errorf("value %d out of range for int8", *nval))
+				}
+
+				typed := int8(*nval)
+				t.Int8Ptr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 10 < 18"))
+			}
+		}
+
+		// t.UintPtr (uint) (uint)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64OrNull()
+			if err != nil {
+				return jr.PathError(".UintPtr", err)
+			}
+			if nval != nil {
+
+				if *nval > math.MaxUint {
+					return jr.PathError(".UintPtr", fmt.Errorf("value %d out of range for uint", *nval))
+				}
+
+				typed := uint(*nval)
+				t.UintPtr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 11 < 18"))
+			}
+		}
+
+		// t.Uint8Ptr (uint8) (uint8)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64OrNull()
+			if err != nil {
+				return jr.PathError(".Uint8Ptr", err)
+			}
+			if nval != nil {
+
+				if *nval > math.MaxUint8 {
+					return jr.PathError(".Uint8Ptr", fmt.Errorf("value %d out of range for uint8", *nval))
+				}
+
+				typed := uint8(*nval)
+				t.Uint8Ptr = &typed
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 12 < 18"))
+			}
+		}
+
+		// t.Int32s ([]int32) (slice)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".Int32s", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".Int32s", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".Int32s", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]int32, 1)
+					{
+
+						nval, err := jr.ReadNumberAsInt64()
+						if err != nil {
+							return jr.PathError(".Int32s"+jsg.IndexPath(i), err)
+						}
+
+						if nval < math.MinInt32 || nval > math.MaxInt32 {
+							return jr.PathError(".Int32s"+jsg.IndexPath(i), fmt.Errorf("value %d out of range for int32", nval))
+						}
+
+						item[0] = int32(nval)
+
+					}
+					t.Int32s = append(t.Int32s, item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Int32s", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Int32s", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 13 < 18"))
+			}
+		}
+
+		// t.Uint16s ([]uint16) (slice)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".Uint16s", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".Uint16s", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".Uint16s", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]uint16, 1)
+					{
+
+						nval, err := jr.ReadNumberAsUint64()
+						if err != nil {
+							return jr.PathError(".Uint16s"+jsg.IndexPath(i), err)
+						}
+
+						if nval > math.MaxUint16 {
+							return jr.PathError(".Uint16s"+jsg.IndexPath(i), fmt.Errorf("value %d out of range for uint16", nval))
+						}
+
+						item[0] = uint16(nval)
+
+					}
+					t.Uint16s = append(t.Uint16s, item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Uint16s", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Uint16s", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 14 < 18"))
+			}
+		}
+
+		// t.Int8Ptrs ([]*int8) (slice)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".Int8Ptrs", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".Int8Ptrs", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".Int8Ptrs", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]*int8, 1)
+					{
+
+						nval, err := jr.ReadNumberAsInt64OrNull()
+						if err != nil {
+							return jr.PathError(".Int8Ptrs"+jsg.IndexPath(i), err)
+						}
+						if nval != nil {
+
+							if *nval < math.MinInt8 || *nval > math.MaxInt8 {
+								return jr.PathError(".Int8Ptrs"+jsg.IndexPath(i), fmt.Errorf("value %d out of range for int8", *nval))
+							}
+
+							typed := int8(*nval)
+							item[0] = &typed
+						}
+
+					}
+					t.Int8Ptrs = append(t.Int8Ptrs, item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Int8Ptrs", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Int8Ptrs", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 15 < 18"))
+			}
+		}
+
+		// t.Fixed ([2]int16) (array)
+
+		if err := jr.ReadArrayOpen(); err != nil {
+			return jr.PathError(".Fixed", err)
+		}
+
+		t.Fixed = [2]int16{}
+		for i := 0; i < 8192; i++ {
+			{
+
+				nval, err := jr.ReadNumberAsInt64()
+				if err != nil {
+					return jr.PathError(".Fixed"+jsg.IndexPath(i), err)
+				}
+
+				if nval < math.MinInt16 || nval > math.MaxInt16 {
+					return jr.PathError(".Fixed"+jsg.IndexPath(i), fmt.Errorf("value %d out of range for int16", nval))
+				}
+
+				t.Fixed[i] = int16(nval)
+
+			}
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError(".Fixed", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError(".Fixed", errors.New("array too large"))
+			}
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 16 < 18"))
+			}
+		}
+
+		// t.Map (map[string]uint32) (map)
+
+		{
+			if err := jr.ReadObjectOpen(); err != nil {
+				return jr.PathError(".Map", err)
+			}
+
+			t.Map = map[string]uint32{}
+
+			close, err := jr.PeekObjectClose()
+			if err != nil {
+				return jr.PathError(".Map", err)
+			}
+			if close {
+				if err := jr.ReadObjectClose(); err != nil {
+					return jr.PathError(".Map", err)
+				}
+			} else {
+				for i, l := 0, 8192; i < l; i++ {
+					var key string
+					{
+						sval, err := jr.ReadString(8192)
+						if err != nil {
+							if errors.Is(err, jsg.ErrLimitExceeded) {
+								return jr.PathError(".Map", errors.New("string too long"))
+							}
+							return jr.PathError(".Map", err)
+						}
+						key = string(sval)
+					}
+					if err := jr.ReadObjectColon(); err != nil {
+						return jr.PathError(".Map", err)
+					}
+					var v uint32
+					{
+
+						nval, err := jr.ReadNumberAsUint64()
+						if err != nil {
+							return jr.PathError(".Map"+jsg.KeyPath(string(key)), err)
+						}
+
+						if nval > math.MaxUint32 {
+							return jr.PathError(".Map"+jsg.KeyPath(string(key)), fmt.Errorf("value %d out of range for uint32", nval))
+						}
+
+						v = uint32(nval)
+
+					}
+					t.Map[key] = v
+
+					close, err := jr.ReadObjectCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Map", err)
+					}
+					if close {
+						break
+					}
+					if i == l-1 {
+						return jr.PathError(".Map", errors.New("map too large"))
+					}
+				}
+			}
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 17 < 18"))
+			}
+		}
+
+		// t.MapInt8 (map[string]int8) (map)
+
+		{
+			if err := jr.ReadObjectOpen(); err != nil {
+				return jr.PathError(".MapInt8", err)
+			}
+
+			t.MapInt8 = map[string]int8{}
+
+			close, err := jr.PeekObjectClose()
+			if err != nil {
+				return jr.PathError(".MapInt8", err)
+			}
+			if close {
+				if err := jr.ReadObjectClose(); err != nil {
+					return jr.PathError(".MapInt8", err)
+				}
+			} else {
+				for i, l := 0, 8192; i < l; i++ {
+					var key string
+					{
+						sval, err := jr.ReadString(8192)
+						if err != nil {
+							if errors.Is(err, jsg.ErrLimitExceeded) {
+								return jr.PathError(".MapInt8", errors.New("string too long"))
+							}
+							return jr.PathError(".MapInt8", err)
+						}
+						key = string(sval)
+					}
+					if err := jr.ReadObjectColon(); err != nil {
+						return jr.PathError(".MapInt8", err)
+					}
+					var v int8
+					{
+
+						nval, err := jr.ReadNumberAsInt64()
+						if err != nil {
+							return jr.PathError(".MapInt8"+jsg.KeyPath(string(key)), err)
+						}
+
+						if nval < math.MinInt8 || nval > math.MaxInt8 {
+							return jr.PathError(".MapInt8"+jsg.KeyPath(string(key)), fmt.Errorf("value %d out of range for int8", nval))
+						}
+
+						v = int8(nval)
+
+					}
+					t.MapInt8[key] = v
+
+					close, err := jr.ReadObjectCloseOrComma()
+					if err != nil {
+						return jr.PathError(".MapInt8", err)
+					}
+					if close {
+						break
+					}
+					if i == l-1 {
+						return jr.PathError(".MapInt8", errors.New("map too large"))
+					}
+				}
+			}
+		}
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *IntWidths) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TupleWithDefaults) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("TupleWithDefaults: %w", err)
+	}
+
+	// t.Int1 (int64) (int64)
+
+	if err := jw.WriteInt64(int64(t.Int1)); err != nil {
+		return fmt.Errorf("t.Int1: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Int2: %w", err)
+	}
+
+	// t.Int2 (int64) (int64)
+
+	if err := jw.WriteInt64(int64(t.Int2)); err != nil {
+		return fmt.Errorf("t.Int2: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Str3: %w", err)
+	}
+
+	// t.Str3 (string) (string)
+	if len(t.Str3) > 8192 {
+		return fmt.Errorf("String in field t.Str3 was too long")
+	}
+	if err := jw.WriteString(string(t.Str3)); err != nil {
+		return fmt.Errorf("t.Str3: %w", err)
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("TupleWithDefaults: %w", err)
+	}
+	return nil
+}
+
+func (t *TupleWithDefaults) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TupleWithDefaults{}
+
+	t.Int2 = 7
+
+	t.Str3 = "three"
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadArrayOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	close, err := jr.PeekArrayClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+
+		// t.Int1 (int64) (int64)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int1", err)
+			}
+
+			t.Int1 = int64(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return nil
+			}
+		}
+
+		// t.Int2 (int64) (int64)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int2", err)
+			}
+
+			t.Int2 = int64(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return nil
+			}
+		}
+
+		// t.Str3 (string) (string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Str3", errors.New("string too long"))
+				}
+				return jr.PathError(".Str3", err)
+			}
+			t.Str3 = string(sval)
+		}
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *TupleWithDefaults) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TupleEnvelope) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("TupleEnvelope: %w", err)
+	}
+
+	// t.Header.Version (uint64) (uint64)
+
+	if err := jw.WriteUint64(uint64(t.Header.Version)); err != nil {
+		return fmt.Errorf("t.Header.Version: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Header.Sender: %w", err)
+	}
+
+	// t.Header.Sender (string) (string)
+	if len(t.Header.Sender) > 8192 {
+		return fmt.Errorf("String in field t.Header.Sender was too long")
+	}
+	if err := jw.WriteString(string(t.Header.Sender)); err != nil {
+		return fmt.Errorf("t.Header.Sender: %w", err)
+	}
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Body: %w", err)
+	}
+
+	// t.Body (string) (string)
+	if len(t.Body) > 8192 {
+		return fmt.Errorf("String in field t.Body was too long")
+	}
+	if err := jw.WriteString(string(t.Body)); err != nil {
+		return fmt.Errorf("t.Body: %w", err)
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("TupleEnvelope: %w", err)
+	}
+	return nil
+}
+
+func (t *TupleEnvelope) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TupleEnvelope{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadArrayOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	close, err := jr.PeekArrayClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+
+		// t.Header.Version (uint64) (uint64)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Header.Version", err)
+			}
+
+			t.Header.Version = uint64(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
+			}
+		}
+
+		// t.Header.Sender (string) (string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Header.Sender", errors.New("string too long"))
+				}
+				return jr.PathError(".Header.Sender", err)
+			}
+			t.Header.Sender = string(sval)
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
+			}
+		}
+
+		// t.Body (string) (string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Body", errors.New("string too long"))
+				}
+				return jr.PathError(".Body", err)
+			}
+			t.Body = string(sval)
+		}
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *TupleEnvelope) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
@@ -3,23 +3,29 @@
 package testing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"strconv"
 
 	jsg "github.com/alanshaw/dag-json-gen"
 	cid "github.com/ipfs/go-cid"
+	big "math/big"
 )
 
 var _ = cid.Undef
+var _ = bytes.NewReader
 var _ = math.E
 var _ = sort.Sort
+var _ = strconv.Itoa
 var _ = errors.Is
 
-func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -29,21 +35,24 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Dog (string) (string)
-	if len("Dog") > 8192 {
-		return fmt.Errorf("String in field \"Dog\" was too long")
+	// t.BoolPtr (bool) (bool)
+	if len("BoolPtr") > 8192 {
+		return fmt.Errorf("String in field \"BoolPtr\" was too long")
 	}
-	if err := jw.WriteString(string("Dog")); err != nil {
-		return fmt.Errorf("\"Dog\": %w", err)
+	if err := jw.WriteString(string("BoolPtr")); err != nil {
+		return fmt.Errorf("\"BoolPtr\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Dog) > 8192 {
-		return fmt.Errorf("String in field t.Dog was too long")
-	}
-	if err := jw.WriteString(string(t.Dog)); err != nil {
-		return fmt.Errorf("t.Dog: %w", err)
+	if t.BoolPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.BoolPtr: %w", err)
+		}
+	} else {
+		if err := jw.WriteBool(*t.BoolPtr); err != nil {
+			return fmt.Errorf("t.BoolPtr: %w", err)
+		}
 	}
 	written++
 	if written > 0 {
@@ -52,42 +61,22 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Test ([][]uint8) (slice)
-	if len("Test") > 8192 {
-		return fmt.Errorf("String in field \"Test\" was too long")
+	// t.Dog (string) (string)
+	if len("Dog") > 8192 {
+		return fmt.Errorf("String in field \"Dog\" was too long")
 	}
-	if err := jw.WriteString(string("Test")); err != nil {
-		return fmt.Errorf("\"Test\": %w", err)
+	if err := jw.WriteString(string("Dog")); err != nil {
+		return fmt.Errorf("\"Dog\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Test) > 8192 {
-		return fmt.Errorf("Slice value in field t.Test was too long")
-	}
-
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.Test: %w", err)
-	}
-	for i, v := range t.Test {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.Test: %w", err)
-			}
-		}
-		if len(v) > 2097152 {
-			return fmt.Errorf("Byte array in field v was too long")
-		}
-
-		if err := jw.WriteBytes(v); err != nil {
-			return fmt.Errorf("v: %w", err)
-		}
-
+	if len(t.Dog) > 8192 {
+		return fmt.Errorf("String in field t.Dog was too long")
 	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.Test: %w", err)
+	if err := jw.WriteString(string(t.Dog)); err != nil {
+		return fmt.Errorf("t.Dog: %w", err)
 	}
-
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -95,19 +84,27 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Stuff (testing.SimpleTypeTree) (struct)
-	if len("Stuff") > 8192 {
-		return fmt.Errorf("String in field \"Stuff\" was too long")
+	// t.NotPizza (uint64) (uint64)
+	if len("NotPizza") > 8192 {
+		return fmt.Errorf("String in field \"NotPizza\" was too long")
 	}
-	if err := jw.WriteString(string("Stuff")); err != nil {
-		return fmt.Errorf("\"Stuff\": %w", err)
+	if err := jw.WriteString(string("NotPizza")); err != nil {
+		return fmt.Errorf("\"NotPizza\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := t.Stuff.MarshalDagJSON(jw); err != nil {
-		return fmt.Errorf("t.Stuff: %w", err)
+
+	if t.NotPizza == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.NotPizza: %w", err)
+		}
+	} else {
+		if err := jw.WriteUint64(uint64(*t.NotPizza)); err != nil {
+			return fmt.Errorf("t.NotPizza: %w", err)
+		}
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -155,19 +152,21 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Stufff (testing.SimpleTypeTwo) (struct)
-	if len("Stufff") > 8192 {
-		return fmt.Errorf("String in field \"Stufff\" was too long")
+	// t.SixtyThreeBitIntegerWithASignBit (int64) (int64)
+	if len("SixtyThreeBitIntegerWithASignBit") > 8192 {
+		return fmt.Errorf("String in field \"SixtyThreeBitIntegerWithASignBit\" was too long")
 	}
-	if err := jw.WriteString(string("Stufff")); err != nil {
-		return fmt.Errorf("\"Stufff\": %w", err)
+	if err := jw.WriteString(string("SixtyThreeBitIntegerWithASignBit")); err != nil {
+		return fmt.Errorf("\"SixtyThreeBitIntegerWithASignBit\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := t.Stufff.MarshalDagJSON(jw); err != nil {
-		return fmt.Errorf("t.Stufff: %w", err)
+
+	if err := jw.WriteInt64(int64(t.SixtyThreeBitIntegerWithASignBit)); err != nil {
+		return fmt.Errorf("t.SixtyThreeBitIntegerWithASignBit: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -175,23 +174,26 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.BoolPtr (bool) (bool)
-	if len("BoolPtr") > 8192 {
-		return fmt.Errorf("String in field \"BoolPtr\" was too long")
+	// t.StringPtr (string) (string)
+	if len("StringPtr") > 8192 {
+		return fmt.Errorf("String in field \"StringPtr\" was too long")
 	}
-	if err := jw.WriteString(string("BoolPtr")); err != nil {
-		return fmt.Errorf("\"BoolPtr\": %w", err)
+	if err := jw.WriteString(string("StringPtr")); err != nil {
+		return fmt.Errorf("\"StringPtr\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if t.BoolPtr == nil {
+	if t.StringPtr == nil {
 		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.BoolPtr: %w", err)
+			return fmt.Errorf("t.StringPtr: %w", err)
 		}
 	} else {
-		if err := jw.WriteBool(*t.BoolPtr); err != nil {
-			return fmt.Errorf("t.BoolPtr: %w", err)
+		if len(*t.StringPtr) > 8192 {
+			return fmt.Errorf("String in field t.StringPtr was too long")
+		}
+		if err := jw.WriteString(string(*t.StringPtr)); err != nil {
+			return fmt.Errorf("t.StringPtr: %w", err)
 		}
 	}
 	written++
@@ -201,27 +203,19 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.NotPizza (uint64) (uint64)
-	if len("NotPizza") > 8192 {
-		return fmt.Errorf("String in field \"NotPizza\" was too long")
+	// t.Stuff (testing.SimpleTypeTree) (struct)
+	if len("Stuff") > 8192 {
+		return fmt.Errorf("String in field \"Stuff\" was too long")
 	}
-	if err := jw.WriteString(string("NotPizza")); err != nil {
-		return fmt.Errorf("\"NotPizza\": %w", err)
+	if err := jw.WriteString(string("Stuff")); err != nil {
+		return fmt.Errorf("\"Stuff\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-
-	if t.NotPizza == nil {
-		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.NotPizza: %w", err)
-		}
-	} else {
-		if err := jw.WriteUint64(uint64(*t.NotPizza)); err != nil {
-			return fmt.Errorf("t.NotPizza: %w", err)
-		}
+	if err := t.Stuff.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Stuff: %w", err)
 	}
-
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -229,27 +223,18 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.StringPtr (string) (string)
-	if len("StringPtr") > 8192 {
-		return fmt.Errorf("String in field \"StringPtr\" was too long")
+	// t.Stufff (testing.SimpleTypeTwo) (struct)
+	if len("Stufff") > 8192 {
+		return fmt.Errorf("String in field \"Stufff\" was too long")
 	}
-	if err := jw.WriteString(string("StringPtr")); err != nil {
-		return fmt.Errorf("\"StringPtr\": %w", err)
+	if err := jw.WriteString(string("Stufff")); err != nil {
+		return fmt.Errorf("\"Stufff\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if t.StringPtr == nil {
-		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.StringPtr: %w", err)
-		}
-	} else {
-		if len(*t.StringPtr) > 8192 {
-			return fmt.Errorf("String in field t.StringPtr was too long")
-		}
-		if err := jw.WriteString(string(*t.StringPtr)); err != nil {
-			return fmt.Errorf("t.StringPtr: %w", err)
-		}
+	if err := t.Stufff.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Stufff: %w", err)
 	}
 	written++
 	if written > 0 {
@@ -258,19 +243,40 @@ func (t *SimpleTypeTree) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.SixtyThreeBitIntegerWithASignBit (int64) (int64)
-	if len("SixtyThreeBitIntegerWithASignBit") > 8192 {
-		return fmt.Errorf("String in field \"SixtyThreeBitIntegerWithASignBit\" was too long")
+	// t.Test ([][]uint8) (slice)
+	if len("Test") > 8192 {
+		return fmt.Errorf("String in field \"Test\" was too long")
 	}
-	if err := jw.WriteString(string("SixtyThreeBitIntegerWithASignBit")); err != nil {
-		return fmt.Errorf("\"SixtyThreeBitIntegerWithASignBit\": %w", err)
+	if err := jw.WriteString(string("Test")); err != nil {
+		return fmt.Errorf("\"Test\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
+	if len(t.Test) > 8192 {
+		return fmt.Errorf("Slice value in field t.Test was too long")
+	}
 
-	if err := jw.WriteInt64(int64(t.SixtyThreeBitIntegerWithASignBit)); err != nil {
-		return fmt.Errorf("t.SixtyThreeBitIntegerWithASignBit: %w", err)
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Test: %w", err)
+	}
+	for i, v := range t.Test {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Test: %w", err)
+			}
+		}
+		if len(v) > 2097152 {
+			return fmt.Errorf("Byte array in field v was too long")
+		}
+
+		if err := jw.WriteBytes(v); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Test: %w", err)
 	}
 
 	written++
@@ -283,164 +289,172 @@ func (t *SimpleTypeTree) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = SimpleTypeTree{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("SimpleTypeTree: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("SimpleTypeTree: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("SimpleTypeTree: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("SimpleTypeTree: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("SimpleTypeTree: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("SimpleTypeTree: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Dog (string) (string)
+			// t.BoolPtr (bool) (bool)
+			case "BoolPtr":
+				{
+					bval, err := jr.ReadBoolOrNull()
+					if err != nil {
+						return jr.PathError(".BoolPtr", err)
+					}
+					if bval != nil {
+						t.BoolPtr = bval
+					}
+				}
+
+				// t.Dog (string) (string)
 			case "Dog":
 				{
 					sval, err := jr.ReadString(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Dog: string too long")
+							return jr.PathError(".Dog", errors.New("string too long"))
 						}
-						return fmt.Errorf("t.Dog: %w", err)
+						return jr.PathError(".Dog", err)
 					}
 					t.Dog = string(sval)
 				}
 
-				// t.Test ([][]uint8) (slice)
-			case "Test":
+				// t.NotPizza (uint64) (uint64)
+			case "NotPizza":
+				{
+
+					nval, err := jr.ReadNumberAsUint64OrNull()
+					if err != nil {
+						return jr.PathError(".NotPizza", err)
+					}
+					if nval != nil {
+
+						typed := uint64(*nval)
+						t.NotPizza = &typed
+					}
+
+				}
+
+				// t.Others ([]uint64) (slice)
+			case "Others":
 				{
 
 					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.Test: %w", err)
+						return jr.PathError(".Others", err)
 					}
 
 					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.Test: %w", err)
+						return jr.PathError(".Others", err)
 					}
 					if close {
 						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.Test: %w", err)
+							return jr.PathError(".Others", err)
 						}
 
 					} else {
 						for i := 0; i < 8192; i++ {
-							item := make([][]uint8, 1)
-
+							item := make([]uint64, 1)
 							{
-								bval, err := jr.ReadBytes(2097152)
+
+								nval, err := jr.ReadNumberAsUint64()
 								if err != nil {
-									if errors.Is(err, jsg.ErrLimitExceeded) {
-										return fmt.Errorf("item[0]: byte array too large")
-									}
-									return fmt.Errorf("item[0]: %w", err)
-								}
-								if len(bval) > 0 {
-									item[0] = []uint8(bval)
+									return jr.PathError(".Others"+jsg.IndexPath(i), err)
 								}
-							}
 
-							t.Test = append(t.Test, item[0])
+								item[0] = uint64(nval)
+
+							}
+							t.Others = append(t.Others, item[0])
 
 							close, err := jr.ReadArrayCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.Test: %w", err)
+								return jr.PathError(".Others", err)
 							}
 							if close {
 								break
 							}
 							if i == 8192-1 {
-								return fmt.Errorf("t.Test: slice too large")
+								return jr.PathError(".Others", errors.New("slice too large"))
 							}
 						}
 					}
 
 				}
 
-				// t.Stuff (testing.SimpleTypeTree) (struct)
-			case "Stuff":
-
+				// t.SixtyThreeBitIntegerWithASignBit (int64) (int64)
+			case "SixtyThreeBitIntegerWithASignBit":
 				{
-					null, err := jr.PeekNull()
+
+					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("t.Stuff: %w", err)
-					}
-					if null {
-						if err := jr.ReadNull(); err != nil {
-							return fmt.Errorf("t.Stuff: %w", err)
-						}
-					} else {
-						t.Stuff = new(SimpleTypeTree)
-						if err := t.Stuff.UnmarshalDagJSON(jr); err != nil {
-							return fmt.Errorf("unmarshaling t.Stuff pointer: %w", err)
-						}
+						return jr.PathError(".SixtyThreeBitIntegerWithASignBit", err)
 					}
+
+					t.SixtyThreeBitIntegerWithASignBit = int64(nval)
+
 				}
 
-				// t.Others ([]uint64) (slice)
-			case "Others":
+				// t.StringPtr (string) (string)
+			case "StringPtr":
 				{
-
-					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.Others: %w", err)
+					sval, err := jr.ReadStringOrNull(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".StringPtr", errors.New("string too long"))
+						}
+						return jr.PathError(".StringPtr", err)
 					}
+					if sval != nil {
+						t.StringPtr = (*string)(sval)
+					}
+				}
 
-					close, err := jr.PeekArrayClose()
+				// t.Stuff (testing.SimpleTypeTree) (struct)
+			case "Stuff":
+
+				{
+					null, err := jr.PeekNull()
 					if err != nil {
-						return fmt.Errorf("t.Others: %w", err)
+						return jr.PathError(".Stuff", err)
 					}
-					if close {
-						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.Others: %w", err)
+					if null {
+						if err := jr.ReadNull(); err != nil {
+							return jr.PathError(".Stuff", err)
 						}
-
 					} else {
-						for i := 0; i < 8192; i++ {
-							item := make([]uint64, 1)
-							{
-
-								nval, err := jr.ReadNumberAsUint64()
-								if err != nil {
-									return fmt.Errorf("item[0]: %w", err)
-								}
-								item[0] = uint64(nval)
-
-							}
-							t.Others = append(t.Others, item[0])
-
-							close, err := jr.ReadArrayCloseOrComma()
-							if err != nil {
-								return fmt.Errorf("t.Others: %w", err)
-							}
-							if close {
-								break
-							}
-							if i == 8192-1 {
-								return fmt.Errorf("t.Others: slice too large")
-							}
+						t.Stuff = new(SimpleTypeTree)
+						if err := t.Stuff.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(".Stuff", err)
 						}
 					}
-
 				}
 
 				// t.Stufff (testing.SimpleTypeTwo) (struct)
@@ -449,97 +463,117 @@ func (t *SimpleTypeTree) UnmarshalDagJSON(r io.Reader) (err error) {
 				{
 					null, err := jr.PeekNull()
 					if err != nil {
-						return fmt.Errorf("t.Stufff: %w", err)
+						return jr.PathError(".Stufff", err)
 					}
 					if null {
 						if err := jr.ReadNull(); err != nil {
-							return fmt.Errorf("t.Stufff: %w", err)
+							return jr.PathError(".Stufff", err)
 						}
 					} else {
 						t.Stufff = new(SimpleTypeTwo)
 						if err := t.Stufff.UnmarshalDagJSON(jr); err != nil {
-							return fmt.Errorf("unmarshaling t.Stufff pointer: %w", err)
+							return jr.PathError(".Stufff", err)
 						}
 					}
 				}
 
-				// t.BoolPtr (bool) (bool)
-			case "BoolPtr":
+				// t.Test ([][]uint8) (slice)
+			case "Test":
 				{
-					bval, err := jr.ReadBoolOrNull()
-					if err != nil {
-						return fmt.Errorf("t.BoolPtr: %w", err)
-					}
-					if bval != nil {
-						t.BoolPtr = bval
-					}
-				}
 
-				// t.NotPizza (uint64) (uint64)
-			case "NotPizza":
-				{
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Test", err)
+					}
 
-					nval, err := jr.ReadNumberAsUint64OrNull()
+					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.NotPizza: %w", err)
-					}
-					if nval != nil {
-						typed := uint64(*nval)
-						t.NotPizza = &typed
+						return jr.PathError(".Test", err)
 					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Test", err)
+						}
 
-				}
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([][]uint8, 1)
 
-				// t.StringPtr (string) (string)
-			case "StringPtr":
-				{
-					sval, err := jr.ReadStringOrNull(8192)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.StringPtr: string too long")
-						}
-						return fmt.Errorf("t.StringPtr: %w", err)
-					}
-					if sval != nil {
-						t.StringPtr = (*string)(sval)
-					}
-				}
+							{
+								bval, err := jr.ReadBytes(2097152)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Test"+jsg.IndexPath(i), errors.New("byte array too large"))
+									}
+									return jr.PathError(".Test"+jsg.IndexPath(i), err)
+								}
+								if len(bval) > 0 {
+									item[0] = []uint8(bval)
+								}
+							}
 
-				// t.SixtyThreeBitIntegerWithASignBit (int64) (int64)
-			case "SixtyThreeBitIntegerWithASignBit":
-				{
+							t.Test = append(t.Test, item[0])
 
-					nval, err := jr.ReadNumberAsInt64()
-					if err != nil {
-						return fmt.Errorf("t.SixtyThreeBitIntegerWithASignBit: %w", err)
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Test", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Test", errors.New("slice too large"))
+							}
+						}
 					}
-					t.SixtyThreeBitIntegerWithASignBit = int64(nval)
 
 				}
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("SimpleTypeTree: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleTypeTree: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("SimpleTypeTree: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *NeedScratchForMap) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SimpleTypeTree) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Stuff != nil {
+		if err := jsg.ScanLinks(t.Stuff, cb); err != nil {
+			return fmt.Errorf("t.Stuff: %w", err)
+		}
+	}
+
+	if t.Stufff != nil {
+		if err := jsg.ScanLinks(t.Stufff, cb); err != nil {
+			return fmt.Errorf("t.Stufff: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *NeedScratchForMap) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -570,67 +604,81 @@ func (t *NeedScratchForMap) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = NeedScratchForMap{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("NeedScratchForMap: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("NeedScratchForMap: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("NeedScratchForMap: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("NeedScratchForMap: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("NeedScratchForMap: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("NeedScratchForMap: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
 			// t.Thing (bool) (bool)
 			case "Thing":
-				bval, err := jr.ReadBool()
-				if err != nil {
-					return fmt.Errorf("t.Thing: %w", err)
+				{
+					bval, err := jr.ReadBool()
+					if err != nil {
+						return jr.PathError(".Thing", err)
+					}
+					t.Thing = bool(bval)
 				}
-				t.Thing = bval
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("NeedScratchForMap: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("NeedScratchForMap: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("NeedScratchForMap: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *NeedScratchForMap) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -640,55 +688,36 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.OldMap (map[string]testing.SimpleTypeOne) (map)
-	if len("OldMap") > 8192 {
-		return fmt.Errorf("String in field \"OldMap\" was too long")
+	// t.OldArray ([]testing.SimpleTypeOne) (slice)
+	if len("OldArray") > 8192 {
+		return fmt.Errorf("String in field \"OldArray\" was too long")
 	}
-	if err := jw.WriteString(string("OldMap")); err != nil {
-		return fmt.Errorf("\"OldMap\": %w", err)
+	if err := jw.WriteString(string("OldArray")); err != nil {
+		return fmt.Errorf("\"OldArray\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	{
-		if len(t.OldMap) > 4096 {
-			return fmt.Errorf("cannot marshal t.OldMap map too large")
-		}
-
-		if err := jw.WriteObjectOpen(); err != nil {
-			return fmt.Errorf("t.OldMap: %w", err)
-		}
-
-		keys := make([]string, 0, len(t.OldMap))
-		for k := range t.OldMap {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for i, k := range keys {
-			if i > 0 {
-				if err := jw.WriteComma(); err != nil {
-					return fmt.Errorf("t.OldMap: %w", err)
-				}
-			}
-			v := t.OldMap[k]
-			if len(k) > 8192 {
-				return fmt.Errorf("String in field k was too long")
-			}
-			if err := jw.WriteString(string(k)); err != nil {
-				return fmt.Errorf("k: %w", err)
-			}
-			if err := jw.WriteObjectColon(); err != nil {
-				return fmt.Errorf("t.OldMap: %w", err)
-			}
+	if len(t.OldArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldArray was too long")
+	}
 
-			if err := v.MarshalDagJSON(jw); err != nil {
-				return fmt.Errorf("v: %w", err)
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
+	for i, v := range t.OldArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldArray: %w", err)
 			}
 		}
-		if err := jw.WriteObjectClose(); err != nil {
-			return fmt.Errorf("t.OldMap: %w", err)
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
 		}
 	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
 
 	written++
 	if written > 0 {
@@ -697,19 +726,22 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldNum (uint64) (uint64)
-	if len("OldNum") > 8192 {
-		return fmt.Errorf("String in field \"OldNum\" was too long")
+	// t.OldBytes ([]uint8) (slice)
+	if len("OldBytes") > 8192 {
+		return fmt.Errorf("String in field \"OldBytes\" was too long")
 	}
-	if err := jw.WriteString(string("OldNum")); err != nil {
-		return fmt.Errorf("\"OldNum\": %w", err)
+	if err := jw.WriteString(string("OldBytes")); err != nil {
+		return fmt.Errorf("\"OldBytes\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
+	if len(t.OldBytes) > 2097152 {
+		return fmt.Errorf("Byte array in field t.OldBytes was too long")
+	}
 
-	if err := jw.WriteUint64(uint64(t.OldNum)); err != nil {
-		return fmt.Errorf("t.OldNum: %w", err)
+	if err := jw.WriteBytes(t.OldBytes); err != nil {
+		return fmt.Errorf("t.OldBytes: %w", err)
 	}
 
 	written++
@@ -719,50 +751,39 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldPtr (cid.Cid) (struct)
-	if len("OldPtr") > 8192 {
-		return fmt.Errorf("String in field \"OldPtr\" was too long")
+	// t.OldCidArray ([]cid.Cid) (slice)
+	if len("OldCidArray") > 8192 {
+		return fmt.Errorf("String in field \"OldCidArray\" was too long")
 	}
-	if err := jw.WriteString(string("OldPtr")); err != nil {
-		return fmt.Errorf("\"OldPtr\": %w", err)
+	if err := jw.WriteString(string("OldCidArray")); err != nil {
+		return fmt.Errorf("\"OldCidArray\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
+	if len(t.OldCidArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldCidArray was too long")
+	}
 
-	if t.OldPtr == nil {
-		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.OldPtr: %w", err)
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldCidArray: %w", err)
+	}
+	for i, v := range t.OldCidArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldCidArray: %w", err)
+			}
 		}
-	} else {
-		if err := jw.WriteCid(*t.OldPtr); err != nil {
-			return fmt.Errorf("t.OldPtr: %w", err)
+
+		if err := jw.WriteCid(v); err != nil {
+			return fmt.Errorf("v: %w", err)
 		}
-	}
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
-		}
-	}
-
-	// t.OldStr (string) (string)
-	if len("OldStr") > 8192 {
-		return fmt.Errorf("String in field \"OldStr\" was too long")
-	}
-	if err := jw.WriteString(string("OldStr")); err != nil {
-		return fmt.Errorf("\"OldStr\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-	if len(t.OldStr) > 8192 {
-		return fmt.Errorf("String in field t.OldStr was too long")
 	}
-	if err := jw.WriteString(string(t.OldStr)); err != nil {
-		return fmt.Errorf("t.OldStr: %w", err)
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldCidArray: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -770,35 +791,43 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldArray ([]testing.SimpleTypeOne) (slice)
-	if len("OldArray") > 8192 {
-		return fmt.Errorf("String in field \"OldArray\" was too long")
+	// t.OldCidPtrArray ([]*cid.Cid) (slice)
+	if len("OldCidPtrArray") > 8192 {
+		return fmt.Errorf("String in field \"OldCidPtrArray\" was too long")
 	}
-	if err := jw.WriteString(string("OldArray")); err != nil {
-		return fmt.Errorf("\"OldArray\": %w", err)
+	if err := jw.WriteString(string("OldCidPtrArray")); err != nil {
+		return fmt.Errorf("\"OldCidPtrArray\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.OldArray) > 8192 {
-		return fmt.Errorf("Slice value in field t.OldArray was too long")
+	if len(t.OldCidPtrArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldCidPtrArray was too long")
 	}
 
 	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.OldArray: %w", err)
+		return fmt.Errorf("t.OldCidPtrArray: %w", err)
 	}
-	for i, v := range t.OldArray {
+	for i, v := range t.OldCidPtrArray {
 		if i > 0 {
 			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.OldArray: %w", err)
+				return fmt.Errorf("t.OldCidPtrArray: %w", err)
 			}
 		}
-		if err := v.MarshalDagJSON(jw); err != nil {
-			return fmt.Errorf("v: %w", err)
+
+		if v == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		} else {
+			if err := jw.WriteCid(*v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
 		}
+
 	}
 	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.OldArray: %w", err)
+		return fmt.Errorf("t.OldCidPtrArray: %w", err)
 	}
 
 	written++
@@ -808,22 +837,58 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldBytes ([]uint8) (slice)
-	if len("OldBytes") > 8192 {
-		return fmt.Errorf("String in field \"OldBytes\" was too long")
+	// t.OldMap (map[string]testing.SimpleTypeOne) (map)
+	if len("OldMap") > 8192 {
+		return fmt.Errorf("String in field \"OldMap\" was too long")
 	}
-	if err := jw.WriteString(string("OldBytes")); err != nil {
-		return fmt.Errorf("\"OldBytes\": %w", err)
+	if err := jw.WriteString(string("OldMap")); err != nil {
+		return fmt.Errorf("\"OldMap\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.OldBytes) > 2097152 {
-		return fmt.Errorf("Byte array in field t.OldBytes was too long")
-	}
+	{
+		if len(t.OldMap) > 4096 {
+			return fmt.Errorf("cannot marshal t.OldMap map too large")
+		}
 
-	if err := jw.WriteBytes(t.OldBytes); err != nil {
-		return fmt.Errorf("t.OldBytes: %w", err)
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.OldMap: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.OldMap))
+
+		for k := range t.OldMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.OldMap: %w", err)
+				}
+			}
+
+			v := t.OldMap[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.OldMap: %w", err)
+			}
+
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.OldMap: %w", err)
+		}
 	}
 
 	written++
@@ -833,19 +898,21 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldStruct (testing.SimpleTypeOne) (struct)
-	if len("OldStruct") > 8192 {
-		return fmt.Errorf("String in field \"OldStruct\" was too long")
+	// t.OldNum (uint64) (uint64)
+	if len("OldNum") > 8192 {
+		return fmt.Errorf("String in field \"OldNum\" was too long")
 	}
-	if err := jw.WriteString(string("OldStruct")); err != nil {
-		return fmt.Errorf("\"OldStruct\": %w", err)
+	if err := jw.WriteString(string("OldNum")); err != nil {
+		return fmt.Errorf("\"OldNum\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := t.OldStruct.MarshalDagJSON(jw); err != nil {
-		return fmt.Errorf("t.OldStruct: %w", err)
+
+	if err := jw.WriteUint64(uint64(t.OldNum)); err != nil {
+		return fmt.Errorf("t.OldNum: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -853,37 +920,25 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldCidArray ([]cid.Cid) (slice)
-	if len("OldCidArray") > 8192 {
-		return fmt.Errorf("String in field \"OldCidArray\" was too long")
+	// t.OldPtr (cid.Cid) (struct)
+	if len("OldPtr") > 8192 {
+		return fmt.Errorf("String in field \"OldPtr\" was too long")
 	}
-	if err := jw.WriteString(string("OldCidArray")); err != nil {
-		return fmt.Errorf("\"OldCidArray\": %w", err)
+	if err := jw.WriteString(string("OldPtr")); err != nil {
+		return fmt.Errorf("\"OldPtr\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.OldCidArray) > 8192 {
-		return fmt.Errorf("Slice value in field t.OldCidArray was too long")
-	}
 
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.OldCidArray: %w", err)
-	}
-	for i, v := range t.OldCidArray {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.OldCidArray: %w", err)
-			}
+	if t.OldPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.OldPtr: %w", err)
 		}
-
-		if err := jw.WriteCid(v); err != nil {
-			return fmt.Errorf("v: %w", err)
+	} else {
+		if err := jw.WriteCid(*t.OldPtr); err != nil {
+			return fmt.Errorf("t.OldPtr: %w", err)
 		}
-
-	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.OldCidArray: %w", err)
 	}
 
 	written++
@@ -893,45 +948,42 @@ func (t *SimpleStructV1) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldCidPtrArray ([]*cid.Cid) (slice)
-	if len("OldCidPtrArray") > 8192 {
-		return fmt.Errorf("String in field \"OldCidPtrArray\" was too long")
+	// t.OldStr (string) (string)
+	if len("OldStr") > 8192 {
+		return fmt.Errorf("String in field \"OldStr\" was too long")
 	}
-	if err := jw.WriteString(string("OldCidPtrArray")); err != nil {
-		return fmt.Errorf("\"OldCidPtrArray\": %w", err)
+	if err := jw.WriteString(string("OldStr")); err != nil {
+		return fmt.Errorf("\"OldStr\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.OldCidPtrArray) > 8192 {
-		return fmt.Errorf("Slice value in field t.OldCidPtrArray was too long")
+	if len(t.OldStr) > 8192 {
+		return fmt.Errorf("String in field t.OldStr was too long")
 	}
-
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.OldCidPtrArray: %w", err)
+	if err := jw.WriteString(string(t.OldStr)); err != nil {
+		return fmt.Errorf("t.OldStr: %w", err)
 	}
-	for i, v := range t.OldCidPtrArray {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.OldCidPtrArray: %w", err)
-			}
-		}
-
-		if v == nil {
-			if err := jw.WriteNull(); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
-		} else {
-			if err := jw.WriteCid(*v); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
 		}
+	}
 
+	// t.OldStruct (testing.SimpleTypeOne) (struct)
+	if len("OldStruct") > 8192 {
+		return fmt.Errorf("String in field \"OldStruct\" was too long")
 	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.OldCidPtrArray: %w", err)
+	if err := jw.WriteString(string("OldStruct")); err != nil {
+		return fmt.Errorf("\"OldStruct\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.OldStruct.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
 	}
-
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
@@ -942,435 +994,543 @@ func (t *SimpleStructV1) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = SimpleStructV1{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("SimpleStructV1: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("SimpleStructV1: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("SimpleStructV1: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("SimpleStructV1: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("SimpleStructV1: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("SimpleStructV1: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.OldMap (map[string]testing.SimpleTypeOne) (map)
-			case "OldMap":
-				if err := jr.ReadObjectOpen(); err != nil {
-					return fmt.Errorf("t.OldMap: %w", err)
-				}
+			// t.OldArray ([]testing.SimpleTypeOne) (slice)
+			case "OldArray":
+				{
 
-				t.OldMap = map[string]SimpleTypeOne{}
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".OldArray", err)
+					}
 
-				close, err := jr.PeekObjectClose()
-				if err != nil {
-					return fmt.Errorf("t.OldMap: %w", err)
-				}
-				if close {
-					if err := jr.ReadObjectClose(); err != nil {
-						return fmt.Errorf("t.OldMap: %w", err)
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".OldArray", err)
 					}
-				} else {
-					for i, l := 0, 8192; i < l; i++ {
-						var k string
-						{
-							sval, err := jr.ReadString(8192)
-							if err != nil {
-								if errors.Is(err, jsg.ErrLimitExceeded) {
-									return fmt.Errorf("k: string too long")
-								}
-								return fmt.Errorf("k: %w", err)
-							}
-							k = string(sval)
-						}
-						if err := jr.ReadObjectColon(); err != nil {
-							return fmt.Errorf("t.OldMap: %w", err)
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".OldArray", err)
 						}
-						var v SimpleTypeOne
 
-						if err := v.UnmarshalDagJSON(jr); err != nil {
-							return fmt.Errorf("unmarshaling v: %w", err)
-						}
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]SimpleTypeOne, 1)
 
-						t.OldMap[k] = v
-						close, err := jr.ReadObjectCloseOrComma()
-						if err != nil {
-							return fmt.Errorf("t.OldMap: %w", err)
-						}
-						if close {
-							break
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldArray"+jsg.IndexPath(i), err)
+							}
+
+							t.OldArray = append(t.OldArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".OldArray", errors.New("slice too large"))
+							}
 						}
 					}
+
 				}
 
-				// t.OldNum (uint64) (uint64)
-			case "OldNum":
-				{
-
-					nval, err := jr.ReadNumberAsUint64()
-					if err != nil {
-						return fmt.Errorf("t.OldNum: %w", err)
-					}
-					t.OldNum = uint64(nval)
-
-				}
-
-				// t.OldPtr (cid.Cid) (struct)
-			case "OldPtr":
-				{
-
-					c, err := jr.ReadCidOrNull()
-					if err != nil {
-						return fmt.Errorf("t.OldPtr: %w", err)
-					}
-					t.OldPtr = c
-
-				}
+				// t.OldBytes ([]uint8) (slice)
+			case "OldBytes":
 
-				// t.OldStr (string) (string)
-			case "OldStr":
 				{
-					sval, err := jr.ReadString(8192)
+					bval, err := jr.ReadBytes(2097152)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.OldStr: string too long")
+							return jr.PathError(".OldBytes", errors.New("byte array too large"))
 						}
-						return fmt.Errorf("t.OldStr: %w", err)
+						return jr.PathError(".OldBytes", err)
+					}
+					if len(bval) > 0 {
+						t.OldBytes = []uint8(bval)
 					}
-					t.OldStr = string(sval)
 				}
 
-				// t.OldArray ([]testing.SimpleTypeOne) (slice)
-			case "OldArray":
+				// t.OldCidArray ([]cid.Cid) (slice)
+			case "OldCidArray":
 				{
 
 					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.OldArray: %w", err)
+						return jr.PathError(".OldCidArray", err)
 					}
 
 					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.OldArray: %w", err)
+						return jr.PathError(".OldCidArray", err)
 					}
 					if close {
 						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.OldArray: %w", err)
+							return jr.PathError(".OldCidArray", err)
 						}
 
 					} else {
 						for i := 0; i < 8192; i++ {
-							item := make([]SimpleTypeOne, 1)
+							item := make([]cid.Cid, 1)
+							{
 
-							if err := item[0].UnmarshalDagJSON(jr); err != nil {
-								return fmt.Errorf("unmarshaling item[0]: %w", err)
-							}
+								c, err := jr.ReadCid()
+								if err != nil {
+									return jr.PathError(".OldCidArray"+jsg.IndexPath(i), err)
+								}
+								item[0] = c
 
-							t.OldArray = append(t.OldArray, item[0])
+							}
+							t.OldCidArray = append(t.OldCidArray, item[0])
 
 							close, err := jr.ReadArrayCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.OldArray: %w", err)
+								return jr.PathError(".OldCidArray", err)
 							}
 							if close {
 								break
 							}
 							if i == 8192-1 {
-								return fmt.Errorf("t.OldArray: slice too large")
+								return jr.PathError(".OldCidArray", errors.New("slice too large"))
 							}
 						}
 					}
 
 				}
 
-				// t.OldBytes ([]uint8) (slice)
-			case "OldBytes":
-
-				{
-					bval, err := jr.ReadBytes(2097152)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.OldBytes: byte array too large")
-						}
-						return fmt.Errorf("t.OldBytes: %w", err)
-					}
-					if len(bval) > 0 {
-						t.OldBytes = []uint8(bval)
-					}
-				}
-
-				// t.OldStruct (testing.SimpleTypeOne) (struct)
-			case "OldStruct":
-
-				if err := t.OldStruct.UnmarshalDagJSON(jr); err != nil {
-					return fmt.Errorf("unmarshaling t.OldStruct: %w", err)
-				}
-
-				// t.OldCidArray ([]cid.Cid) (slice)
-			case "OldCidArray":
+				// t.OldCidPtrArray ([]*cid.Cid) (slice)
+			case "OldCidPtrArray":
 				{
 
 					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.OldCidArray: %w", err)
+						return jr.PathError(".OldCidPtrArray", err)
 					}
 
 					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.OldCidArray: %w", err)
+						return jr.PathError(".OldCidPtrArray", err)
 					}
 					if close {
 						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.OldCidArray: %w", err)
+							return jr.PathError(".OldCidPtrArray", err)
 						}
 
 					} else {
 						for i := 0; i < 8192; i++ {
-							item := make([]cid.Cid, 1)
+							item := make([]*cid.Cid, 1)
 							{
 
-								c, err := jr.ReadCid()
+								c, err := jr.ReadCidOrNull()
 								if err != nil {
-									return fmt.Errorf("item[0]: %w", err)
+									return jr.PathError(".OldCidPtrArray"+jsg.IndexPath(i), err)
 								}
 								item[0] = c
 
 							}
-							t.OldCidArray = append(t.OldCidArray, item[0])
+							t.OldCidPtrArray = append(t.OldCidPtrArray, item[0])
 
 							close, err := jr.ReadArrayCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.OldCidArray: %w", err)
+								return jr.PathError(".OldCidPtrArray", err)
 							}
 							if close {
 								break
 							}
 							if i == 8192-1 {
-								return fmt.Errorf("t.OldCidArray: slice too large")
+								return jr.PathError(".OldCidPtrArray", errors.New("slice too large"))
 							}
 						}
 					}
 
 				}
 
-				// t.OldCidPtrArray ([]*cid.Cid) (slice)
-			case "OldCidPtrArray":
+				// t.OldMap (map[string]testing.SimpleTypeOne) (map)
+			case "OldMap":
 				{
-
-					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.OldCidPtrArray: %w", err)
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".OldMap", err)
 					}
 
-					close, err := jr.PeekArrayClose()
+					t.OldMap = map[string]SimpleTypeOne{}
+
+					close, err := jr.PeekObjectClose()
 					if err != nil {
-						return fmt.Errorf("t.OldCidPtrArray: %w", err)
+						return jr.PathError(".OldMap", err)
 					}
 					if close {
-						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.OldCidPtrArray: %w", err)
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".OldMap", err)
 						}
-
 					} else {
-						for i := 0; i < 8192; i++ {
-							item := make([]*cid.Cid, 1)
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
 							{
-
-								c, err := jr.ReadCidOrNull()
+								sval, err := jr.ReadString(8192)
 								if err != nil {
-									return fmt.Errorf("item[0]: %w", err)
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".OldMap", errors.New("string too long"))
+									}
+									return jr.PathError(".OldMap", err)
 								}
-								item[0] = c
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".OldMap", err)
+							}
+							var v SimpleTypeOne
 
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldMap"+jsg.KeyPath(string(key)), err)
 							}
-							t.OldCidPtrArray = append(t.OldCidPtrArray, item[0])
 
-							close, err := jr.ReadArrayCloseOrComma()
+							t.OldMap[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.OldCidPtrArray: %w", err)
+								return jr.PathError(".OldMap", err)
 							}
 							if close {
 								break
 							}
-							if i == 8192-1 {
-								return fmt.Errorf("t.OldCidPtrArray: slice too large")
+							if i == l-1 {
+								return jr.PathError(".OldMap", errors.New("map too large"))
 							}
 						}
 					}
+				}
+
+				// t.OldNum (uint64) (uint64)
+			case "OldNum":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".OldNum", err)
+					}
+
+					t.OldNum = uint64(nval)
+
+				}
+
+				// t.OldPtr (cid.Cid) (struct)
+			case "OldPtr":
+				{
+
+					c, err := jr.ReadCidOrNull()
+					if err != nil {
+						return jr.PathError(".OldPtr", err)
+					}
+					t.OldPtr = c
+
+				}
+
+				// t.OldStr (string) (string)
+			case "OldStr":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".OldStr", errors.New("string too long"))
+						}
+						return jr.PathError(".OldStr", err)
+					}
+					t.OldStr = string(sval)
+				}
+
+				// t.OldStruct (testing.SimpleTypeOne) (struct)
+			case "OldStruct":
 
+				if err := t.OldStruct.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".OldStruct", err)
 				}
+
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("SimpleStructV1: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleStructV1: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("SimpleStructV1: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *SimpleStructV1) ScanDagJSONLinks(cb func(cid.Cid)) error {
 	if t == nil {
-		err := jw.WriteNull()
-		return err
-	}
-	if err := jw.WriteObjectOpen(); err != nil {
-		return err
+		return nil
 	}
-	written := 0
+	for _, v := range t.OldArray {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
 
-	// t.NewMap (map[string]testing.SimpleTypeOne) (map)
-	if len("NewMap") > 8192 {
-		return fmt.Errorf("String in field \"NewMap\" was too long")
 	}
-	if err := jw.WriteString(string("NewMap")); err != nil {
-		return fmt.Errorf("\"NewMap\": %w", err)
+	for _, v := range t.OldCidArray {
+
+		if v.Defined() {
+			cb(v)
+		}
+
 	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
+	for _, v := range t.OldCidPtrArray {
+
+		if v != nil && v.Defined() {
+			cb(*v)
+		}
+
 	}
 	{
-		if len(t.NewMap) > 4096 {
-			return fmt.Errorf("cannot marshal t.NewMap map too large")
-		}
+		keys := make([]string, 0, len(t.OldMap))
 
-		if err := jw.WriteObjectOpen(); err != nil {
-			return fmt.Errorf("t.NewMap: %w", err)
+		for k := range t.OldMap {
+			keys = append(keys, string(k))
 		}
 
-		keys := make([]string, 0, len(t.NewMap))
-		for k := range t.NewMap {
-			keys = append(keys, k)
-		}
 		sort.Strings(keys)
-		for i, k := range keys {
-			if i > 0 {
-				if err := jw.WriteComma(); err != nil {
-					return fmt.Errorf("t.NewMap: %w", err)
-				}
-			}
-			v := t.NewMap[k]
-			if len(k) > 8192 {
-				return fmt.Errorf("String in field k was too long")
-			}
-			if err := jw.WriteString(string(k)); err != nil {
-				return fmt.Errorf("k: %w", err)
-			}
-			if err := jw.WriteObjectColon(); err != nil {
-				return fmt.Errorf("t.NewMap: %w", err)
-			}
+		for _, k := range keys {
 
-			if err := v.MarshalDagJSON(jw); err != nil {
+			v := t.OldMap[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
 				return fmt.Errorf("v: %w", err)
 			}
-		}
-		if err := jw.WriteObjectClose(); err != nil {
-			return fmt.Errorf("t.NewMap: %w", err)
+
 		}
 	}
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
-		}
+	if t.OldPtr != nil && t.OldPtr.Defined() {
+		cb(*t.OldPtr)
 	}
 
-	// t.NewNum (uint64) (uint64)
-	if len("NewNum") > 8192 {
-		return fmt.Errorf("String in field \"NewNum\" was too long")
+	if err := jsg.ScanLinks(&t.OldStruct, cb); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
 	}
-	if err := jw.WriteString(string("NewNum")); err != nil {
-		return fmt.Errorf("\"NewNum\": %w", err)
+
+	return nil
+}
+
+func (t *SimpleStructV1Lossless) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
 	}
-	if err := jw.WriteObjectColon(); err != nil {
+	if err := jw.WriteObjectOpen(); err != nil {
 		return err
 	}
-
-	if err := jw.WriteUint64(uint64(t.NewNum)); err != nil {
-		return fmt.Errorf("t.NewNum: %w", err)
+	unknown := jsg.NewUnknownFields(t.Unknown)
+	written := 0
+	if err := unknown.WriteBefore(jw, "OldArray", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
 	}
-
-	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
 		}
 	}
 
-	// t.NewPtr (cid.Cid) (struct)
-	if len("NewPtr") > 8192 {
-		return fmt.Errorf("String in field \"NewPtr\" was too long")
+	// t.OldArray ([]testing.SimpleTypeOne) (slice)
+	if len("OldArray") > 8192 {
+		return fmt.Errorf("String in field \"OldArray\" was too long")
 	}
-	if err := jw.WriteString(string("NewPtr")); err != nil {
-		return fmt.Errorf("\"NewPtr\": %w", err)
+	if err := jw.WriteString(string("OldArray")); err != nil {
+		return fmt.Errorf("\"OldArray\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
+	if len(t.OldArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldArray was too long")
+	}
 
-	if t.NewPtr == nil {
-		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.NewPtr: %w", err)
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
+	for i, v := range t.OldArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldArray: %w", err)
+			}
 		}
-	} else {
-		if err := jw.WriteCid(*t.NewPtr); err != nil {
-			return fmt.Errorf("t.NewPtr: %w", err)
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
 		}
 	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
 
 	written++
+	if err := unknown.WriteBefore(jw, "OldBytes", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
 		}
 	}
 
-	// t.NewStr (string) (string)
-	if len("NewStr") > 8192 {
-		return fmt.Errorf("String in field \"NewStr\" was too long")
-	}
-	if err := jw.WriteString(string("NewStr")); err != nil {
-		return fmt.Errorf("\"NewStr\": %w", err)
+	// t.OldBytes ([]uint8) (slice)
+	if len("OldBytes") > 8192 {
+		return fmt.Errorf("String in field \"OldBytes\" was too long")
+	}
+	if err := jw.WriteString(string("OldBytes")); err != nil {
+		return fmt.Errorf("\"OldBytes\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.NewStr) > 8192 {
-		return fmt.Errorf("String in field t.NewStr was too long")
+	if len(t.OldBytes) > 2097152 {
+		return fmt.Errorf("Byte array in field t.OldBytes was too long")
 	}
-	if err := jw.WriteString(string(t.NewStr)); err != nil {
-		return fmt.Errorf("t.NewStr: %w", err)
+
+	if err := jw.WriteBytes(t.OldBytes); err != nil {
+		return fmt.Errorf("t.OldBytes: %w", err)
+	}
+
+	written++
+	if err := unknown.WriteBefore(jw, "OldCidArray", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldCidArray ([]cid.Cid) (slice)
+	if len("OldCidArray") > 8192 {
+		return fmt.Errorf("String in field \"OldCidArray\" was too long")
+	}
+	if err := jw.WriteString(string("OldCidArray")); err != nil {
+		return fmt.Errorf("\"OldCidArray\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.OldCidArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldCidArray was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldCidArray: %w", err)
+	}
+	for i, v := range t.OldCidArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldCidArray: %w", err)
+			}
+		}
+
+		if err := jw.WriteCid(v); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldCidArray: %w", err)
+	}
+
+	written++
+	if err := unknown.WriteBefore(jw, "OldCidPtrArray", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldCidPtrArray ([]*cid.Cid) (slice)
+	if len("OldCidPtrArray") > 8192 {
+		return fmt.Errorf("String in field \"OldCidPtrArray\" was too long")
+	}
+	if err := jw.WriteString(string("OldCidPtrArray")); err != nil {
+		return fmt.Errorf("\"OldCidPtrArray\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.OldCidPtrArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldCidPtrArray was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldCidPtrArray: %w", err)
+	}
+	for i, v := range t.OldCidPtrArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldCidPtrArray: %w", err)
+			}
+		}
+
+		if v == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		} else {
+			if err := jw.WriteCid(*v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldCidPtrArray: %w", err)
 	}
+
 	written++
+	if err := unknown.WriteBefore(jw, "OldMap", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
@@ -1397,9 +1557,11 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 		}
 
 		keys := make([]string, 0, len(t.OldMap))
+
 		for k := range t.OldMap {
-			keys = append(keys, k)
+			keys = append(keys, string(k))
 		}
+
 		sort.Strings(keys)
 		for i, k := range keys {
 			if i > 0 {
@@ -1407,7 +1569,9 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 					return fmt.Errorf("t.OldMap: %w", err)
 				}
 			}
-			v := t.OldMap[k]
+
+			v := t.OldMap[string(k)]
+
 			if len(k) > 8192 {
 				return fmt.Errorf("String in field k was too long")
 			}
@@ -1428,6 +1592,9 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 	}
 
 	written++
+	if err := unknown.WriteBefore(jw, "OldNum", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
@@ -1450,6 +1617,9 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 	}
 
 	written++
+	if err := unknown.WriteBefore(jw, "OldPtr", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
@@ -1478,6 +1648,9 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 	}
 
 	written++
+	if err := unknown.WriteBefore(jw, "OldStr", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
@@ -1501,151 +1674,8589 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 		return fmt.Errorf("t.OldStr: %w", err)
 	}
 	written++
+	if err := unknown.WriteBefore(jw, "OldStruct", &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
+	}
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
 		}
 	}
 
-	// t.NewArray ([]testing.SimpleTypeOne) (slice)
-	if len("NewArray") > 8192 {
-		return fmt.Errorf("String in field \"NewArray\" was too long")
+	// t.OldStruct (testing.SimpleTypeOne) (struct)
+	if len("OldStruct") > 8192 {
+		return fmt.Errorf("String in field \"OldStruct\" was too long")
 	}
-	if err := jw.WriteString(string("NewArray")); err != nil {
-		return fmt.Errorf("\"NewArray\": %w", err)
+	if err := jw.WriteString(string("OldStruct")); err != nil {
+		return fmt.Errorf("\"OldStruct\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.NewArray) > 8192 {
-		return fmt.Errorf("Slice value in field t.NewArray was too long")
-	}
-
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.NewArray: %w", err)
-	}
-	for i, v := range t.NewArray {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.NewArray: %w", err)
-			}
-		}
-		if err := v.MarshalDagJSON(jw); err != nil {
-			return fmt.Errorf("v: %w", err)
-		}
-	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.NewArray: %w", err)
+	if err := t.OldStruct.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
 	}
-
 	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
-		}
-	}
-
-	// t.NewBytes ([]uint8) (slice)
-	if len("NewBytes") > 8192 {
-		return fmt.Errorf("String in field \"NewBytes\" was too long")
+	if err := unknown.WriteRest(jw, &written); err != nil {
+		return fmt.Errorf("t.Unknown: %w", err)
 	}
-	if err := jw.WriteString(string("NewBytes")); err != nil {
-		return fmt.Errorf("\"NewBytes\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
+	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
-	if len(t.NewBytes) > 2097152 {
-		return fmt.Errorf("Byte array in field t.NewBytes was too long")
-	}
-
-	if err := jw.WriteBytes(t.NewBytes); err != nil {
-		return fmt.Errorf("t.NewBytes: %w", err)
-	}
+	return nil
+}
+func (t *SimpleStructV1Lossless) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = SimpleStructV1Lossless{}
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
 		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
 	}
 
-	// t.OldArray ([]testing.SimpleTypeOne) (slice)
-	if len("OldArray") > 8192 {
-		return fmt.Errorf("String in field \"OldArray\" was too long")
-	}
-	if err := jw.WriteString(string("OldArray")); err != nil {
-		return fmt.Errorf("\"OldArray\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-	if len(t.OldArray) > 8192 {
-		return fmt.Errorf("Slice value in field t.OldArray was too long")
-	}
-
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.OldArray: %w", err)
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
 	}
-	for i, v := range t.OldArray {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.OldArray: %w", err)
-			}
-		}
-		if err := v.MarshalDagJSON(jw); err != nil {
-			return fmt.Errorf("v: %w", err)
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
 		}
-	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.OldArray: %w", err)
-	}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
-		}
-	}
+			// t.OldArray ([]testing.SimpleTypeOne) (slice)
+			case "OldArray":
+				{
 
-	// t.OldBytes ([]uint8) (slice)
-	if len("OldBytes") > 8192 {
-		return fmt.Errorf("String in field \"OldBytes\" was too long")
-	}
-	if err := jw.WriteString(string("OldBytes")); err != nil {
-		return fmt.Errorf("\"OldBytes\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-	if len(t.OldBytes) > 2097152 {
-		return fmt.Errorf("Byte array in field t.OldBytes was too long")
-	}
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".OldArray", err)
+					}
 
-	if err := jw.WriteBytes(t.OldBytes); err != nil {
-		return fmt.Errorf("t.OldBytes: %w", err)
-	}
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".OldArray", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".OldArray", err)
+						}
 
-	written++
-	if written > 0 {
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]SimpleTypeOne, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldArray"+jsg.IndexPath(i), err)
+							}
+
+							t.OldArray = append(t.OldArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".OldArray", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.OldBytes ([]uint8) (slice)
+			case "OldBytes":
+
+				{
+					bval, err := jr.ReadBytes(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".OldBytes", errors.New("byte array too large"))
+						}
+						return jr.PathError(".OldBytes", err)
+					}
+					if len(bval) > 0 {
+						t.OldBytes = []uint8(bval)
+					}
+				}
+
+				// t.OldCidArray ([]cid.Cid) (slice)
+			case "OldCidArray":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".OldCidArray", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".OldCidArray", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".OldCidArray", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]cid.Cid, 1)
+							{
+
+								c, err := jr.ReadCid()
+								if err != nil {
+									return jr.PathError(".OldCidArray"+jsg.IndexPath(i), err)
+								}
+								item[0] = c
+
+							}
+							t.OldCidArray = append(t.OldCidArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldCidArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".OldCidArray", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.OldCidPtrArray ([]*cid.Cid) (slice)
+			case "OldCidPtrArray":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".OldCidPtrArray", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".OldCidPtrArray", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".OldCidPtrArray", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]*cid.Cid, 1)
+							{
+
+								c, err := jr.ReadCidOrNull()
+								if err != nil {
+									return jr.PathError(".OldCidPtrArray"+jsg.IndexPath(i), err)
+								}
+								item[0] = c
+
+							}
+							t.OldCidPtrArray = append(t.OldCidPtrArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldCidPtrArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".OldCidPtrArray", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.OldMap (map[string]testing.SimpleTypeOne) (map)
+			case "OldMap":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".OldMap", err)
+					}
+
+					t.OldMap = map[string]SimpleTypeOne{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".OldMap", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".OldMap", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".OldMap", errors.New("string too long"))
+									}
+									return jr.PathError(".OldMap", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".OldMap", err)
+							}
+							var v SimpleTypeOne
+
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldMap"+jsg.KeyPath(string(key)), err)
+							}
+
+							t.OldMap[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldMap", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".OldMap", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.OldNum (uint64) (uint64)
+			case "OldNum":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".OldNum", err)
+					}
+
+					t.OldNum = uint64(nval)
+
+				}
+
+				// t.OldPtr (cid.Cid) (struct)
+			case "OldPtr":
+				{
+
+					c, err := jr.ReadCidOrNull()
+					if err != nil {
+						return jr.PathError(".OldPtr", err)
+					}
+					t.OldPtr = c
+
+				}
+
+				// t.OldStr (string) (string)
+			case "OldStr":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".OldStr", errors.New("string too long"))
+						}
+						return jr.PathError(".OldStr", err)
+					}
+					t.OldStr = string(sval)
+				}
+
+				// t.OldStruct (testing.SimpleTypeOne) (struct)
+			case "OldStruct":
+
+				if err := t.OldStruct.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".OldStruct", err)
+				}
+
+			default:
+
+				// Field doesn't exist on this type, so keep it as is
+
+				var v jsg.Deferred
+				if err := v.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+				if t.Unknown == nil {
+					t.Unknown = make(map[string]jsg.Deferred)
+				}
+				t.Unknown[name] = v
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *SimpleStructV1Lossless) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	for _, v := range t.OldArray {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	for _, v := range t.OldCidArray {
+
+		if v.Defined() {
+			cb(v)
+		}
+
+	}
+	for _, v := range t.OldCidPtrArray {
+
+		if v != nil && v.Defined() {
+			cb(*v)
+		}
+
+	}
+	{
+		keys := make([]string, 0, len(t.OldMap))
+
+		for k := range t.OldMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.OldMap[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+
+	if t.OldPtr != nil && t.OldPtr.Defined() {
+		cb(*t.OldPtr)
+	}
+
+	if err := jsg.ScanLinks(&t.OldStruct, cb); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
+	}
+
+	{
+		keys := make([]string, 0, len(t.Unknown))
+
+		for k := range t.Unknown {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Unknown[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.NewArray ([]testing.SimpleTypeOne) (slice)
+	if len("NewArray") > 8192 {
+		return fmt.Errorf("String in field \"NewArray\" was too long")
+	}
+	if err := jw.WriteString(string("NewArray")); err != nil {
+		return fmt.Errorf("\"NewArray\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.NewArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.NewArray was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.NewArray: %w", err)
+	}
+	for i, v := range t.NewArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.NewArray: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.NewArray: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewBytes ([]uint8) (slice)
+	if len("NewBytes") > 8192 {
+		return fmt.Errorf("String in field \"NewBytes\" was too long")
+	}
+	if err := jw.WriteString(string("NewBytes")); err != nil {
+		return fmt.Errorf("\"NewBytes\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.NewBytes) > 2097152 {
+		return fmt.Errorf("Byte array in field t.NewBytes was too long")
+	}
+
+	if err := jw.WriteBytes(t.NewBytes); err != nil {
+		return fmt.Errorf("t.NewBytes: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewMap (map[string]testing.SimpleTypeOne) (map)
+	if len("NewMap") > 8192 {
+		return fmt.Errorf("String in field \"NewMap\" was too long")
+	}
+	if err := jw.WriteString(string("NewMap")); err != nil {
+		return fmt.Errorf("\"NewMap\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.NewMap) > 4096 {
+			return fmt.Errorf("cannot marshal t.NewMap map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.NewMap: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.NewMap))
+
+		for k := range t.NewMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.NewMap: %w", err)
+				}
+			}
+
+			v := t.NewMap[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.NewMap: %w", err)
+			}
+
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.NewMap: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewNum (uint64) (uint64)
+	if len("NewNum") > 8192 {
+		return fmt.Errorf("String in field \"NewNum\" was too long")
+	}
+	if err := jw.WriteString(string("NewNum")); err != nil {
+		return fmt.Errorf("\"NewNum\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.NewNum)); err != nil {
+		return fmt.Errorf("t.NewNum: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewPtr (cid.Cid) (struct)
+	if len("NewPtr") > 8192 {
+		return fmt.Errorf("String in field \"NewPtr\" was too long")
+	}
+	if err := jw.WriteString(string("NewPtr")); err != nil {
+		return fmt.Errorf("\"NewPtr\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if t.NewPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.NewPtr: %w", err)
+		}
+	} else {
+		if err := jw.WriteCid(*t.NewPtr); err != nil {
+			return fmt.Errorf("t.NewPtr: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewStr (string) (string)
+	if len("NewStr") > 8192 {
+		return fmt.Errorf("String in field \"NewStr\" was too long")
+	}
+	if err := jw.WriteString(string("NewStr")); err != nil {
+		return fmt.Errorf("\"NewStr\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.NewStr) > 8192 {
+		return fmt.Errorf("String in field t.NewStr was too long")
+	}
+	if err := jw.WriteString(string(t.NewStr)); err != nil {
+		return fmt.Errorf("t.NewStr: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NewStruct (testing.SimpleTypeOne) (struct)
+	if len("NewStruct") > 8192 {
+		return fmt.Errorf("String in field \"NewStruct\" was too long")
+	}
+	if err := jw.WriteString(string("NewStruct")); err != nil {
+		return fmt.Errorf("\"NewStruct\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.NewStruct.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.NewStruct: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldArray ([]testing.SimpleTypeOne) (slice)
+	if len("OldArray") > 8192 {
+		return fmt.Errorf("String in field \"OldArray\" was too long")
+	}
+	if err := jw.WriteString(string("OldArray")); err != nil {
+		return fmt.Errorf("\"OldArray\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.OldArray) > 8192 {
+		return fmt.Errorf("Slice value in field t.OldArray was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
+	for i, v := range t.OldArray {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.OldArray: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.OldArray: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldBytes ([]uint8) (slice)
+	if len("OldBytes") > 8192 {
+		return fmt.Errorf("String in field \"OldBytes\" was too long")
+	}
+	if err := jw.WriteString(string("OldBytes")); err != nil {
+		return fmt.Errorf("\"OldBytes\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.OldBytes) > 2097152 {
+		return fmt.Errorf("Byte array in field t.OldBytes was too long")
+	}
+
+	if err := jw.WriteBytes(t.OldBytes); err != nil {
+		return fmt.Errorf("t.OldBytes: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldMap (map[string]testing.SimpleTypeOne) (map)
+	if len("OldMap") > 8192 {
+		return fmt.Errorf("String in field \"OldMap\" was too long")
+	}
+	if err := jw.WriteString(string("OldMap")); err != nil {
+		return fmt.Errorf("\"OldMap\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.OldMap) > 4096 {
+			return fmt.Errorf("cannot marshal t.OldMap map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.OldMap: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.OldMap))
+
+		for k := range t.OldMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.OldMap: %w", err)
+				}
+			}
+
+			v := t.OldMap[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.OldMap: %w", err)
+			}
+
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.OldMap: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldNum (uint64) (uint64)
+	if len("OldNum") > 8192 {
+		return fmt.Errorf("String in field \"OldNum\" was too long")
+	}
+	if err := jw.WriteString(string("OldNum")); err != nil {
+		return fmt.Errorf("\"OldNum\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.OldNum)); err != nil {
+		return fmt.Errorf("t.OldNum: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldPtr (cid.Cid) (struct)
+	if len("OldPtr") > 8192 {
+		return fmt.Errorf("String in field \"OldPtr\" was too long")
+	}
+	if err := jw.WriteString(string("OldPtr")); err != nil {
+		return fmt.Errorf("\"OldPtr\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if t.OldPtr == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.OldPtr: %w", err)
+		}
+	} else {
+		if err := jw.WriteCid(*t.OldPtr); err != nil {
+			return fmt.Errorf("t.OldPtr: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldStr (string) (string)
+	if len("OldStr") > 8192 {
+		return fmt.Errorf("String in field \"OldStr\" was too long")
+	}
+	if err := jw.WriteString(string("OldStr")); err != nil {
+		return fmt.Errorf("\"OldStr\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.OldStr) > 8192 {
+		return fmt.Errorf("String in field t.OldStr was too long")
+	}
+	if err := jw.WriteString(string(t.OldStr)); err != nil {
+		return fmt.Errorf("t.OldStr: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.OldStruct (testing.SimpleTypeOne) (struct)
+	if len("OldStruct") > 8192 {
+		return fmt.Errorf("String in field \"OldStruct\" was too long")
+	}
+	if err := jw.WriteString(string("OldStruct")); err != nil {
+		return fmt.Errorf("\"OldStruct\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.OldStruct.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
+	}
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *SimpleStructV2) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = SimpleStructV2{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.NewArray ([]testing.SimpleTypeOne) (slice)
+			case "NewArray":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".NewArray", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".NewArray", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".NewArray", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]SimpleTypeOne, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".NewArray"+jsg.IndexPath(i), err)
+							}
+
+							t.NewArray = append(t.NewArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".NewArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".NewArray", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.NewBytes ([]uint8) (slice)
+			case "NewBytes":
+
+				{
+					bval, err := jr.ReadBytes(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".NewBytes", errors.New("byte array too large"))
+						}
+						return jr.PathError(".NewBytes", err)
+					}
+					if len(bval) > 0 {
+						t.NewBytes = []uint8(bval)
+					}
+				}
+
+				// t.NewMap (map[string]testing.SimpleTypeOne) (map)
+			case "NewMap":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".NewMap", err)
+					}
+
+					t.NewMap = map[string]SimpleTypeOne{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".NewMap", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".NewMap", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".NewMap", errors.New("string too long"))
+									}
+									return jr.PathError(".NewMap", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".NewMap", err)
+							}
+							var v SimpleTypeOne
+
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".NewMap"+jsg.KeyPath(string(key)), err)
+							}
+
+							t.NewMap[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".NewMap", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".NewMap", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.NewNum (uint64) (uint64)
+			case "NewNum":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".NewNum", err)
+					}
+
+					t.NewNum = uint64(nval)
+
+				}
+
+				// t.NewPtr (cid.Cid) (struct)
+			case "NewPtr":
+				{
+
+					c, err := jr.ReadCidOrNull()
+					if err != nil {
+						return jr.PathError(".NewPtr", err)
+					}
+					t.NewPtr = c
+
+				}
+
+				// t.NewStr (string) (string)
+			case "NewStr":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".NewStr", errors.New("string too long"))
+						}
+						return jr.PathError(".NewStr", err)
+					}
+					t.NewStr = string(sval)
+				}
+
+				// t.NewStruct (testing.SimpleTypeOne) (struct)
+			case "NewStruct":
+
+				if err := t.NewStruct.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".NewStruct", err)
+				}
+
+				// t.OldArray ([]testing.SimpleTypeOne) (slice)
+			case "OldArray":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".OldArray", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".OldArray", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".OldArray", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]SimpleTypeOne, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldArray"+jsg.IndexPath(i), err)
+							}
+
+							t.OldArray = append(t.OldArray, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldArray", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".OldArray", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.OldBytes ([]uint8) (slice)
+			case "OldBytes":
+
+				{
+					bval, err := jr.ReadBytes(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".OldBytes", errors.New("byte array too large"))
+						}
+						return jr.PathError(".OldBytes", err)
+					}
+					if len(bval) > 0 {
+						t.OldBytes = []uint8(bval)
+					}
+				}
+
+				// t.OldMap (map[string]testing.SimpleTypeOne) (map)
+			case "OldMap":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".OldMap", err)
+					}
+
+					t.OldMap = map[string]SimpleTypeOne{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".OldMap", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".OldMap", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".OldMap", errors.New("string too long"))
+									}
+									return jr.PathError(".OldMap", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".OldMap", err)
+							}
+							var v SimpleTypeOne
+
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".OldMap"+jsg.KeyPath(string(key)), err)
+							}
+
+							t.OldMap[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".OldMap", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".OldMap", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.OldNum (uint64) (uint64)
+			case "OldNum":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".OldNum", err)
+					}
+
+					t.OldNum = uint64(nval)
+
+				}
+
+				// t.OldPtr (cid.Cid) (struct)
+			case "OldPtr":
+				{
+
+					c, err := jr.ReadCidOrNull()
+					if err != nil {
+						return jr.PathError(".OldPtr", err)
+					}
+					t.OldPtr = c
+
+				}
+
+				// t.OldStr (string) (string)
+			case "OldStr":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".OldStr", errors.New("string too long"))
+						}
+						return jr.PathError(".OldStr", err)
+					}
+					t.OldStr = string(sval)
+				}
+
+				// t.OldStruct (testing.SimpleTypeOne) (struct)
+			case "OldStruct":
+
+				if err := t.OldStruct.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".OldStruct", err)
+				}
+
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *SimpleStructV2) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	for _, v := range t.NewArray {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	{
+		keys := make([]string, 0, len(t.NewMap))
+
+		for k := range t.NewMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.NewMap[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+
+	if t.NewPtr != nil && t.NewPtr.Defined() {
+		cb(*t.NewPtr)
+	}
+
+	if err := jsg.ScanLinks(&t.NewStruct, cb); err != nil {
+		return fmt.Errorf("t.NewStruct: %w", err)
+	}
+
+	for _, v := range t.OldArray {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	{
+		keys := make([]string, 0, len(t.OldMap))
+
+		for k := range t.OldMap {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.OldMap[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+
+	if t.OldPtr != nil && t.OldPtr.Defined() {
+		cb(*t.OldPtr)
+	}
+
+	if err := jsg.ScanLinks(&t.OldStruct, cb); err != nil {
+		return fmt.Errorf("t.OldStruct: %w", err)
+	}
+
+	return nil
+}
+
+func (t *RenamedFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Bar (string) (string)
+	if len("beep") > 8192 {
+		return fmt.Errorf("String in field \"beep\" was too long")
+	}
+	if err := jw.WriteString(string("beep")); err != nil {
+		return fmt.Errorf("\"beep\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Bar) > 8192 {
+		return fmt.Errorf("String in field t.Bar was too long")
+	}
+	if err := jw.WriteString(string(t.Bar)); err != nil {
+		return fmt.Errorf("t.Bar: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Foo (int64) (int64)
+	if len("foo") > 8192 {
+		return fmt.Errorf("String in field \"foo\" was too long")
+	}
+	if err := jw.WriteString(string("foo")); err != nil {
+		return fmt.Errorf("\"foo\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
+		return fmt.Errorf("t.Foo: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *RenamedFields) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = RenamedFields{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Bar (string) (string)
+			case "beep":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Bar", errors.New("string too long"))
+						}
+						return jr.PathError(".Bar", err)
+					}
+					t.Bar = string(sval)
+				}
+
+				// t.Foo (int64) (int64)
+			case "foo":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Foo", err)
+					}
+
+					t.Foo = int64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *RenamedFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TestEmpty) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Beep (string) (string)
+	if t.Beep != "" {
+		if len("Beep") > 8192 {
+			return fmt.Errorf("String in field \"Beep\" was too long")
+		}
+		if err := jw.WriteString(string("Beep")); err != nil {
+			return fmt.Errorf("\"Beep\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		if len(t.Beep) > 8192 {
+			return fmt.Errorf("String in field t.Beep was too long")
+		}
+		if err := jw.WriteString(string(t.Beep)); err != nil {
+			return fmt.Errorf("t.Beep: %w", err)
+		}
+		written++
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Cat (int64) (int64)
+	if len("Cat") > 8192 {
+		return fmt.Errorf("String in field \"Cat\" was too long")
+	}
+	if err := jw.WriteString(string("Cat")); err != nil {
+		return fmt.Errorf("\"Cat\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Cat)); err != nil {
+		return fmt.Errorf("t.Cat: %w", err)
+	}
+
+	written++
+	if t.Foo != nil {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Foo (string) (string)
+	if t.Foo != nil {
+		if len("Foo") > 8192 {
+			return fmt.Errorf("String in field \"Foo\" was too long")
+		}
+		if err := jw.WriteString(string("Foo")); err != nil {
+			return fmt.Errorf("\"Foo\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		if t.Foo == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("t.Foo: %w", err)
+			}
+		} else {
+			if len(*t.Foo) > 8192 {
+				return fmt.Errorf("String in field t.Foo was too long")
+			}
+			if err := jw.WriteString(string(*t.Foo)); err != nil {
+				return fmt.Errorf("t.Foo: %w", err)
+			}
+		}
+		written++
+	}
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *TestEmpty) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TestEmpty{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Beep (string) (string)
+			case "Beep":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Beep", errors.New("string too long"))
+						}
+						return jr.PathError(".Beep", err)
+					}
+					t.Beep = string(sval)
+				}
+
+				// t.Cat (int64) (int64)
+			case "Cat":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Cat", err)
+					}
+
+					t.Cat = int64(nval)
+
+				}
+
+				// t.Foo (string) (string)
+			case "Foo":
+				{
+					sval, err := jr.ReadStringOrNull(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Foo", errors.New("string too long"))
+						}
+						return jr.PathError(".Foo", err)
+					}
+					if sval != nil {
+						t.Foo = (*string)(sval)
+					}
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *TestEmpty) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TestConstField) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Cats (string) (string)
+	if len("Cats") > 8192 {
+		return fmt.Errorf("String in field \"Cats\" was too long")
+	}
+	if err := jw.WriteString(string("Cats")); err != nil {
+		return fmt.Errorf("\"Cats\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := jw.WriteString("dogsdrool"); err != nil {
+		return err
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Thing (int64) (int64)
+	if len("Thing") > 8192 {
+		return fmt.Errorf("String in field \"Thing\" was too long")
+	}
+	if err := jw.WriteString(string("Thing")); err != nil {
+		return fmt.Errorf("\"Thing\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Thing)); err != nil {
+		return fmt.Errorf("t.Thing: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *TestConstField) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TestConstField{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Cats (string) (string)
+			case "Cats":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Cats", errors.New("string too long"))
+						}
+						return jr.PathError(".Cats", err)
+					}
+					t.Cats = string(sval)
+				}
+
+				// t.Thing (int64) (int64)
+			case "Thing":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Thing", err)
+					}
+
+					t.Thing = int64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *TestConstField) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Drond (int64) (int64)
+	if len("Drond") > 8192 {
+		return fmt.Errorf("String in field \"Drond\" was too long")
+	}
+	if err := jw.WriteString(string("Drond")); err != nil {
+		return fmt.Errorf("\"Drond\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Drond)); err != nil {
+		return fmt.Errorf("t.Drond: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Zp (string) (string)
+	if len("ap") > 8192 {
+		return fmt.Errorf("String in field \"ap\" was too long")
+	}
+	if err := jw.WriteString(string("ap")); err != nil {
+		return fmt.Errorf("\"ap\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Zp) > 8192 {
+		return fmt.Errorf("String in field t.Zp was too long")
+	}
+	if err := jw.WriteString(string(t.Zp)); err != nil {
+		return fmt.Errorf("t.Zp: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Bar (string) (string)
+	if len("beep") > 8192 {
+		return fmt.Errorf("String in field \"beep\" was too long")
+	}
+	if err := jw.WriteString(string("beep")); err != nil {
+		return fmt.Errorf("\"beep\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Bar) > 8192 {
+		return fmt.Errorf("String in field t.Bar was too long")
+	}
+	if err := jw.WriteString(string(t.Bar)); err != nil {
+		return fmt.Errorf("t.Bar: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Foo (int64) (int64)
+	if len("foo") > 8192 {
+		return fmt.Errorf("String in field \"foo\" was too long")
+	}
+	if err := jw.WriteString(string("foo")); err != nil {
+		return fmt.Errorf("\"foo\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
+		return fmt.Errorf("t.Foo: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *TestCanonicalFieldOrder) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TestCanonicalFieldOrder{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Drond (int64) (int64)
+			case "Drond":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Drond", err)
+					}
+
+					t.Drond = int64(nval)
+
+				}
+
+				// t.Zp (string) (string)
+			case "ap":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Zp", errors.New("string too long"))
+						}
+						return jr.PathError(".Zp", err)
+					}
+					t.Zp = string(sval)
+				}
+
+				// t.Bar (string) (string)
+			case "beep":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Bar", errors.New("string too long"))
+						}
+						return jr.PathError(".Bar", err)
+					}
+					t.Bar = string(sval)
+				}
+
+				// t.Foo (int64) (int64)
+			case "foo":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Foo", err)
+					}
+
+					t.Foo = int64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *TestCanonicalFieldOrder) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *MapStringString) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+
+	// t.Snorkleblump (map[string]string) (map)
+	if len("Snorkleblump") > 8192 {
+		return fmt.Errorf("String in field \"Snorkleblump\" was too long")
+	}
+	if err := jw.WriteString(string("Snorkleblump")); err != nil {
+		return fmt.Errorf("\"Snorkleblump\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Snorkleblump) > 4096 {
+			return fmt.Errorf("cannot marshal t.Snorkleblump map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Snorkleblump: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Snorkleblump))
+
+		for k := range t.Snorkleblump {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Snorkleblump: %w", err)
+				}
+			}
+
+			v := t.Snorkleblump[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Snorkleblump: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Snorkleblump: %w", err)
+		}
+	}
+
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *MapStringString) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MapStringString{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Snorkleblump (map[string]string) (map)
+			case "Snorkleblump":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Snorkleblump", err)
+					}
+
+					t.Snorkleblump = map[string]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Snorkleblump", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Snorkleblump", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Snorkleblump", errors.New("string too long"))
+									}
+									return jr.PathError(".Snorkleblump", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Snorkleblump", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Snorkleblump"+jsg.KeyPath(string(key)), errors.New("string too long"))
+									}
+									return jr.PathError(".Snorkleblump"+jsg.KeyPath(string(key)), err)
+								}
+								v = string(sval)
+							}
+							t.Snorkleblump[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Snorkleblump", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Snorkleblump", errors.New("map too large"))
+							}
+						}
+					}
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *MapStringString) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *TestSliceNilPreserve) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Beep (int64) (int64)
+	if len("Beep") > 8192 {
+		return fmt.Errorf("String in field \"Beep\" was too long")
+	}
+	if err := jw.WriteString(string("Beep")); err != nil {
+		return fmt.Errorf("\"Beep\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Beep)); err != nil {
+		return fmt.Errorf("t.Beep: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Cat (string) (string)
+	if len("Cat") > 8192 {
+		return fmt.Errorf("String in field \"Cat\" was too long")
+	}
+	if err := jw.WriteString(string("Cat")); err != nil {
+		return fmt.Errorf("\"Cat\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Cat) > 8192 {
+		return fmt.Errorf("String in field t.Cat was too long")
+	}
+	if err := jw.WriteString(string(t.Cat)); err != nil {
+		return fmt.Errorf("t.Cat: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Not ([]uint64) (slice)
+	if len("Not") > 8192 {
+		return fmt.Errorf("String in field \"Not\" was too long")
+	}
+	if err := jw.WriteString(string("Not")); err != nil {
+		return fmt.Errorf("\"Not\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Not) > 8192 {
+		return fmt.Errorf("Slice value in field t.Not was too long")
+	}
+
+	if t.Not == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.Not: %w", err)
+		}
+	} else {
+
+		if err := jw.WriteArrayOpen(); err != nil {
+			return fmt.Errorf("t.Not: %w", err)
+		}
+		for i, v := range t.Not {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Not: %w", err)
+				}
+			}
+
+			if err := jw.WriteUint64(uint64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteArrayClose(); err != nil {
+			return fmt.Errorf("t.Not: %w", err)
+		}
+
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.NotOther ([]uint8) (slice)
+	if len("NotOther") > 8192 {
+		return fmt.Errorf("String in field \"NotOther\" was too long")
+	}
+	if err := jw.WriteString(string("NotOther")); err != nil {
+		return fmt.Errorf("\"NotOther\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.NotOther) > 2097152 {
+		return fmt.Errorf("Byte array in field t.NotOther was too long")
+	}
+
+	if t.NotOther == nil {
+		if err := jw.WriteNull(); err != nil {
+			return fmt.Errorf("t.NotOther: %w", err)
+		}
+	} else {
+
+		if err := jw.WriteBytes(t.NotOther); err != nil {
+			return fmt.Errorf("t.NotOther: %w", err)
+		}
+
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Other ([]uint8) (slice)
+	if len("Other") > 8192 {
+		return fmt.Errorf("String in field \"Other\" was too long")
+	}
+	if err := jw.WriteString(string("Other")); err != nil {
+		return fmt.Errorf("\"Other\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Other) > 2097152 {
+		return fmt.Errorf("Byte array in field t.Other was too long")
+	}
+
+	if err := jw.WriteBytes(t.Other); err != nil {
+		return fmt.Errorf("t.Other: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Stuff ([]uint64) (slice)
+	if len("Stuff") > 8192 {
+		return fmt.Errorf("String in field \"Stuff\" was too long")
+	}
+	if err := jw.WriteString(string("Stuff")); err != nil {
+		return fmt.Errorf("\"Stuff\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Stuff) > 8192 {
+		return fmt.Errorf("Slice value in field t.Stuff was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Stuff: %w", err)
+	}
+	for i, v := range t.Stuff {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Stuff: %w", err)
+			}
+		}
+
+		if err := jw.WriteUint64(uint64(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Stuff: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *TestSliceNilPreserve) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = TestSliceNilPreserve{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Beep (int64) (int64)
+			case "Beep":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Beep", err)
+					}
+
+					t.Beep = int64(nval)
+
+				}
+
+				// t.Cat (string) (string)
+			case "Cat":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Cat", errors.New("string too long"))
+						}
+						return jr.PathError(".Cat", err)
+					}
+					t.Cat = string(sval)
+				}
+
+				// t.Not ([]uint64) (slice)
+			case "Not":
+				{
+
+					open, err := jr.ReadArrayOpenOrNull()
+					if err != nil {
+						return jr.PathError(".Not", err)
+					}
+					if open {
+
+						close, err := jr.PeekArrayClose()
+						if err != nil {
+							return jr.PathError(".Not", err)
+						}
+						if close {
+							if err := jr.ReadArrayClose(); err != nil {
+								return jr.PathError(".Not", err)
+							}
+
+							t.Not = []uint64{}
+
+						} else {
+							for i := 0; i < 8192; i++ {
+								item := make([]uint64, 1)
+								{
+
+									nval, err := jr.ReadNumberAsUint64()
+									if err != nil {
+										return jr.PathError(".Not"+jsg.IndexPath(i), err)
+									}
+
+									item[0] = uint64(nval)
+
+								}
+								t.Not = append(t.Not, item[0])
+
+								close, err := jr.ReadArrayCloseOrComma()
+								if err != nil {
+									return jr.PathError(".Not", err)
+								}
+								if close {
+									break
+								}
+								if i == 8192-1 {
+									return jr.PathError(".Not", errors.New("slice too large"))
+								}
+							}
+						}
+
+					}
+
+				}
+
+				// t.NotOther ([]uint8) (slice)
+			case "NotOther":
+
+				{
+					bval, err := jr.ReadBytesOrNull(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".NotOther", errors.New("byte array too large"))
+						}
+						return jr.PathError(".NotOther", err)
+					}
+					if bval != nil {
+						t.NotOther = []uint8(*bval)
+					}
+				}
+
+				// t.Other ([]uint8) (slice)
+			case "Other":
+
+				{
+					bval, err := jr.ReadBytes(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Other", errors.New("byte array too large"))
+						}
+						return jr.PathError(".Other", err)
+					}
+					if len(bval) > 0 {
+						t.Other = []uint8(bval)
+					}
+				}
+
+				// t.Stuff ([]uint64) (slice)
+			case "Stuff":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Stuff", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".Stuff", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Stuff", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]uint64, 1)
+							{
+
+								nval, err := jr.ReadNumberAsUint64()
+								if err != nil {
+									return jr.PathError(".Stuff"+jsg.IndexPath(i), err)
+								}
+
+								item[0] = uint64(nval)
+
+							}
+							t.Stuff = append(t.Stuff, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Stuff", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Stuff", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *TestSliceNilPreserve) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *StringPtrSlices) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.StringPtrs ([]*string) (slice)
+	if len("StringPtrs") > 8192 {
+		return fmt.Errorf("String in field \"StringPtrs\" was too long")
+	}
+	if err := jw.WriteString(string("StringPtrs")); err != nil {
+		return fmt.Errorf("\"StringPtrs\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.StringPtrs) > 8192 {
+		return fmt.Errorf("Slice value in field t.StringPtrs was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.StringPtrs: %w", err)
+	}
+	for i, v := range t.StringPtrs {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.StringPtrs: %w", err)
+			}
+		}
+		if v == nil {
+			if err := jw.WriteNull(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		} else {
+			if len(*v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(*v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.StringPtrs: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Strings ([]string) (slice)
+	if len("Strings") > 8192 {
+		return fmt.Errorf("String in field \"Strings\" was too long")
+	}
+	if err := jw.WriteString(string("Strings")); err != nil {
+		return fmt.Errorf("\"Strings\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Strings) > 8192 {
+		return fmt.Errorf("Slice value in field t.Strings was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Strings: %w", err)
+	}
+	for i, v := range t.Strings {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Strings: %w", err)
+			}
+		}
+		if len(v) > 8192 {
+			return fmt.Errorf("String in field v was too long")
+		}
+		if err := jw.WriteString(string(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Strings: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *StringPtrSlices) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = StringPtrSlices{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.StringPtrs ([]*string) (slice)
+			case "StringPtrs":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".StringPtrs", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".StringPtrs", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".StringPtrs", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]*string, 1)
+							{
+								sval, err := jr.ReadStringOrNull(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".StringPtrs"+jsg.IndexPath(i), errors.New("string too long"))
+									}
+									return jr.PathError(".StringPtrs"+jsg.IndexPath(i), err)
+								}
+								if sval != nil {
+									item[0] = (*string)(sval)
+								}
+							}
+							t.StringPtrs = append(t.StringPtrs, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".StringPtrs", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".StringPtrs", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.Strings ([]string) (slice)
+			case "Strings":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Strings", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".Strings", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Strings", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]string, 1)
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Strings"+jsg.IndexPath(i), errors.New("string too long"))
+									}
+									return jr.PathError(".Strings"+jsg.IndexPath(i), err)
+								}
+								item[0] = string(sval)
+							}
+							t.Strings = append(t.Strings, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Strings", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Strings", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *StringPtrSlices) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.LongerNamedField (string) (string)
+	if len("LongerNamedField") > 8192 {
+		return fmt.Errorf("String in field \"LongerNamedField\" was too long")
+	}
+	if err := jw.WriteString(string("LongerNamedField")); err != nil {
+		return fmt.Errorf("\"LongerNamedField\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.LongerNamedField) > 8192 {
+		return fmt.Errorf("String in field t.LongerNamedField was too long")
+	}
+	if err := jw.WriteString(string(t.LongerNamedField)); err != nil {
+		return fmt.Errorf("t.LongerNamedField: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Bar (string) (string)
+	if len("beep") > 8192 {
+		return fmt.Errorf("String in field \"beep\" was too long")
+	}
+	if err := jw.WriteString(string("beep")); err != nil {
+		return fmt.Errorf("\"beep\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Bar) > 8192 {
+		return fmt.Errorf("String in field t.Bar was too long")
+	}
+	if err := jw.WriteString(string(t.Bar)); err != nil {
+		return fmt.Errorf("t.Bar: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Foo (int64) (int64)
+	if len("foo") > 8192 {
+		return fmt.Errorf("String in field \"foo\" was too long")
+	}
+	if err := jw.WriteString(string("foo")); err != nil {
+		return fmt.Errorf("\"foo\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
+		return fmt.Errorf("t.Foo: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *FieldNameOverlap) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = FieldNameOverlap{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.LongerNamedField (string) (string)
+			case "LongerNamedField":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".LongerNamedField", errors.New("string too long"))
+						}
+						return jr.PathError(".LongerNamedField", err)
+					}
+					t.LongerNamedField = string(sval)
+				}
+
+				// t.Bar (string) (string)
+			case "beep":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Bar", errors.New("string too long"))
+						}
+						return jr.PathError(".Bar", err)
+					}
+					t.Bar = string(sval)
+				}
+
+				// t.Foo (int64) (int64)
+			case "foo":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Foo", err)
+					}
+
+					t.Foo = int64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *FieldNameOverlap) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *MapKeys) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Int64s (map[int64]string) (map)
+	if len("Int64s") > 8192 {
+		return fmt.Errorf("String in field \"Int64s\" was too long")
+	}
+	if err := jw.WriteString(string("Int64s")); err != nil {
+		return fmt.Errorf("\"Int64s\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Int64s) > 4096 {
+			return fmt.Errorf("cannot marshal t.Int64s map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Int64s: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Int64s))
+
+		mkeys := make(map[string]int64, len(t.Int64s))
+		for k := range t.Int64s {
+
+			ks := strconv.FormatInt(int64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Int64s: %w", err)
+				}
+			}
+
+			v := t.Int64s[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Int64s: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Int64s: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Int8s (map[int8]string) (map)
+	if len("Int8s") > 8192 {
+		return fmt.Errorf("String in field \"Int8s\" was too long")
+	}
+	if err := jw.WriteString(string("Int8s")); err != nil {
+		return fmt.Errorf("\"Int8s\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Int8s) > 4096 {
+			return fmt.Errorf("cannot marshal t.Int8s map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Int8s: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Int8s))
+
+		mkeys := make(map[string]int8, len(t.Int8s))
+		for k := range t.Int8s {
+
+			ks := strconv.FormatInt(int64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Int8s: %w", err)
+				}
+			}
+
+			v := t.Int8s[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Int8s: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Int8s: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Ints (map[int]string) (map)
+	if len("Ints") > 8192 {
+		return fmt.Errorf("String in field \"Ints\" was too long")
+	}
+	if err := jw.WriteString(string("Ints")); err != nil {
+		return fmt.Errorf("\"Ints\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Ints) > 4096 {
+			return fmt.Errorf("cannot marshal t.Ints map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Ints: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Ints))
+
+		mkeys := make(map[string]int, len(t.Ints))
+		for k := range t.Ints {
+
+			ks := strconv.FormatInt(int64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Ints: %w", err)
+				}
+			}
+
+			v := t.Ints[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Ints: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Ints: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Named (map[testing.NamedNumber]string) (map)
+	if len("Named") > 8192 {
+		return fmt.Errorf("String in field \"Named\" was too long")
+	}
+	if err := jw.WriteString(string("Named")); err != nil {
+		return fmt.Errorf("\"Named\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Named) > 4096 {
+			return fmt.Errorf("cannot marshal t.Named map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Named: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Named))
+
+		mkeys := make(map[string]NamedNumber, len(t.Named))
+		for k := range t.Named {
+
+			ks := strconv.FormatUint(uint64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Named: %w", err)
+				}
+			}
+
+			v := t.Named[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Named: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Named: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Strings (map[testing.NamedString]string) (map)
+	if len("Strings") > 8192 {
+		return fmt.Errorf("String in field \"Strings\" was too long")
+	}
+	if err := jw.WriteString(string("Strings")); err != nil {
+		return fmt.Errorf("\"Strings\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Strings) > 4096 {
+			return fmt.Errorf("cannot marshal t.Strings map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Strings: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Strings))
+
+		for k := range t.Strings {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Strings: %w", err)
+				}
+			}
+
+			v := t.Strings[NamedString(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Strings: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Strings: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Text (map[testing.TextKey]string) (map)
+	if len("Text") > 8192 {
+		return fmt.Errorf("String in field \"Text\" was too long")
+	}
+	if err := jw.WriteString(string("Text")); err != nil {
+		return fmt.Errorf("\"Text\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Text) > 4096 {
+			return fmt.Errorf("cannot marshal t.Text map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Text: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Text))
+
+		mkeys := make(map[string]TextKey, len(t.Text))
+		for k := range t.Text {
+
+			kk := k
+			kb, err := kk.MarshalText()
+			if err != nil {
+				return fmt.Errorf("t.Text: %w", err)
+			}
+			ks := string(kb)
+			if _, ok := mkeys[ks]; ok {
+				return fmt.Errorf("t.Text: duplicate map key %q", ks)
+			}
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Text: %w", err)
+				}
+			}
+
+			v := t.Text[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Text: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Text: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Uint64s (map[uint64]string) (map)
+	if len("Uint64s") > 8192 {
+		return fmt.Errorf("String in field \"Uint64s\" was too long")
+	}
+	if err := jw.WriteString(string("Uint64s")); err != nil {
+		return fmt.Errorf("\"Uint64s\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Uint64s) > 4096 {
+			return fmt.Errorf("cannot marshal t.Uint64s map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Uint64s: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Uint64s))
+
+		mkeys := make(map[string]uint64, len(t.Uint64s))
+		for k := range t.Uint64s {
+
+			ks := strconv.FormatUint(uint64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Uint64s: %w", err)
+				}
+			}
+
+			v := t.Uint64s[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Uint64s: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Uint64s: %w", err)
+		}
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *MapKeys) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MapKeys{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Int64s (map[int64]string) (map)
+			case "Int64s":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Int64s", err)
+					}
+
+					t.Int64s = map[int64]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Int64s", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Int64s", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key int64
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Int64s", errors.New("map key too long"))
+								}
+								return jr.PathError(".Int64s", err)
+							}
+							{
+
+								n, err := strconv.ParseInt(ks, 10, 64)
+
+								if err != nil {
+									return jr.PathError(".Int64s", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatInt(n, 10) != ks {
+
+									return jr.PathError(".Int64s", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = int64(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Int64s", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Int64s"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Int64s"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Int64s[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Int64s", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Int64s", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Int8s (map[int8]string) (map)
+			case "Int8s":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Int8s", err)
+					}
+
+					t.Int8s = map[int8]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Int8s", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Int8s", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key int8
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Int8s", errors.New("map key too long"))
+								}
+								return jr.PathError(".Int8s", err)
+							}
+							{
+
+								n, err := strconv.ParseInt(ks, 10, 8)
+
+								if err != nil {
+									return jr.PathError(".Int8s", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatInt(n, 10) != ks {
+
+									return jr.PathError(".Int8s", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = int8(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Int8s", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Int8s"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Int8s"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Int8s[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Int8s", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Int8s", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Ints (map[int]string) (map)
+			case "Ints":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Ints", err)
+					}
+
+					t.Ints = map[int]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Ints", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Ints", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key int
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Ints", errors.New("map key too long"))
+								}
+								return jr.PathError(".Ints", err)
+							}
+							{
+
+								n, err := strconv.ParseInt(ks, 10, strconv.IntSize)
+
+								if err != nil {
+									return jr.PathError(".Ints", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatInt(n, 10) != ks {
+
+									return jr.PathError(".Ints", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = int(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Ints", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Ints"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Ints"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Ints[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Ints", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Ints", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Named (map[testing.NamedNumber]string) (map)
+			case "Named":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Named", err)
+					}
+
+					t.Named = map[NamedNumber]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Named", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Named", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key NamedNumber
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Named", errors.New("map key too long"))
+								}
+								return jr.PathError(".Named", err)
+							}
+							{
+
+								n, err := strconv.ParseUint(ks, 10, 64)
+
+								if err != nil {
+									return jr.PathError(".Named", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatUint(n, 10) != ks {
+
+									return jr.PathError(".Named", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = NamedNumber(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Named", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Named"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Named"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Named[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Named", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Named", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Strings (map[testing.NamedString]string) (map)
+			case "Strings":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Strings", err)
+					}
+
+					t.Strings = map[NamedString]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Strings", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Strings", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key NamedString
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Strings", errors.New("string too long"))
+									}
+									return jr.PathError(".Strings", err)
+								}
+								key = NamedString(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Strings", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Strings"+jsg.KeyPath(string(key)), errors.New("string too long"))
+									}
+									return jr.PathError(".Strings"+jsg.KeyPath(string(key)), err)
+								}
+								v = string(sval)
+							}
+							t.Strings[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Strings", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Strings", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Text (map[testing.TextKey]string) (map)
+			case "Text":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Text", err)
+					}
+
+					t.Text = map[TextKey]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Text", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Text", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key TextKey
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Text", errors.New("map key too long"))
+								}
+								return jr.PathError(".Text", err)
+							}
+							{
+
+								if err := key.UnmarshalText([]byte(ks)); err != nil {
+									return jr.PathError(".Text", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Text", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Text"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Text"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Text[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Text", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Text", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Uint64s (map[uint64]string) (map)
+			case "Uint64s":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Uint64s", err)
+					}
+
+					t.Uint64s = map[uint64]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Uint64s", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Uint64s", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key uint64
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".Uint64s", errors.New("map key too long"))
+								}
+								return jr.PathError(".Uint64s", err)
+							}
+							{
+
+								n, err := strconv.ParseUint(ks, 10, 64)
+
+								if err != nil {
+									return jr.PathError(".Uint64s", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatUint(n, 10) != ks {
+
+									return jr.PathError(".Uint64s", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = uint64(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Uint64s", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Uint64s"+jsg.KeyPath(ks), errors.New("string too long"))
+									}
+									return jr.PathError(".Uint64s"+jsg.KeyPath(ks), err)
+								}
+								v = string(sval)
+							}
+							t.Uint64s[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Uint64s", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Uint64s", errors.New("map too large"))
+							}
+						}
+					}
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *MapKeys) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *MapValues) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Bools (map[string]bool) (map)
+	if len("Bools") > 8192 {
+		return fmt.Errorf("String in field \"Bools\" was too long")
+	}
+	if err := jw.WriteString(string("Bools")); err != nil {
+		return fmt.Errorf("\"Bools\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Bools) > 4096 {
+			return fmt.Errorf("cannot marshal t.Bools map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Bools: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Bools))
+
+		for k := range t.Bools {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Bools: %w", err)
+				}
+			}
+
+			v := t.Bools[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Bools: %w", err)
+			}
+
+			if err := jw.WriteBool(v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Bools: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Bytes (map[string][]uint8) (map)
+	if len("Bytes") > 8192 {
+		return fmt.Errorf("String in field \"Bytes\" was too long")
+	}
+	if err := jw.WriteString(string("Bytes")); err != nil {
+		return fmt.Errorf("\"Bytes\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Bytes) > 4096 {
+			return fmt.Errorf("cannot marshal t.Bytes map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Bytes: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Bytes))
+
+		for k := range t.Bytes {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Bytes: %w", err)
+				}
+			}
+
+			v := t.Bytes[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Bytes: %w", err)
+			}
+
+			if len(v) > 2097152 {
+				return fmt.Errorf("Byte array in field v was too long")
+			}
+
+			if err := jw.WriteBytes(v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Bytes: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.CidPtrs (map[string]*cid.Cid) (map)
+	if len("CidPtrs") > 8192 {
+		return fmt.Errorf("String in field \"CidPtrs\" was too long")
+	}
+	if err := jw.WriteString(string("CidPtrs")); err != nil {
+		return fmt.Errorf("\"CidPtrs\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.CidPtrs) > 4096 {
+			return fmt.Errorf("cannot marshal t.CidPtrs map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.CidPtrs: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.CidPtrs))
+
+		for k := range t.CidPtrs {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.CidPtrs: %w", err)
+				}
+			}
+
+			v := t.CidPtrs[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.CidPtrs: %w", err)
+			}
+
+			if v == nil {
+				if err := jw.WriteNull(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			} else {
+				if err := jw.WriteCid(*v); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.CidPtrs: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Cids (map[string]cid.Cid) (map)
+	if len("Cids") > 8192 {
+		return fmt.Errorf("String in field \"Cids\" was too long")
+	}
+	if err := jw.WriteString(string("Cids")); err != nil {
+		return fmt.Errorf("\"Cids\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Cids) > 4096 {
+			return fmt.Errorf("cannot marshal t.Cids map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Cids: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Cids))
+
+		for k := range t.Cids {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Cids: %w", err)
+				}
+			}
+
+			v := t.Cids[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Cids: %w", err)
+			}
+
+			if err := jw.WriteCid(v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Cids: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Deep (map[string]map[int64][]string) (map)
+	if len("Deep") > 8192 {
+		return fmt.Errorf("String in field \"Deep\" was too long")
+	}
+	if err := jw.WriteString(string("Deep")); err != nil {
+		return fmt.Errorf("\"Deep\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Deep) > 4096 {
+			return fmt.Errorf("cannot marshal t.Deep map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Deep: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Deep))
+
+		for k := range t.Deep {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Deep: %w", err)
+				}
+			}
+
+			v := t.Deep[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Deep: %w", err)
+			}
+
+			{
+				if len(v) > 4096 {
+					return fmt.Errorf("cannot marshal v map too large")
+				}
+
+				if err := jw.WriteObjectOpen(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+
+				keys := make([]string, 0, len(v))
+
+				mkeys := make(map[string]int64, len(v))
+				for k := range v {
+
+					ks := strconv.FormatInt(int64(k), 10)
+
+					keys = append(keys, ks)
+					mkeys[ks] = k
+				}
+
+				sort.Strings(keys)
+				for i, k := range keys {
+					if i > 0 {
+						if err := jw.WriteComma(); err != nil {
+							return fmt.Errorf("v: %w", err)
+						}
+					}
+
+					v := v[mkeys[k]]
+
+					if len(k) > 8192 {
+						return fmt.Errorf("String in field k was too long")
+					}
+					if err := jw.WriteString(string(k)); err != nil {
+						return fmt.Errorf("k: %w", err)
+					}
+					if err := jw.WriteObjectColon(); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+
+					if len(v) > 8192 {
+						return fmt.Errorf("Slice value in field v was too long")
+					}
+
+					if err := jw.WriteArrayOpen(); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+					for i, v := range v {
+						if i > 0 {
+							if err := jw.WriteComma(); err != nil {
+								return fmt.Errorf("v: %w", err)
+							}
+						}
+						if len(v) > 8192 {
+							return fmt.Errorf("String in field v was too long")
+						}
+						if err := jw.WriteString(string(v)); err != nil {
+							return fmt.Errorf("v: %w", err)
+						}
+					}
+					if err := jw.WriteArrayClose(); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+
+				}
+				if err := jw.WriteObjectClose(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Deep: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.IntCids (map[int64]cid.Cid) (map)
+	if len("IntCids") > 8192 {
+		return fmt.Errorf("String in field \"IntCids\" was too long")
+	}
+	if err := jw.WriteString(string("IntCids")); err != nil {
+		return fmt.Errorf("\"IntCids\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.IntCids) > 4096 {
+			return fmt.Errorf("cannot marshal t.IntCids map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.IntCids: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.IntCids))
+
+		mkeys := make(map[string]int64, len(t.IntCids))
+		for k := range t.IntCids {
+
+			ks := strconv.FormatInt(int64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.IntCids: %w", err)
+				}
+			}
+
+			v := t.IntCids[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.IntCids: %w", err)
+			}
+
+			if err := jw.WriteCid(v); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.IntCids: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.IntPtrs (map[string]*int64) (map)
+	if len("IntPtrs") > 8192 {
+		return fmt.Errorf("String in field \"IntPtrs\" was too long")
+	}
+	if err := jw.WriteString(string("IntPtrs")); err != nil {
+		return fmt.Errorf("\"IntPtrs\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.IntPtrs) > 4096 {
+			return fmt.Errorf("cannot marshal t.IntPtrs map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.IntPtrs: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.IntPtrs))
+
+		for k := range t.IntPtrs {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.IntPtrs: %w", err)
+				}
+			}
+
+			v := t.IntPtrs[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.IntPtrs: %w", err)
+			}
+
+			if v == nil {
+				if err := jw.WriteNull(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			} else {
+				if err := jw.WriteInt64(int64(*v)); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.IntPtrs: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Nested (map[string]map[string]string) (map)
+	if len("Nested") > 8192 {
+		return fmt.Errorf("String in field \"Nested\" was too long")
+	}
+	if err := jw.WriteString(string("Nested")); err != nil {
+		return fmt.Errorf("\"Nested\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Nested) > 4096 {
+			return fmt.Errorf("cannot marshal t.Nested map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Nested: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Nested))
+
+		for k := range t.Nested {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Nested: %w", err)
+				}
+			}
+
+			v := t.Nested[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Nested: %w", err)
+			}
+
+			{
+				if len(v) > 4096 {
+					return fmt.Errorf("cannot marshal v map too large")
+				}
+
+				if err := jw.WriteObjectOpen(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+
+				keys := make([]string, 0, len(v))
+
+				for k := range v {
+					keys = append(keys, string(k))
+				}
+
+				sort.Strings(keys)
+				for i, k := range keys {
+					if i > 0 {
+						if err := jw.WriteComma(); err != nil {
+							return fmt.Errorf("v: %w", err)
+						}
+					}
+
+					v := v[string(k)]
+
+					if len(k) > 8192 {
+						return fmt.Errorf("String in field k was too long")
+					}
+					if err := jw.WriteString(string(k)); err != nil {
+						return fmt.Errorf("k: %w", err)
+					}
+					if err := jw.WriteObjectColon(); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+
+					if len(v) > 8192 {
+						return fmt.Errorf("String in field v was too long")
+					}
+					if err := jw.WriteString(string(v)); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+				}
+				if err := jw.WriteObjectClose(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Nested: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Structs (map[string][]testing.SimpleTypeOne) (map)
+	if len("Structs") > 8192 {
+		return fmt.Errorf("String in field \"Structs\" was too long")
+	}
+	if err := jw.WriteString(string("Structs")); err != nil {
+		return fmt.Errorf("\"Structs\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Structs) > 4096 {
+			return fmt.Errorf("cannot marshal t.Structs map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Structs: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Structs))
+
+		for k := range t.Structs {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Structs: %w", err)
+				}
+			}
+
+			v := t.Structs[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Structs: %w", err)
+			}
+
+			if len(v) > 8192 {
+				return fmt.Errorf("Slice value in field v was too long")
+			}
+
+			if err := jw.WriteArrayOpen(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+			for i, v := range v {
+				if i > 0 {
+					if err := jw.WriteComma(); err != nil {
+						return fmt.Errorf("v: %w", err)
+					}
+				}
+				if err := v.MarshalDagJSON(jw); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+			if err := jw.WriteArrayClose(); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Structs: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.TextCids (map[testing.TextKey]*cid.Cid) (map)
+	if len("TextCids") > 8192 {
+		return fmt.Errorf("String in field \"TextCids\" was too long")
+	}
+	if err := jw.WriteString(string("TextCids")); err != nil {
+		return fmt.Errorf("\"TextCids\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.TextCids) > 4096 {
+			return fmt.Errorf("cannot marshal t.TextCids map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.TextCids: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.TextCids))
+
+		mkeys := make(map[string]TextKey, len(t.TextCids))
+		for k := range t.TextCids {
+
+			kk := k
+			kb, err := kk.MarshalText()
+			if err != nil {
+				return fmt.Errorf("t.TextCids: %w", err)
+			}
+			ks := string(kb)
+			if _, ok := mkeys[ks]; ok {
+				return fmt.Errorf("t.TextCids: duplicate map key %q", ks)
+			}
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.TextCids: %w", err)
+				}
+			}
+
+			v := t.TextCids[mkeys[k]]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.TextCids: %w", err)
+			}
+
+			if v == nil {
+				if err := jw.WriteNull(); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			} else {
+				if err := jw.WriteCid(*v); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.TextCids: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Uints (map[string]uint64) (map)
+	if len("Uints") > 8192 {
+		return fmt.Errorf("String in field \"Uints\" was too long")
+	}
+	if err := jw.WriteString(string("Uints")); err != nil {
+		return fmt.Errorf("\"Uints\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Uints) > 4096 {
+			return fmt.Errorf("cannot marshal t.Uints map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Uints: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Uints))
+
+		for k := range t.Uints {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Uints: %w", err)
+				}
+			}
+
+			v := t.Uints[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Uints: %w", err)
+			}
+
+			if err := jw.WriteUint64(uint64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Uints: %w", err)
+		}
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *MapValues) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MapValues{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Bools (map[string]bool) (map)
+			case "Bools":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Bools", err)
+					}
+
+					t.Bools = map[string]bool{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Bools", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Bools", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Bools", errors.New("string too long"))
+									}
+									return jr.PathError(".Bools", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Bools", err)
+							}
+							var v bool
+							{
+								bval, err := jr.ReadBool()
+								if err != nil {
+									return jr.PathError(".Bools"+jsg.KeyPath(string(key)), err)
+								}
+								v = bool(bval)
+							}
+							t.Bools[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Bools", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Bools", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Bytes (map[string][]uint8) (map)
+			case "Bytes":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Bytes", err)
+					}
+
+					t.Bytes = map[string][]uint8{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Bytes", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Bytes", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Bytes", errors.New("string too long"))
+									}
+									return jr.PathError(".Bytes", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Bytes", err)
+							}
+							var v []uint8
+
+							{
+								bval, err := jr.ReadBytes(2097152)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Bytes"+jsg.KeyPath(string(key)), errors.New("byte array too large"))
+									}
+									return jr.PathError(".Bytes"+jsg.KeyPath(string(key)), err)
+								}
+								if len(bval) > 0 {
+									v = []uint8(bval)
+								}
+							}
+
+							t.Bytes[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Bytes", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Bytes", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.CidPtrs (map[string]*cid.Cid) (map)
+			case "CidPtrs":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".CidPtrs", err)
+					}
+
+					t.CidPtrs = map[string]*cid.Cid{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".CidPtrs", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".CidPtrs", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".CidPtrs", errors.New("string too long"))
+									}
+									return jr.PathError(".CidPtrs", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".CidPtrs", err)
+							}
+							var v *cid.Cid
+							{
+
+								c, err := jr.ReadCidOrNull()
+								if err != nil {
+									return jr.PathError(".CidPtrs"+jsg.KeyPath(string(key)), err)
+								}
+								v = c
+
+							}
+							t.CidPtrs[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".CidPtrs", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".CidPtrs", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Cids (map[string]cid.Cid) (map)
+			case "Cids":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Cids", err)
+					}
+
+					t.Cids = map[string]cid.Cid{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Cids", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Cids", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Cids", errors.New("string too long"))
+									}
+									return jr.PathError(".Cids", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Cids", err)
+							}
+							var v cid.Cid
+							{
+
+								c, err := jr.ReadCid()
+								if err != nil {
+									return jr.PathError(".Cids"+jsg.KeyPath(string(key)), err)
+								}
+								v = c
+
+							}
+							t.Cids[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Cids", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Cids", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Deep (map[string]map[int64][]string) (map)
+			case "Deep":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Deep", err)
+					}
+
+					t.Deep = map[string]map[int64][]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Deep", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Deep", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Deep", errors.New("string too long"))
+									}
+									return jr.PathError(".Deep", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Deep", err)
+							}
+							var v map[int64][]string
+							{
+								if err := jr.ReadObjectOpen(); err != nil {
+									return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+								}
+
+								v = map[int64][]string{}
+
+								close, err := jr.PeekObjectClose()
+								if err != nil {
+									return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+								}
+								if close {
+									if err := jr.ReadObjectClose(); err != nil {
+										return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+									}
+								} else {
+									for i, l := 0, 8192; i < l; i++ {
+										var keyj int64
+										ksj, err := jr.ReadString(8192)
+										if err != nil {
+											if errors.Is(err, jsg.ErrLimitExceeded) {
+												return jr.PathError(".Deep"+jsg.KeyPath(string(key)), errors.New("map key too long"))
+											}
+											return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+										}
+										{
+
+											n, err := strconv.ParseInt(ksj, 10, 64)
+
+											if err != nil {
+												return jr.PathError(".Deep"+jsg.KeyPath(string(key)), fmt.Errorf("map key %q: %w", ksj, err))
+											}
+
+											if strconv.FormatInt(n, 10) != ksj {
+
+												return jr.PathError(".Deep"+jsg.KeyPath(string(key)), fmt.Errorf("non-canonical integer map key %q", ksj))
+											}
+											keyj = int64(n)
+
+										}
+										if err := jr.ReadObjectColon(); err != nil {
+											return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+										}
+										var vj []string
+										{
+
+											if err := jr.ReadArrayOpen(); err != nil {
+												return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj), err)
+											}
+
+											close, err := jr.PeekArrayClose()
+											if err != nil {
+												return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj), err)
+											}
+											if close {
+												if err := jr.ReadArrayClose(); err != nil {
+													return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj), err)
+												}
+
+											} else {
+												for k := 0; k < 8192; k++ {
+													itemk := make([]string, 1)
+													{
+														sval, err := jr.ReadString(8192)
+														if err != nil {
+															if errors.Is(err, jsg.ErrLimitExceeded) {
+																return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj)+jsg.IndexPath(k), errors.New("string too long"))
+															}
+															return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj)+jsg.IndexPath(k), err)
+														}
+														itemk[0] = string(sval)
+													}
+													vj = append(vj, itemk[0])
+
+													close, err := jr.ReadArrayCloseOrComma()
+													if err != nil {
+														return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj), err)
+													}
+													if close {
+														break
+													}
+													if k == 8192-1 {
+														return jr.PathError(".Deep"+jsg.KeyPath(string(key))+jsg.KeyPath(ksj), errors.New("slice too large"))
+													}
+												}
+											}
+
+										}
+										v[keyj] = vj
+
+										close, err := jr.ReadObjectCloseOrComma()
+										if err != nil {
+											return jr.PathError(".Deep"+jsg.KeyPath(string(key)), err)
+										}
+										if close {
+											break
+										}
+										if i == l-1 {
+											return jr.PathError(".Deep"+jsg.KeyPath(string(key)), errors.New("map too large"))
+										}
+									}
+								}
+							}
+							t.Deep[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Deep", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Deep", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.IntCids (map[int64]cid.Cid) (map)
+			case "IntCids":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".IntCids", err)
+					}
+
+					t.IntCids = map[int64]cid.Cid{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".IntCids", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".IntCids", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key int64
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".IntCids", errors.New("map key too long"))
+								}
+								return jr.PathError(".IntCids", err)
+							}
+							{
+
+								n, err := strconv.ParseInt(ks, 10, 64)
+
+								if err != nil {
+									return jr.PathError(".IntCids", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+								if strconv.FormatInt(n, 10) != ks {
+
+									return jr.PathError(".IntCids", fmt.Errorf("non-canonical integer map key %q", ks))
+								}
+								key = int64(n)
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".IntCids", err)
+							}
+							var v cid.Cid
+							{
+
+								c, err := jr.ReadCid()
+								if err != nil {
+									return jr.PathError(".IntCids"+jsg.KeyPath(ks), err)
+								}
+								v = c
+
+							}
+							t.IntCids[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".IntCids", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".IntCids", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.IntPtrs (map[string]*int64) (map)
+			case "IntPtrs":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".IntPtrs", err)
+					}
+
+					t.IntPtrs = map[string]*int64{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".IntPtrs", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".IntPtrs", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".IntPtrs", errors.New("string too long"))
+									}
+									return jr.PathError(".IntPtrs", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".IntPtrs", err)
+							}
+							var v *int64
+							{
+
+								nval, err := jr.ReadNumberAsInt64OrNull()
+								if err != nil {
+									return jr.PathError(".IntPtrs"+jsg.KeyPath(string(key)), err)
+								}
+								if nval != nil {
+
+									typed := int64(*nval)
+									v = &typed
+								}
+
+							}
+							t.IntPtrs[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".IntPtrs", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".IntPtrs", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Nested (map[string]map[string]string) (map)
+			case "Nested":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Nested", err)
+					}
+
+					t.Nested = map[string]map[string]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Nested", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Nested", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Nested", errors.New("string too long"))
+									}
+									return jr.PathError(".Nested", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Nested", err)
+							}
+							var v map[string]string
+							{
+								if err := jr.ReadObjectOpen(); err != nil {
+									return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+								}
+
+								v = map[string]string{}
+
+								close, err := jr.PeekObjectClose()
+								if err != nil {
+									return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+								}
+								if close {
+									if err := jr.ReadObjectClose(); err != nil {
+										return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+									}
+								} else {
+									for i, l := 0, 8192; i < l; i++ {
+										var keyj string
+										{
+											sval, err := jr.ReadString(8192)
+											if err != nil {
+												if errors.Is(err, jsg.ErrLimitExceeded) {
+													return jr.PathError(".Nested"+jsg.KeyPath(string(key)), errors.New("string too long"))
+												}
+												return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+											}
+											keyj = string(sval)
+										}
+										if err := jr.ReadObjectColon(); err != nil {
+											return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+										}
+										var vj string
+										{
+											sval, err := jr.ReadString(8192)
+											if err != nil {
+												if errors.Is(err, jsg.ErrLimitExceeded) {
+													return jr.PathError(".Nested"+jsg.KeyPath(string(key))+jsg.KeyPath(string(keyj)), errors.New("string too long"))
+												}
+												return jr.PathError(".Nested"+jsg.KeyPath(string(key))+jsg.KeyPath(string(keyj)), err)
+											}
+											vj = string(sval)
+										}
+										v[keyj] = vj
+
+										close, err := jr.ReadObjectCloseOrComma()
+										if err != nil {
+											return jr.PathError(".Nested"+jsg.KeyPath(string(key)), err)
+										}
+										if close {
+											break
+										}
+										if i == l-1 {
+											return jr.PathError(".Nested"+jsg.KeyPath(string(key)), errors.New("map too large"))
+										}
+									}
+								}
+							}
+							t.Nested[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Nested", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Nested", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Structs (map[string][]testing.SimpleTypeOne) (map)
+			case "Structs":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Structs", err)
+					}
+
+					t.Structs = map[string][]SimpleTypeOne{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Structs", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Structs", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Structs", errors.New("string too long"))
+									}
+									return jr.PathError(".Structs", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Structs", err)
+							}
+							var v []SimpleTypeOne
+							{
+
+								if err := jr.ReadArrayOpen(); err != nil {
+									return jr.PathError(".Structs"+jsg.KeyPath(string(key)), err)
+								}
+
+								close, err := jr.PeekArrayClose()
+								if err != nil {
+									return jr.PathError(".Structs"+jsg.KeyPath(string(key)), err)
+								}
+								if close {
+									if err := jr.ReadArrayClose(); err != nil {
+										return jr.PathError(".Structs"+jsg.KeyPath(string(key)), err)
+									}
+
+								} else {
+									for j := 0; j < 8192; j++ {
+										itemj := make([]SimpleTypeOne, 1)
+
+										if err := itemj[0].UnmarshalDagJSON(jr); err != nil {
+											return jr.PathError(".Structs"+jsg.KeyPath(string(key))+jsg.IndexPath(j), err)
+										}
+
+										v = append(v, itemj[0])
+
+										close, err := jr.ReadArrayCloseOrComma()
+										if err != nil {
+											return jr.PathError(".Structs"+jsg.KeyPath(string(key)), err)
+										}
+										if close {
+											break
+										}
+										if j == 8192-1 {
+											return jr.PathError(".Structs"+jsg.KeyPath(string(key)), errors.New("slice too large"))
+										}
+									}
+								}
+
+							}
+							t.Structs[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Structs", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Structs", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.TextCids (map[testing.TextKey]*cid.Cid) (map)
+			case "TextCids":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".TextCids", err)
+					}
+
+					t.TextCids = map[TextKey]*cid.Cid{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".TextCids", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".TextCids", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key TextKey
+							ks, err := jr.ReadString(8192)
+							if err != nil {
+								if errors.Is(err, jsg.ErrLimitExceeded) {
+									return jr.PathError(".TextCids", errors.New("map key too long"))
+								}
+								return jr.PathError(".TextCids", err)
+							}
+							{
+
+								if err := key.UnmarshalText([]byte(ks)); err != nil {
+									return jr.PathError(".TextCids", fmt.Errorf("map key %q: %w", ks, err))
+								}
+
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".TextCids", err)
+							}
+							var v *cid.Cid
+							{
+
+								c, err := jr.ReadCidOrNull()
+								if err != nil {
+									return jr.PathError(".TextCids"+jsg.KeyPath(ks), err)
+								}
+								v = c
+
+							}
+							t.TextCids[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".TextCids", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".TextCids", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Uints (map[string]uint64) (map)
+			case "Uints":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Uints", err)
+					}
+
+					t.Uints = map[string]uint64{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Uints", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Uints", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Uints", errors.New("string too long"))
+									}
+									return jr.PathError(".Uints", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Uints", err)
+							}
+							var v uint64
+							{
+
+								nval, err := jr.ReadNumberAsUint64()
+								if err != nil {
+									return jr.PathError(".Uints"+jsg.KeyPath(string(key)), err)
+								}
+
+								v = uint64(nval)
+
+							}
+							t.Uints[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Uints", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Uints", errors.New("map too large"))
+							}
+						}
+					}
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *MapValues) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	{
+		keys := make([]string, 0, len(t.CidPtrs))
+
+		for k := range t.CidPtrs {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.CidPtrs[string(k)]
+
+			if v != nil && v.Defined() {
+				cb(*v)
+			}
+
+		}
+	}
+	{
+		keys := make([]string, 0, len(t.Cids))
+
+		for k := range t.Cids {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Cids[string(k)]
+
+			if v.Defined() {
+				cb(v)
+			}
+
+		}
+	}
+	{
+		keys := make([]string, 0, len(t.IntCids))
+
+		mkeys := make(map[string]int64, len(t.IntCids))
+		for k := range t.IntCids {
+
+			ks := strconv.FormatInt(int64(k), 10)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.IntCids[mkeys[k]]
+
+			if v.Defined() {
+				cb(v)
+			}
+
+		}
+	}
+	{
+		keys := make([]string, 0, len(t.Structs))
+
+		for k := range t.Structs {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Structs[string(k)]
+
+			for _, vj := range v {
+
+				if err := jsg.ScanLinks(&vj, cb); err != nil {
+					return fmt.Errorf("vj: %w", err)
+				}
+
+			}
+		}
+	}
+	{
+		keys := make([]string, 0, len(t.TextCids))
+
+		mkeys := make(map[string]TextKey, len(t.TextCids))
+		for k := range t.TextCids {
+
+			kk := k
+			kb, err := kk.MarshalText()
+			if err != nil {
+				return fmt.Errorf("t.TextCids: %w", err)
+			}
+			ks := string(kb)
+
+			keys = append(keys, ks)
+			mkeys[ks] = k
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.TextCids[mkeys[k]]
+
+			if v != nil && v.Defined() {
+				cb(*v)
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *Circle) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+
+	// t.Radius (uint64) (uint64)
+	if len("Radius") > 8192 {
+		return fmt.Errorf("String in field \"Radius\" was too long")
+	}
+	if err := jw.WriteString(string("Radius")); err != nil {
+		return fmt.Errorf("\"Radius\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.Radius)); err != nil {
+		return fmt.Errorf("t.Radius: %w", err)
+	}
+
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *Circle) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = Circle{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Radius (uint64) (uint64)
+			case "Radius":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".Radius", err)
+					}
+
+					t.Radius = uint64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *Circle) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *Square) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Color (string) (string)
+	if len("Color") > 8192 {
+		return fmt.Errorf("String in field \"Color\" was too long")
+	}
+	if err := jw.WriteString(string("Color")); err != nil {
+		return fmt.Errorf("\"Color\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Color) > 8192 {
+		return fmt.Errorf("String in field t.Color was too long")
+	}
+	if err := jw.WriteString(string(t.Color)); err != nil {
+		return fmt.Errorf("t.Color: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Side (uint64) (uint64)
+	if len("Side") > 8192 {
+		return fmt.Errorf("String in field \"Side\" was too long")
+	}
+	if err := jw.WriteString(string("Side")); err != nil {
+		return fmt.Errorf("\"Side\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.Side)); err != nil {
+		return fmt.Errorf("t.Side: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *Square) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = Square{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Color (string) (string)
+			case "Color":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Color", errors.New("string too long"))
+						}
+						return jr.PathError(".Color", err)
+					}
+					t.Color = string(sval)
+				}
+
+				// t.Side (uint64) (uint64)
+			case "Side":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".Side", err)
+					}
+
+					t.Side = uint64(nval)
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *Square) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *KeyedUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.Circle != nil {
+		members++
+	}
+
+	if t.Square != nil {
+		members++
+	}
+
+	if t.Label != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("KeyedUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.Circle (testing.Circle) (struct)
+	case t.Circle != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteString("circle"); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := (*t.Circle).MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("(*t.Circle): %w", err)
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+
+		// t.Square (testing.Square) (struct)
+	case t.Square != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteString("square"); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := (*t.Square).MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("(*t.Square): %w", err)
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+
+		// t.Label (string) (string)
+	case t.Label != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteString("label"); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+		if len((*t.Label)) > 8192 {
+			return fmt.Errorf("String in field (*t.Label) was too long")
+		}
+		if err := jw.WriteString(string((*t.Label))); err != nil {
+			return fmt.Errorf("(*t.Label): %w", err)
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("KeyedUnion: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *KeyedUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = KeyedUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	discriminant, err := jr.ReadString(8192)
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if err := jr.ReadObjectColon(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	switch discriminant {
+
+	// t.Circle (testing.Circle) (struct)
+
+	case "circle":
+
+		t.Circle = new(Circle)
+
+		if err := (*t.Circle).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Circle", err)
+		}
+
+		// t.Square (testing.Square) (struct)
+
+	case "square":
+
+		t.Square = new(Square)
+
+		if err := (*t.Square).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Square", err)
+		}
+
+		// t.Label (string) (string)
+
+	case "label":
+
+		t.Label = new(string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Label", errors.New("string too long"))
+				}
+				return jr.PathError(".Label", err)
+			}
+			(*t.Label) = string(sval)
+		}
+	default:
+
+		return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+
+	}
+
+	if err := jr.ReadObjectClose(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	return nil
+}
+
+func (t *KeyedUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Circle != nil {
+		if err := jsg.ScanLinks(t.Circle, cb); err != nil {
+			return fmt.Errorf("t.Circle: %w", err)
+		}
+	}
+
+	if t.Square != nil {
+		if err := jsg.ScanLinks(t.Square, cb); err != nil {
+			return fmt.Errorf("t.Square: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *EnvelopeUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.Circle != nil {
+		members++
+	}
+
+	if t.Square != nil {
+		members++
+	}
+
+	if t.Count != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("EnvelopeUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.Circle (testing.Circle) (struct)
+	case t.Circle != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteString("content"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := (*t.Circle).MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("(*t.Circle): %w", err)
+		}
+
+		if err := jw.WriteComma(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("type"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("circle"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		// t.Square (testing.Square) (struct)
+	case t.Square != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteString("content"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := (*t.Square).MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("(*t.Square): %w", err)
+		}
+
+		if err := jw.WriteComma(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("type"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("square"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		// t.Count (uint64) (uint64)
+	case t.Count != nil:
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteString("content"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteUint64(uint64((*t.Count))); err != nil {
+			return fmt.Errorf("(*t.Count): %w", err)
+		}
+
+		if err := jw.WriteComma(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("type"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+		if err := jw.WriteString("count"); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("EnvelopeUnion: %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *EnvelopeUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = EnvelopeUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	discriminant, mr, err := jsg.ReadEnvelopeUnion(jr, "type", "content")
+
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	// The member is read from its own reader, which has the
+	// caller's options.
+	defer mr.Release(nil)
+	jr = mr
+
+	switch discriminant {
+
+	// t.Circle (testing.Circle) (struct)
+
+	case "circle":
+
+		t.Circle = new(Circle)
+
+		if err := (*t.Circle).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Circle", err)
+		}
+
+		// t.Square (testing.Square) (struct)
+
+	case "square":
+
+		t.Square = new(Square)
+
+		if err := (*t.Square).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Square", err)
+		}
+
+		// t.Count (uint64) (uint64)
+
+	case "count":
+
+		t.Count = new(uint64)
+
+		{
+
+			nval, err := jr.ReadNumberAsUint64()
+			if err != nil {
+				return jr.PathError(".Count", err)
+			}
+
+			(*t.Count) = uint64(nval)
+
+		}
+	default:
+
+		return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+
+	}
+
+	return nil
+}
+
+func (t *EnvelopeUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Circle != nil {
+		if err := jsg.ScanLinks(t.Circle, cb); err != nil {
+			return fmt.Errorf("t.Circle: %w", err)
+		}
+	}
+
+	if t.Square != nil {
+		if err := jsg.ScanLinks(t.Square, cb); err != nil {
+			return fmt.Errorf("t.Square: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *InlineUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.Circle != nil {
+		members++
+	}
+
+	if t.Square != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("InlineUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.Circle (testing.Circle) (struct)
+	case t.Circle != nil:
+		{
+			var buf bytes.Buffer
+			if err := t.Circle.MarshalDagJSON(&buf); err != nil {
+				return fmt.Errorf("t.Circle: %w", err)
+			}
+			if err := jsg.WriteInlineUnion(jw, "shape", "circle", buf.Bytes()); err != nil {
+				return fmt.Errorf("InlineUnion: %w", err)
+			}
+		}
+
+		// t.Square (testing.Square) (struct)
+	case t.Square != nil:
+		{
+			var buf bytes.Buffer
+			if err := t.Square.MarshalDagJSON(&buf); err != nil {
+				return fmt.Errorf("t.Square: %w", err)
+			}
+			if err := jsg.WriteInlineUnion(jw, "shape", "square", buf.Bytes()); err != nil {
+				return fmt.Errorf("InlineUnion: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *InlineUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = InlineUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	discriminant, mr, err := jsg.ReadInlineUnion(jr, "shape")
+
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	// The member is read from its own reader, which has the
+	// caller's options.
+	defer mr.Release(nil)
+	jr = mr
+
+	switch discriminant {
+
+	// t.Circle (testing.Circle) (struct)
+
+	case "circle":
+
+		t.Circle = new(Circle)
+
+		if err := (*t.Circle).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Circle", err)
+		}
+
+		// t.Square (testing.Square) (struct)
+
+	case "square":
+
+		t.Square = new(Square)
+
+		if err := (*t.Square).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Square", err)
+		}
+
+	default:
+
+		return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+
+	}
+
+	return nil
+}
+
+func (t *InlineUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Circle != nil {
+		if err := jsg.ScanLinks(t.Circle, cb); err != nil {
+			return fmt.Errorf("t.Circle: %w", err)
+		}
+	}
+
+	if t.Square != nil {
+		if err := jsg.ScanLinks(t.Square, cb); err != nil {
+			return fmt.Errorf("t.Square: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *KindedUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.String != nil {
+		members++
+	}
+
+	if t.Int != nil {
+		members++
+	}
+
+	if t.Float != nil {
+		members++
+	}
+
+	if t.Bool != nil {
+		members++
+	}
+
+	if t.Bytes != nil {
+		members++
+	}
+
+	if t.Link != nil {
+		members++
+	}
+
+	if t.List != nil {
+		members++
+	}
+
+	if t.Map != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("KindedUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.String (string) (string)
+	case t.String != nil:
+		if len((*t.String)) > 8192 {
+			return fmt.Errorf("String in field (*t.String) was too long")
+		}
+		if err := jw.WriteString(string((*t.String))); err != nil {
+			return fmt.Errorf("(*t.String): %w", err)
+		}
+
+		// t.Int (int64) (int64)
+	case t.Int != nil:
+
+		if err := jw.WriteInt64(int64((*t.Int))); err != nil {
+			return fmt.Errorf("(*t.Int): %w", err)
+		}
+
+		// t.Float (float64) (float64)
+	case t.Float != nil:
+
+		if err := jw.WriteFloat64(float64((*t.Float))); err != nil {
+			return fmt.Errorf("(*t.Float): %w", err)
+		}
+
+		// t.Bool (bool) (bool)
+	case t.Bool != nil:
+		if err := jw.WriteBool((*t.Bool)); err != nil {
+			return fmt.Errorf("(*t.Bool): %w", err)
+		}
+
+		// t.Bytes ([]uint8) (slice)
+	case t.Bytes != nil:
+		if len((*t.Bytes)) > 2097152 {
+			return fmt.Errorf("Byte array in field (*t.Bytes) was too long")
+		}
+
+		if err := jw.WriteBytes((*t.Bytes)); err != nil {
+			return fmt.Errorf("(*t.Bytes): %w", err)
+		}
+
+		// t.Link (cid.Cid) (struct)
+	case t.Link != nil:
+
+		if err := jw.WriteCid((*t.Link)); err != nil {
+			return fmt.Errorf("(*t.Link): %w", err)
+		}
+
+		// t.List ([]string) (slice)
+	case t.List != nil:
+		if len((*t.List)) > 8192 {
+			return fmt.Errorf("Slice value in field (*t.List) was too long")
+		}
+
+		if err := jw.WriteArrayOpen(); err != nil {
+			return fmt.Errorf("(*t.List): %w", err)
+		}
+		for i, v := range *t.List {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("(*t.List): %w", err)
+				}
+			}
+			if len(v) > 8192 {
+				return fmt.Errorf("String in field v was too long")
+			}
+			if err := jw.WriteString(string(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteArrayClose(); err != nil {
+			return fmt.Errorf("(*t.List): %w", err)
+		}
+
+		// t.Map (testing.Circle) (struct)
+	case t.Map != nil:
+		if err := (*t.Map).MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("(*t.Map): %w", err)
+		}
+	}
+	return nil
+}
+
+func (t *KindedUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = KindedUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	kind, mr, err := jsg.ReadKinded(jr)
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	defer mr.Release(nil)
+	jr = mr
+
+	switch kind {
+
+	// t.String (string) (string)
+
+	case jsg.KindString:
+
+		t.String = new(string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".String", errors.New("string too long"))
+				}
+				return jr.PathError(".String", err)
+			}
+			(*t.String) = string(sval)
+		}
+
+		// t.Int (int64) (int64)
+
+	case jsg.KindInt:
+
+		t.Int = new(int64)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Int", err)
+			}
+
+			(*t.Int) = int64(nval)
+
+		}
+
+		// t.Float (float64) (float64)
+
+	case jsg.KindFloat:
+
+		t.Float = new(float64)
+
+		{
+
+			nval, err := jr.ReadNumberAsFloat64()
+			if err != nil {
+				return jr.PathError(".Float", err)
+			}
+			(*t.Float) = float64(nval)
+
+		}
+
+		// t.Bool (bool) (bool)
+
+	case jsg.KindBool:
+
+		t.Bool = new(bool)
+
+		{
+			bval, err := jr.ReadBool()
+			if err != nil {
+				return jr.PathError(".Bool", err)
+			}
+			(*t.Bool) = bool(bval)
+		}
+
+		// t.Bytes ([]uint8) (slice)
+
+	case jsg.KindBytes:
+
+		t.Bytes = new([]uint8)
+
+		{
+			bval, err := jr.ReadBytes(2097152)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Bytes", errors.New("byte array too large"))
+				}
+				return jr.PathError(".Bytes", err)
+			}
+			if len(bval) > 0 {
+				(*t.Bytes) = []uint8(bval)
+			}
+		}
+
+		// t.Link (cid.Cid) (struct)
+
+	case jsg.KindLink:
+
+		t.Link = new(cid.Cid)
+
+		{
+
+			c, err := jr.ReadCid()
+			if err != nil {
+				return jr.PathError(".Link", err)
+			}
+			(*t.Link) = c
+
+		}
+
+		// t.List ([]string) (slice)
+
+	case jsg.KindList:
+
+		t.List = new([]string)
+
+		{
+
+			if err := jr.ReadArrayOpen(); err != nil {
+				return jr.PathError(".List", err)
+			}
+
+			close, err := jr.PeekArrayClose()
+			if err != nil {
+				return jr.PathError(".List", err)
+			}
+			if close {
+				if err := jr.ReadArrayClose(); err != nil {
+					return jr.PathError(".List", err)
+				}
+
+			} else {
+				for i := 0; i < 8192; i++ {
+					item := make([]string, 1)
+					{
+						sval, err := jr.ReadString(8192)
+						if err != nil {
+							if errors.Is(err, jsg.ErrLimitExceeded) {
+								return jr.PathError(".List"+jsg.IndexPath(i), errors.New("string too long"))
+							}
+							return jr.PathError(".List"+jsg.IndexPath(i), err)
+						}
+						item[0] = string(sval)
+					}
+					(*t.List) = append((*t.List), item[0])
+
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".List", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".List", errors.New("slice too large"))
+					}
+				}
+			}
+
+		}
+
+		// t.Map (testing.Circle) (struct)
+
+	case jsg.KindMap:
+
+		t.Map = new(Circle)
+
+		if err := (*t.Map).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Map", err)
+		}
+
+	default:
+
+		return jr.PathError("", fmt.Errorf("no union member for kind %s", kind))
+
+	}
+
+	return nil
+}
+
+func (t *KindedUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Link != nil && t.Link.Defined() {
+		cb(*t.Link)
+	}
+
+	if t.Map != nil {
+		if err := jsg.ScanLinks(t.Map, cb); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *UnionContainer) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Envelope (testing.EnvelopeUnion) (struct)
+	if len("Envelope") > 8192 {
+		return fmt.Errorf("String in field \"Envelope\" was too long")
+	}
+	if err := jw.WriteString(string("Envelope")); err != nil {
+		return fmt.Errorf("\"Envelope\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Envelope.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Envelope: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Inline ([]testing.InlineUnion) (slice)
+	if len("Inline") > 8192 {
+		return fmt.Errorf("String in field \"Inline\" was too long")
+	}
+	if err := jw.WriteString(string("Inline")); err != nil {
+		return fmt.Errorf("\"Inline\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Inline) > 8192 {
+		return fmt.Errorf("Slice value in field t.Inline was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Inline: %w", err)
+	}
+	for i, v := range t.Inline {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Inline: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Inline: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Keyed (testing.KeyedUnion) (struct)
+	if len("Keyed") > 8192 {
+		return fmt.Errorf("String in field \"Keyed\" was too long")
+	}
+	if err := jw.WriteString(string("Keyed")); err != nil {
+		return fmt.Errorf("\"Keyed\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Keyed.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Keyed: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Kinded (map[string]testing.KindedUnion) (map)
+	if len("Kinded") > 8192 {
+		return fmt.Errorf("String in field \"Kinded\" was too long")
+	}
+	if err := jw.WriteString(string("Kinded")); err != nil {
+		return fmt.Errorf("\"Kinded\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Kinded) > 4096 {
+			return fmt.Errorf("cannot marshal t.Kinded map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Kinded: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Kinded))
+
+		for k := range t.Kinded {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Kinded: %w", err)
+				}
+			}
+
+			v := t.Kinded[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Kinded: %w", err)
+			}
+
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Kinded: %w", err)
+		}
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *UnionContainer) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = UnionContainer{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Envelope (testing.EnvelopeUnion) (struct)
+			case "Envelope":
+
+				{
+					null, err := jr.PeekNull()
+					if err != nil {
+						return jr.PathError(".Envelope", err)
+					}
+					if null {
+						if err := jr.ReadNull(); err != nil {
+							return jr.PathError(".Envelope", err)
+						}
+					} else {
+						t.Envelope = new(EnvelopeUnion)
+						if err := t.Envelope.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(".Envelope", err)
+						}
+					}
+				}
+
+				// t.Inline ([]testing.InlineUnion) (slice)
+			case "Inline":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Inline", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".Inline", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Inline", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]InlineUnion, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Inline"+jsg.IndexPath(i), err)
+							}
+
+							t.Inline = append(t.Inline, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Inline", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Inline", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.Keyed (testing.KeyedUnion) (struct)
+			case "Keyed":
+
+				if err := t.Keyed.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Keyed", err)
+				}
+
+				// t.Kinded (map[string]testing.KindedUnion) (map)
+			case "Kinded":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Kinded", err)
+					}
+
+					t.Kinded = map[string]KindedUnion{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Kinded", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Kinded", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Kinded", errors.New("string too long"))
+									}
+									return jr.PathError(".Kinded", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Kinded", err)
+							}
+							var v KindedUnion
+
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Kinded"+jsg.KeyPath(string(key)), err)
+							}
+
+							t.Kinded[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Kinded", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Kinded", errors.New("map too large"))
+							}
+						}
+					}
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *UnionContainer) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Envelope != nil {
+		if err := jsg.ScanLinks(t.Envelope, cb); err != nil {
+			return fmt.Errorf("t.Envelope: %w", err)
+		}
+	}
+
+	for _, v := range t.Inline {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+
+	if err := jsg.ScanLinks(&t.Keyed, cb); err != nil {
+		return fmt.Errorf("t.Keyed: %w", err)
+	}
+
+	{
+		keys := make([]string, 0, len(t.Kinded))
+
+		for k := range t.Kinded {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Kinded[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *NodeContainer) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Any (typegen.Node) (struct)
+	if len("Any") > 8192 {
+		return fmt.Errorf("String in field \"Any\" was too long")
+	}
+	if err := jw.WriteString(string("Any")); err != nil {
+		return fmt.Errorf("\"Any\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Any.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Any: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.List ([]typegen.Node) (slice)
+	if len("List") > 8192 {
+		return fmt.Errorf("String in field \"List\" was too long")
+	}
+	if err := jw.WriteString(string("List")); err != nil {
+		return fmt.Errorf("\"List\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.List) > 8192 {
+		return fmt.Errorf("Slice value in field t.List was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.List: %w", err)
+	}
+	for i, v := range t.List {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.List: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.List: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Map (map[string]typegen.Node) (map)
+	if len("Map") > 8192 {
+		return fmt.Errorf("String in field \"Map\" was too long")
+	}
+	if err := jw.WriteString(string("Map")); err != nil {
+		return fmt.Errorf("\"Map\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	{
+		if len(t.Map) > 4096 {
+			return fmt.Errorf("cannot marshal t.Map map too large")
+		}
+
+		if err := jw.WriteObjectOpen(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+
+		keys := make([]string, 0, len(t.Map))
+
+		for k := range t.Map {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for i, k := range keys {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Map: %w", err)
+				}
+			}
+
+			v := t.Map[string(k)]
+
+			if len(k) > 8192 {
+				return fmt.Errorf("String in field k was too long")
+			}
+			if err := jw.WriteString(string(k)); err != nil {
+				return fmt.Errorf("k: %w", err)
+			}
+			if err := jw.WriteObjectColon(); err != nil {
+				return fmt.Errorf("t.Map: %w", err)
+			}
+
+			if err := v.MarshalDagJSON(jw); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+		}
+		if err := jw.WriteObjectClose(); err != nil {
+			return fmt.Errorf("t.Map: %w", err)
+		}
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Ptr (typegen.Node) (struct)
+	if len("Ptr") > 8192 {
+		return fmt.Errorf("String in field \"Ptr\" was too long")
+	}
+	if err := jw.WriteString(string("Ptr")); err != nil {
+		return fmt.Errorf("\"Ptr\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Ptr.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Ptr: %w", err)
+	}
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *NodeContainer) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = NodeContainer{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Any (typegen.Node) (struct)
+			case "Any":
+
+				if err := t.Any.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Any", err)
+				}
+
+				// t.List ([]typegen.Node) (slice)
+			case "List":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".List", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".List", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".List", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]jsg.Node, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".List"+jsg.IndexPath(i), err)
+							}
+
+							t.List = append(t.List, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".List", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".List", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+
+				// t.Map (map[string]typegen.Node) (map)
+			case "Map":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Map", err)
+					}
+
+					t.Map = map[string]jsg.Node{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Map", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Map", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Map", errors.New("string too long"))
+									}
+									return jr.PathError(".Map", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Map", err)
+							}
+							var v jsg.Node
+
+							if err := v.UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Map"+jsg.KeyPath(string(key)), err)
+							}
+
+							t.Map[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Map", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Map", errors.New("map too large"))
+							}
+						}
+					}
+				}
+
+				// t.Ptr (typegen.Node) (struct)
+			case "Ptr":
+
+				{
+					null, err := jr.PeekNull()
+					if err != nil {
+						return jr.PathError(".Ptr", err)
+					}
+					if null {
+						if err := jr.ReadNull(); err != nil {
+							return jr.PathError(".Ptr", err)
+						}
+					} else {
+						t.Ptr = new(jsg.Node)
+						if err := t.Ptr.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(".Ptr", err)
+						}
+					}
+				}
+
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *NodeContainer) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.Any, cb); err != nil {
+		return fmt.Errorf("t.Any: %w", err)
+	}
+
+	for _, v := range t.List {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	{
+		keys := make([]string, 0, len(t.Map))
+
+		for k := range t.Map {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Map[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+
+	if t.Ptr != nil {
+		if err := jsg.ScanLinks(t.Ptr, cb); err != nil {
+			return fmt.Errorf("t.Ptr: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *StrictFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Name (string) (string)
+	if len("Name") > 8192 {
+		return fmt.Errorf("String in field \"Name\" was too long")
+	}
+	if err := jw.WriteString(string("Name")); err != nil {
+		return fmt.Errorf("\"Name\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Name) > 8192 {
+		return fmt.Errorf("String in field t.Name was too long")
+	}
+	if err := jw.WriteString(string(t.Name)); err != nil {
+		return fmt.Errorf("t.Name: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Count (uint64) (uint64)
+	if len("count") > 8192 {
+		return fmt.Errorf("String in field \"count\" was too long")
+	}
+	if err := jw.WriteString(string("count")); err != nil {
+		return fmt.Errorf("\"count\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.Count)); err != nil {
+		return fmt.Errorf("t.Count: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *StrictFields) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = StrictFields{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	var seen [2]bool
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Name (string) (string)
+			case "Name":
+				if seen[0] {
+					return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+				}
+				seen[0] = true
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Name", errors.New("string too long"))
+						}
+						return jr.PathError(".Name", err)
+					}
+					t.Name = string(sval)
+				}
+
+				// t.Count (uint64) (uint64)
+			case "count":
+				if seen[1] {
+					return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+				}
+				seen[1] = true
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".Count", err)
+					}
+
+					t.Count = uint64(nval)
+
+				}
+			default:
+
+				return jr.PathError(jsg.KeyPath(name), errors.New("unknown field"))
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *StrictFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *StrictFieldsLossless) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	unknown := jsg.NewUnknownFields(t.Extra)
+	written := 0
+	if err := unknown.WriteBefore(jw, "Name", &written); err != nil {
+		return fmt.Errorf("t.Extra: %w", err)
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Name (string) (string)
+	if len("Name") > 8192 {
+		return fmt.Errorf("String in field \"Name\" was too long")
+	}
+	if err := jw.WriteString(string("Name")); err != nil {
+		return fmt.Errorf("\"Name\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Name) > 8192 {
+		return fmt.Errorf("String in field t.Name was too long")
+	}
+	if err := jw.WriteString(string(t.Name)); err != nil {
+		return fmt.Errorf("t.Name: %w", err)
+	}
+	written++
+	if err := unknown.WriteRest(jw, &written); err != nil {
+		return fmt.Errorf("t.Extra: %w", err)
+	}
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *StrictFieldsLossless) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = StrictFieldsLossless{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	var seen [1]bool
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Name (string) (string)
+			case "Name":
+				if seen[0] {
+					return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+				}
+				seen[0] = true
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Name", errors.New("string too long"))
+						}
+						return jr.PathError(".Name", err)
+					}
+					t.Name = string(sval)
+				}
+			default:
+
+				// Field doesn't exist on this type, so keep it as is
+
+				if _, ok := t.Extra[name]; ok {
+					return jr.PathError(jsg.KeyPath(name), errors.New("duplicate field"))
+				}
+
+				var v jsg.Deferred
+				if err := v.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+				if t.Extra == nil {
+					t.Extra = make(map[string]jsg.Deferred)
+				}
+				t.Extra[name] = v
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *StrictFieldsLossless) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	{
+		keys := make([]string, 0, len(t.Extra))
+
+		for k := range t.Extra {
+			keys = append(keys, string(k))
+		}
+
+		sort.Strings(keys)
+		for _, k := range keys {
+
+			v := t.Extra[string(k)]
+
+			if err := jsg.ScanLinks(&v, cb); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+	}
+	return nil
+}
+
+func (t *StrictInlineUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.Strict != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("StrictInlineUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.Strict (testing.StrictFields) (struct)
+	case t.Strict != nil:
+		{
+			var buf bytes.Buffer
+			if err := t.Strict.MarshalDagJSON(&buf); err != nil {
+				return fmt.Errorf("t.Strict: %w", err)
+			}
+			if err := jsg.WriteInlineUnion(jw, "kind", "strict", buf.Bytes()); err != nil {
+				return fmt.Errorf("StrictInlineUnion: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *StrictInlineUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = StrictInlineUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	discriminant, mr, err := jsg.ReadInlineUnion(jr, "kind")
+
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	// The member is read from its own reader, which has the
+	// caller's options.
+	defer mr.Release(nil)
+	jr = mr
+
+	switch discriminant {
+
+	// t.Strict (testing.StrictFields) (struct)
+
+	case "strict":
+
+		t.Strict = new(StrictFields)
+
+		if err := (*t.Strict).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Strict", err)
+		}
+
+	default:
+
+		return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+
+	}
+
+	return nil
+}
+
+func (t *StrictInlineUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Strict != nil {
+		if err := jsg.ScanLinks(t.Strict, cb); err != nil {
+			return fmt.Errorf("t.Strict: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *LosslessInlineUnion) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+
+	members := 0
+
+	if t.Lossless != nil {
+		members++
+	}
+
+	if members != 1 {
+		return fmt.Errorf("LosslessInlineUnion: union must have exactly one member set, found %d", members)
+	}
+
+	switch {
+
+	// t.Lossless (testing.StrictFieldsLossless) (struct)
+	case t.Lossless != nil:
+		{
+			var buf bytes.Buffer
+			if err := t.Lossless.MarshalDagJSON(&buf); err != nil {
+				return fmt.Errorf("t.Lossless: %w", err)
+			}
+			if err := jsg.WriteInlineUnion(jw, "kind", "lossless", buf.Bytes()); err != nil {
+				return fmt.Errorf("LosslessInlineUnion: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *LosslessInlineUnion) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = LosslessInlineUnion{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+
+	discriminant, mr, err := jsg.ReadInlineUnion(jr, "kind")
+
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	// The member is read from its own reader, which has the
+	// caller's options.
+	defer mr.Release(nil)
+	jr = mr
+
+	switch discriminant {
+
+	// t.Lossless (testing.StrictFieldsLossless) (struct)
+
+	case "lossless":
+
+		t.Lossless = new(StrictFieldsLossless)
+
+		if err := (*t.Lossless).UnmarshalDagJSON(jr); err != nil {
+			return jr.PathError(".Lossless", err)
+		}
+
+	default:
+
+		return jr.PathError("", fmt.Errorf("unknown union discriminant %q", discriminant))
+
+	}
+
+	return nil
+}
+
+func (t *LosslessInlineUnion) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Lossless != nil {
+		if err := jsg.ScanLinks(t.Lossless, cb); err != nil {
+			return fmt.Errorf("t.Lossless: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *UnionHolder) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.KeyedUnion (testing.KeyedUnion) (struct)
+	if len("KeyedUnion") > 8192 {
+		return fmt.Errorf("String in field \"KeyedUnion\" was too long")
+	}
+	if err := jw.WriteString(string("KeyedUnion")); err != nil {
+		return fmt.Errorf("\"KeyedUnion\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.KeyedUnion.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.KeyedUnion: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Name (string) (string)
+	if len("Name") > 8192 {
+		return fmt.Errorf("String in field \"Name\" was too long")
+	}
+	if err := jw.WriteString(string("Name")); err != nil {
+		return fmt.Errorf("\"Name\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Name) > 8192 {
+		return fmt.Errorf("String in field t.Name was too long")
+	}
+	if err := jw.WriteString(string(t.Name)); err != nil {
+		return fmt.Errorf("t.Name: %w", err)
+	}
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *UnionHolder) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = UnionHolder{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.KeyedUnion (testing.KeyedUnion) (struct)
+			case "KeyedUnion":
+
+				if err := t.KeyedUnion.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".KeyedUnion", err)
+				}
+
+				// t.Name (string) (string)
+			case "Name":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Name", errors.New("string too long"))
+						}
+						return jr.PathError(".Name", err)
+					}
+					t.Name = string(sval)
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *UnionHolder) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.KeyedUnion, cb); err != nil {
+		return fmt.Errorf("t.KeyedUnion: %w", err)
+	}
+
+	return nil
+}
+
+func (t *RequiredFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Name (string) (string)
+	if len("Name") > 8192 {
+		return fmt.Errorf("String in field \"Name\" was too long")
+	}
+	if err := jw.WriteString(string("Name")); err != nil {
+		return fmt.Errorf("\"Name\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Name) > 8192 {
+		return fmt.Errorf("String in field t.Name was too long")
+	}
+	if err := jw.WriteString(string(t.Name)); err != nil {
+		return fmt.Errorf("t.Name: %w", err)
+	}
+	written++
+	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
 			return err
 		}
 	}
 
-	// t.NewStruct (testing.SimpleTypeOne) (struct)
-	if len("NewStruct") > 8192 {
-		return fmt.Errorf("String in field \"NewStruct\" was too long")
+	// t.Count (uint64) (uint64)
+	if len("count") > 8192 {
+		return fmt.Errorf("String in field \"count\" was too long")
 	}
-	if err := jw.WriteString(string("NewStruct")); err != nil {
-		return fmt.Errorf("\"NewStruct\": %w", err)
+	if err := jw.WriteString(string("count")); err != nil {
+		return fmt.Errorf("\"count\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := t.NewStruct.MarshalDagJSON(jw); err != nil {
-		return fmt.Errorf("t.NewStruct: %w", err)
+
+	if err := jw.WriteUint64(uint64(t.Count)); err != nil {
+		return fmt.Errorf("t.Count: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -1653,385 +10264,206 @@ func (t *SimpleStructV2) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.OldStruct (testing.SimpleTypeOne) (struct)
-	if len("OldStruct") > 8192 {
-		return fmt.Errorf("String in field \"OldStruct\" was too long")
+	// t.Tags ([]string) (slice)
+	if len("tags") > 8192 {
+		return fmt.Errorf("String in field \"tags\" was too long")
 	}
-	if err := jw.WriteString(string("OldStruct")); err != nil {
-		return fmt.Errorf("\"OldStruct\": %w", err)
+	if err := jw.WriteString(string("tags")); err != nil {
+		return fmt.Errorf("\"tags\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := t.OldStruct.MarshalDagJSON(jw); err != nil {
-		return fmt.Errorf("t.OldStruct: %w", err)
+	if len(t.Tags) > 8192 {
+		return fmt.Errorf("Slice value in field t.Tags was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Tags: %w", err)
+	}
+	for i, v := range t.Tags {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Tags: %w", err)
+			}
+		}
+		if len(v) > 8192 {
+			return fmt.Errorf("String in field v was too long")
+		}
+		if err := jw.WriteString(string(v)); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Tags: %w", err)
 	}
+
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
 	return nil
 }
-func (t *SimpleStructV2) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = SimpleStructV2{}
+func (t *RequiredFields) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = RequiredFields{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("SimpleStructV2: %w", err)
+		return jr.PathError("", err)
 	}
+
+	var seen [3]bool
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("SimpleStructV2: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("SimpleStructV2: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("SimpleStructV2: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("SimpleStructV2: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("SimpleStructV2: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.NewMap (map[string]testing.SimpleTypeOne) (map)
-			case "NewMap":
-				if err := jr.ReadObjectOpen(); err != nil {
-					return fmt.Errorf("t.NewMap: %w", err)
-				}
-
-				t.NewMap = map[string]SimpleTypeOne{}
-
-				close, err := jr.PeekObjectClose()
-				if err != nil {
-					return fmt.Errorf("t.NewMap: %w", err)
-				}
-				if close {
-					if err := jr.ReadObjectClose(); err != nil {
-						return fmt.Errorf("t.NewMap: %w", err)
-					}
-				} else {
-					for i, l := 0, 8192; i < l; i++ {
-						var k string
-						{
-							sval, err := jr.ReadString(8192)
-							if err != nil {
-								if errors.Is(err, jsg.ErrLimitExceeded) {
-									return fmt.Errorf("k: string too long")
-								}
-								return fmt.Errorf("k: %w", err)
-							}
-							k = string(sval)
-						}
-						if err := jr.ReadObjectColon(); err != nil {
-							return fmt.Errorf("t.NewMap: %w", err)
-						}
-						var v SimpleTypeOne
-
-						if err := v.UnmarshalDagJSON(jr); err != nil {
-							return fmt.Errorf("unmarshaling v: %w", err)
-						}
-
-						t.NewMap[k] = v
-						close, err := jr.ReadObjectCloseOrComma()
-						if err != nil {
-							return fmt.Errorf("t.NewMap: %w", err)
-						}
-						if close {
-							break
-						}
-					}
-				}
-
-				// t.NewNum (uint64) (uint64)
-			case "NewNum":
-				{
-
-					nval, err := jr.ReadNumberAsUint64()
-					if err != nil {
-						return fmt.Errorf("t.NewNum: %w", err)
-					}
-					t.NewNum = uint64(nval)
-
-				}
-
-				// t.NewPtr (cid.Cid) (struct)
-			case "NewPtr":
-				{
-
-					c, err := jr.ReadCidOrNull()
-					if err != nil {
-						return fmt.Errorf("t.NewPtr: %w", err)
-					}
-					t.NewPtr = c
-
-				}
-
-				// t.NewStr (string) (string)
-			case "NewStr":
+			// t.Name (string) (string)
+			case "Name":
+				seen[0] = true
 				{
 					sval, err := jr.ReadString(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.NewStr: string too long")
-						}
-						return fmt.Errorf("t.NewStr: %w", err)
-					}
-					t.NewStr = string(sval)
-				}
-
-				// t.OldMap (map[string]testing.SimpleTypeOne) (map)
-			case "OldMap":
-				if err := jr.ReadObjectOpen(); err != nil {
-					return fmt.Errorf("t.OldMap: %w", err)
-				}
-
-				t.OldMap = map[string]SimpleTypeOne{}
-
-				close, err := jr.PeekObjectClose()
-				if err != nil {
-					return fmt.Errorf("t.OldMap: %w", err)
-				}
-				if close {
-					if err := jr.ReadObjectClose(); err != nil {
-						return fmt.Errorf("t.OldMap: %w", err)
-					}
-				} else {
-					for i, l := 0, 8192; i < l; i++ {
-						var k string
-						{
-							sval, err := jr.ReadString(8192)
-							if err != nil {
-								if errors.Is(err, jsg.ErrLimitExceeded) {
-									return fmt.Errorf("k: string too long")
-								}
-								return fmt.Errorf("k: %w", err)
-							}
-							k = string(sval)
-						}
-						if err := jr.ReadObjectColon(); err != nil {
-							return fmt.Errorf("t.OldMap: %w", err)
-						}
-						var v SimpleTypeOne
-
-						if err := v.UnmarshalDagJSON(jr); err != nil {
-							return fmt.Errorf("unmarshaling v: %w", err)
-						}
-
-						t.OldMap[k] = v
-						close, err := jr.ReadObjectCloseOrComma()
-						if err != nil {
-							return fmt.Errorf("t.OldMap: %w", err)
-						}
-						if close {
-							break
+							return jr.PathError(".Name", errors.New("string too long"))
 						}
+						return jr.PathError(".Name", err)
 					}
+					t.Name = string(sval)
 				}
 
-				// t.OldNum (uint64) (uint64)
-			case "OldNum":
+				// t.Count (uint64) (uint64)
+			case "count":
+				seen[1] = true
 				{
 
 					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						return fmt.Errorf("t.OldNum: %w", err)
-					}
-					t.OldNum = uint64(nval)
-
-				}
-
-				// t.OldPtr (cid.Cid) (struct)
-			case "OldPtr":
-				{
-
-					c, err := jr.ReadCidOrNull()
-					if err != nil {
-						return fmt.Errorf("t.OldPtr: %w", err)
-					}
-					t.OldPtr = c
-
-				}
-
-				// t.OldStr (string) (string)
-			case "OldStr":
-				{
-					sval, err := jr.ReadString(8192)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.OldStr: string too long")
-						}
-						return fmt.Errorf("t.OldStr: %w", err)
-					}
-					t.OldStr = string(sval)
-				}
-
-				// t.NewArray ([]testing.SimpleTypeOne) (slice)
-			case "NewArray":
-				{
-
-					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.NewArray: %w", err)
-					}
-
-					close, err := jr.PeekArrayClose()
-					if err != nil {
-						return fmt.Errorf("t.NewArray: %w", err)
-					}
-					if close {
-						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.NewArray: %w", err)
-						}
-
-					} else {
-						for i := 0; i < 8192; i++ {
-							item := make([]SimpleTypeOne, 1)
-
-							if err := item[0].UnmarshalDagJSON(jr); err != nil {
-								return fmt.Errorf("unmarshaling item[0]: %w", err)
-							}
-
-							t.NewArray = append(t.NewArray, item[0])
-
-							close, err := jr.ReadArrayCloseOrComma()
-							if err != nil {
-								return fmt.Errorf("t.NewArray: %w", err)
-							}
-							if close {
-								break
-							}
-							if i == 8192-1 {
-								return fmt.Errorf("t.NewArray: slice too large")
-							}
-						}
+						return jr.PathError(".Count", err)
 					}
 
-				}
-
-				// t.NewBytes ([]uint8) (slice)
-			case "NewBytes":
+					t.Count = uint64(nval)
 
-				{
-					bval, err := jr.ReadBytes(2097152)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.NewBytes: byte array too large")
-						}
-						return fmt.Errorf("t.NewBytes: %w", err)
-					}
-					if len(bval) > 0 {
-						t.NewBytes = []uint8(bval)
-					}
 				}
 
-				// t.OldArray ([]testing.SimpleTypeOne) (slice)
-			case "OldArray":
+				// t.Tags ([]string) (slice)
+			case "tags":
 				{
 
 					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.OldArray: %w", err)
+						return jr.PathError(".Tags", err)
 					}
 
 					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.OldArray: %w", err)
+						return jr.PathError(".Tags", err)
 					}
 					if close {
 						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.OldArray: %w", err)
+							return jr.PathError(".Tags", err)
 						}
 
 					} else {
 						for i := 0; i < 8192; i++ {
-							item := make([]SimpleTypeOne, 1)
-
-							if err := item[0].UnmarshalDagJSON(jr); err != nil {
-								return fmt.Errorf("unmarshaling item[0]: %w", err)
+							item := make([]string, 1)
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Tags"+jsg.IndexPath(i), errors.New("string too long"))
+									}
+									return jr.PathError(".Tags"+jsg.IndexPath(i), err)
+								}
+								item[0] = string(sval)
 							}
-
-							t.OldArray = append(t.OldArray, item[0])
+							t.Tags = append(t.Tags, item[0])
 
 							close, err := jr.ReadArrayCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.OldArray: %w", err)
+								return jr.PathError(".Tags", err)
 							}
 							if close {
 								break
 							}
 							if i == 8192-1 {
-								return fmt.Errorf("t.OldArray: slice too large")
+								return jr.PathError(".Tags", errors.New("slice too large"))
 							}
 						}
-					}
-
-				}
-
-				// t.OldBytes ([]uint8) (slice)
-			case "OldBytes":
-
-				{
-					bval, err := jr.ReadBytes(2097152)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.OldBytes: byte array too large")
-						}
-						return fmt.Errorf("t.OldBytes: %w", err)
-					}
-					if len(bval) > 0 {
-						t.OldBytes = []uint8(bval)
-					}
-				}
-
-				// t.NewStruct (testing.SimpleTypeOne) (struct)
-			case "NewStruct":
-
-				if err := t.NewStruct.UnmarshalDagJSON(jr); err != nil {
-					return fmt.Errorf("unmarshaling t.NewStruct: %w", err)
-				}
-
-				// t.OldStruct (testing.SimpleTypeOne) (struct)
-			case "OldStruct":
+					}
 
-				if err := t.OldStruct.UnmarshalDagJSON(jr); err != nil {
-					return fmt.Errorf("unmarshaling t.OldStruct: %w", err)
 				}
-
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("SimpleStructV2: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("SimpleStructV2: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("SimpleStructV2: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
+	var missing []string
+
+	if !seen[0] {
+		missing = append(missing, "Name")
+	}
+
+	if !seen[1] {
+		missing = append(missing, "count")
+	}
+
+	if len(missing) > 0 {
+		return jr.PathError("", fmt.Errorf("missing required fields %q", missing))
+	}
+
+	return nil
+}
+func (t *RequiredFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
 	return nil
 }
-func (t *RenamedFields) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+
+func (t *DefaultFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2041,21 +10473,42 @@ func (t *RenamedFields) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Foo (int64) (int64)
-	if len("foo") > 8192 {
-		return fmt.Errorf("String in field \"foo\" was too long")
+	// t.Enabled (bool) (bool)
+	if len("enabled") > 8192 {
+		return fmt.Errorf("String in field \"enabled\" was too long")
 	}
-	if err := jw.WriteString(string("foo")); err != nil {
-		return fmt.Errorf("\"foo\": %w", err)
+	if err := jw.WriteString(string("enabled")); err != nil {
+		return fmt.Errorf("\"enabled\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-
-	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
-		return fmt.Errorf("t.Foo: %w", err)
+	if err := jw.WriteBool(t.Enabled); err != nil {
+		return fmt.Errorf("t.Enabled: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
 	}
 
+	// t.Kind (testing.NamedString) (string)
+	if len("kind") > 8192 {
+		return fmt.Errorf("String in field \"kind\" was too long")
+	}
+	if err := jw.WriteString(string("kind")); err != nil {
+		return fmt.Errorf("\"kind\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Kind) > 8192 {
+		return fmt.Errorf("String in field t.Kind was too long")
+	}
+	if err := jw.WriteString(string(t.Kind)); err != nil {
+		return fmt.Errorf("t.Kind: %w", err)
+	}
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -2063,110 +10516,265 @@ func (t *RenamedFields) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Bar (string) (string)
-	if len("beep") > 8192 {
-		return fmt.Errorf("String in field \"beep\" was too long")
+	// t.Limit (uint32) (uint32)
+	if len("limit") > 8192 {
+		return fmt.Errorf("String in field \"limit\" was too long")
 	}
-	if err := jw.WriteString(string("beep")); err != nil {
-		return fmt.Errorf("\"beep\": %w", err)
+	if err := jw.WriteString(string("limit")); err != nil {
+		return fmt.Errorf("\"limit\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Bar) > 8192 {
-		return fmt.Errorf("String in field t.Bar was too long")
+
+	if err := jw.WriteUint64(uint64(t.Limit)); err != nil {
+		return fmt.Errorf("t.Limit: %w", err)
 	}
-	if err := jw.WriteString(string(t.Bar)); err != nil {
-		return fmt.Errorf("t.Bar: %w", err)
+
+	written++
+	if t.Name != "anon" {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Name (string) (string)
+	if t.Name != "anon" {
+		if len("name") > 8192 {
+			return fmt.Errorf("String in field \"name\" was too long")
+		}
+		if err := jw.WriteString(string("name")); err != nil {
+			return fmt.Errorf("\"name\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		if len(t.Name) > 8192 {
+			return fmt.Errorf("String in field t.Name was too long")
+		}
+		if err := jw.WriteString(string(t.Name)); err != nil {
+			return fmt.Errorf("t.Name: %w", err)
+		}
+		written++
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Ratio (float64) (float64)
+	if len("ratio") > 8192 {
+		return fmt.Errorf("String in field \"ratio\" was too long")
 	}
+	if err := jw.WriteString(string("ratio")); err != nil {
+		return fmt.Errorf("\"ratio\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteFloat64(float64(t.Ratio)); err != nil {
+		return fmt.Errorf("t.Ratio: %w", err)
+	}
+
 	written++
+	if t.Retries != -3 {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Retries (int8) (int8)
+	if t.Retries != -3 {
+		if len("retries") > 8192 {
+			return fmt.Errorf("String in field \"retries\" was too long")
+		}
+		if err := jw.WriteString(string("retries")); err != nil {
+			return fmt.Errorf("\"retries\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+
+		if err := jw.WriteInt64(int64(t.Retries)); err != nil {
+			return fmt.Errorf("t.Retries: %w", err)
+		}
+
+		written++
+	}
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
 	return nil
 }
-func (t *RenamedFields) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = RenamedFields{}
+func (t *DefaultFields) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = DefaultFields{}
+
+	t.Enabled = true
+
+	t.Kind = "a=b"
+
+	t.Limit = 100
+
+	t.Name = "anon"
+
+	t.Ratio = 0.5
+
+	t.Retries = -3
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("RenamedFields: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("RenamedFields: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("RenamedFields: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("RenamedFields: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("RenamedFields: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("RenamedFields: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Foo (int64) (int64)
-			case "foo":
+			// t.Enabled (bool) (bool)
+			case "enabled":
 				{
+					bval, err := jr.ReadBool()
+					if err != nil {
+						return jr.PathError(".Enabled", err)
+					}
+					t.Enabled = bool(bval)
+				}
 
-					nval, err := jr.ReadNumberAsInt64()
+				// t.Kind (testing.NamedString) (string)
+			case "kind":
+				{
+					sval, err := jr.ReadString(8192)
 					if err != nil {
-						return fmt.Errorf("t.Foo: %w", err)
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Kind", errors.New("string too long"))
+						}
+						return jr.PathError(".Kind", err)
+					}
+					t.Kind = NamedString(sval)
+				}
+
+				// t.Limit (uint32) (uint32)
+			case "limit":
+				{
+
+					nval, err := jr.ReadNumberAsUint64()
+					if err != nil {
+						return jr.PathError(".Limit", err)
+					}
+
+					if nval > math.MaxUint32 {
+						return jr.PathError(".Limit", fmt.Errorf("value %d out of range for uint32", nval))
 					}
-					t.Foo = int64(nval)
+
+					t.Limit = uint32(nval)
 
 				}
 
-				// t.Bar (string) (string)
-			case "beep":
+				// t.Name (string) (string)
+			case "name":
 				{
 					sval, err := jr.ReadString(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Bar: string too long")
+							return jr.PathError(".Name", errors.New("string too long"))
 						}
-						return fmt.Errorf("t.Bar: %w", err)
+						return jr.PathError(".Name", err)
 					}
-					t.Bar = string(sval)
+					t.Name = string(sval)
+				}
+
+				// t.Ratio (float64) (float64)
+			case "ratio":
+				{
+
+					nval, err := jr.ReadNumberAsFloat64()
+					if err != nil {
+						return jr.PathError(".Ratio", err)
+					}
+					t.Ratio = float64(nval)
+
+				}
+
+				// t.Retries (int8) (int8)
+			case "retries":
+				{
+
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".Retries", err)
+					}
+
+					if nval < math.MinInt8 || nval > math.MaxInt8 {
+						return jr.PathError(".Retries", fmt.Errorf("value %d out of range for int8", nval))
+					}
+
+					t.Retries = int8(nval)
+
 				}
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("RenamedFields: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("RenamedFields: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("RenamedFields: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *TestEmpty) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *DefaultFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *OmitEmptyKinds) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2176,23 +10784,207 @@ func (t *TestEmpty) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Cat (int64) (int64)
-	if len("Cat") > 8192 {
-		return fmt.Errorf("String in field \"Cat\" was too long")
+	// t.Count (int64) (int64)
+	if t.Count != 0 {
+		if len("Count") > 8192 {
+			return fmt.Errorf("String in field \"Count\" was too long")
+		}
+		if err := jw.WriteString(string("Count")); err != nil {
+			return fmt.Errorf("\"Count\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+
+		if err := jw.WriteInt64(int64(t.Count)); err != nil {
+			return fmt.Errorf("t.Count: %w", err)
+		}
+
+		written++
 	}
-	if err := jw.WriteString(string("Cat")); err != nil {
-		return fmt.Errorf("\"Cat\": %w", err)
+	if t.Enabled {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Enabled (bool) (bool)
+	if t.Enabled {
+		if len("Enabled") > 8192 {
+			return fmt.Errorf("String in field \"Enabled\" was too long")
+		}
+		if err := jw.WriteString(string("Enabled")); err != nil {
+			return fmt.Errorf("\"Enabled\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		if err := jw.WriteBool(t.Enabled); err != nil {
+			return fmt.Errorf("t.Enabled: %w", err)
+		}
+		written++
+	}
+	if t.Fixed != ([2]int16{}) {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Fixed ([2]int16) (array)
+	if t.Fixed != ([2]int16{}) {
+		if len("Fixed") > 8192 {
+			return fmt.Errorf("String in field \"Fixed\" was too long")
+		}
+		if err := jw.WriteString(string("Fixed")); err != nil {
+			return fmt.Errorf("\"Fixed\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		if len(t.Fixed) > 8192 {
+			return fmt.Errorf("Slice value in field t.Fixed was too long")
+		}
+		if err := jw.WriteArrayOpen(); err != nil {
+			return fmt.Errorf("t.Fixed: %w", err)
+		}
+		for i, v := range t.Fixed {
+			if i > 0 {
+				if err := jw.WriteComma(); err != nil {
+					return fmt.Errorf("t.Fixed: %w", err)
+				}
+			}
+
+			if err := jw.WriteInt64(int64(v)); err != nil {
+				return fmt.Errorf("v: %w", err)
+			}
+
+		}
+		if err := jw.WriteArrayClose(); err != nil {
+			return fmt.Errorf("t.Fixed: %w", err)
+		}
+		written++
+	}
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Kept (uint64) (uint64)
+	if len("Kept") > 8192 {
+		return fmt.Errorf("String in field \"Kept\" was too long")
+	}
+	if err := jw.WriteString(string("Kept")); err != nil {
+		return fmt.Errorf("\"Kept\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.Kept)); err != nil {
+		return fmt.Errorf("t.Kept: %w", err)
+	}
+
+	written++
+	if len(t.Labels) != 0 {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+	}
+
+	// t.Labels (map[string]string) (map)
+	if len(t.Labels) != 0 {
+		if len("Labels") > 8192 {
+			return fmt.Errorf("String in field \"Labels\" was too long")
+		}
+		if err := jw.WriteString(string("Labels")); err != nil {
+			return fmt.Errorf("\"Labels\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		{
+			if len(t.Labels) > 4096 {
+				return fmt.Errorf("cannot marshal t.Labels map too large")
+			}
+
+			if err := jw.WriteObjectOpen(); err != nil {
+				return fmt.Errorf("t.Labels: %w", err)
+			}
+
+			keys := make([]string, 0, len(t.Labels))
+
+			for k := range t.Labels {
+				keys = append(keys, string(k))
+			}
+
+			sort.Strings(keys)
+			for i, k := range keys {
+				if i > 0 {
+					if err := jw.WriteComma(); err != nil {
+						return fmt.Errorf("t.Labels: %w", err)
+					}
+				}
+
+				v := t.Labels[string(k)]
+
+				if len(k) > 8192 {
+					return fmt.Errorf("String in field k was too long")
+				}
+				if err := jw.WriteString(string(k)); err != nil {
+					return fmt.Errorf("k: %w", err)
+				}
+				if err := jw.WriteObjectColon(); err != nil {
+					return fmt.Errorf("t.Labels: %w", err)
+				}
+
+				if len(v) > 8192 {
+					return fmt.Errorf("String in field v was too long")
+				}
+				if err := jw.WriteString(string(v)); err != nil {
+					return fmt.Errorf("v: %w", err)
+				}
+			}
+			if err := jw.WriteObjectClose(); err != nil {
+				return fmt.Errorf("t.Labels: %w", err)
+			}
+		}
+
+		written++
 	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
+	if t.Link.Defined() {
+		if written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
 	}
 
-	if err := jw.WriteInt64(int64(t.Cat)); err != nil {
-		return fmt.Errorf("t.Cat: %w", err)
-	}
+	// t.Link (cid.Cid) (struct)
+	if t.Link.Defined() {
+		if len("Link") > 8192 {
+			return fmt.Errorf("String in field \"Link\" was too long")
+		}
+		if err := jw.WriteString(string("Link")); err != nil {
+			return fmt.Errorf("\"Link\": %w", err)
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
 
-	written++
-	if t.Foo != nil {
+		if err := jw.WriteCid(t.Link); err != nil {
+			return fmt.Errorf("t.Link: %w", err)
+		}
+
+		written++
+	}
+	if t.Ratio != 0 {
 		if written > 0 {
 			if err := jw.WriteComma(); err != nil {
 				return err
@@ -2200,32 +10992,25 @@ func (t *TestEmpty) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Foo (string) (string)
-	if t.Foo != nil {
-		if len("Foo") > 8192 {
-			return fmt.Errorf("String in field \"Foo\" was too long")
+	// t.Ratio (float32) (float32)
+	if t.Ratio != 0 {
+		if len("Ratio") > 8192 {
+			return fmt.Errorf("String in field \"Ratio\" was too long")
 		}
-		if err := jw.WriteString(string("Foo")); err != nil {
-			return fmt.Errorf("\"Foo\": %w", err)
+		if err := jw.WriteString(string("Ratio")); err != nil {
+			return fmt.Errorf("\"Ratio\": %w", err)
 		}
 		if err := jw.WriteObjectColon(); err != nil {
 			return err
 		}
-		if t.Foo == nil {
-			if err := jw.WriteNull(); err != nil {
-				return fmt.Errorf("t.Foo: %w", err)
-			}
-		} else {
-			if len(*t.Foo) > 8192 {
-				return fmt.Errorf("String in field t.Foo was too long")
-			}
-			if err := jw.WriteString(string(*t.Foo)); err != nil {
-				return fmt.Errorf("t.Foo: %w", err)
-			}
+
+		if err := jw.WriteFloat32(float32(t.Ratio)); err != nil {
+			return fmt.Errorf("t.Ratio: %w", err)
 		}
+
 		written++
 	}
-	if t.Beep != "" {
+	if !t.Window.IsZero() {
 		if written > 0 {
 			if err := jw.WriteComma(); err != nil {
 				return err
@@ -2233,22 +11018,19 @@ func (t *TestEmpty) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Beep (string) (string)
-	if t.Beep != "" {
-		if len("Beep") > 8192 {
-			return fmt.Errorf("String in field \"Beep\" was too long")
+	// t.Window (testing.Window) (struct)
+	if !t.Window.IsZero() {
+		if len("Window") > 8192 {
+			return fmt.Errorf("String in field \"Window\" was too long")
 		}
-		if err := jw.WriteString(string("Beep")); err != nil {
-			return fmt.Errorf("\"Beep\": %w", err)
+		if err := jw.WriteString(string("Window")); err != nil {
+			return fmt.Errorf("\"Window\": %w", err)
 		}
 		if err := jw.WriteObjectColon(); err != nil {
 			return err
 		}
-		if len(t.Beep) > 8192 {
-			return fmt.Errorf("String in field t.Beep was too long")
-		}
-		if err := jw.WriteString(string(t.Beep)); err != nil {
-			return fmt.Errorf("t.Beep: %w", err)
+		if err := t.Window.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("t.Window: %w", err)
 		}
 		written++
 	}
@@ -2257,103 +11039,246 @@ func (t *TestEmpty) MarshalDagJSON(w io.Writer) error {
 	}
 	return nil
 }
-func (t *TestEmpty) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = TestEmpty{}
+func (t *OmitEmptyKinds) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = OmitEmptyKinds{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("TestEmpty: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("TestEmpty: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("TestEmpty: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("TestEmpty: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("TestEmpty: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("TestEmpty: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Cat (int64) (int64)
-			case "Cat":
+			// t.Count (int64) (int64)
+			case "Count":
 				{
 
 					nval, err := jr.ReadNumberAsInt64()
 					if err != nil {
-						return fmt.Errorf("t.Cat: %w", err)
+						return jr.PathError(".Count", err)
 					}
-					t.Cat = int64(nval)
+
+					t.Count = int64(nval)
 
 				}
 
-				// t.Foo (string) (string)
-			case "Foo":
+				// t.Enabled (bool) (bool)
+			case "Enabled":
 				{
-					sval, err := jr.ReadStringOrNull(8192)
+					bval, err := jr.ReadBool()
 					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Foo: string too long")
+						return jr.PathError(".Enabled", err)
+					}
+					t.Enabled = bool(bval)
+				}
+
+				// t.Fixed ([2]int16) (array)
+			case "Fixed":
+				if err := jr.ReadArrayOpen(); err != nil {
+					return jr.PathError(".Fixed", err)
+				}
+
+				t.Fixed = [2]int16{}
+				for i := 0; i < 8192; i++ {
+					{
+
+						nval, err := jr.ReadNumberAsInt64()
+						if err != nil {
+							return jr.PathError(".Fixed"+jsg.IndexPath(i), err)
 						}
-						return fmt.Errorf("t.Foo: %w", err)
+
+						if nval < math.MinInt16 || nval > math.MaxInt16 {
+							return jr.PathError(".Fixed"+jsg.IndexPath(i), fmt.Errorf("value %d out of range for int16", nval))
+						}
+
+						t.Fixed[i] = int16(nval)
+
 					}
-					if sval != nil {
-						t.Foo = (*string)(sval)
+					close, err := jr.ReadArrayCloseOrComma()
+					if err != nil {
+						return jr.PathError(".Fixed", err)
+					}
+					if close {
+						break
+					}
+					if i == 8192-1 {
+						return jr.PathError(".Fixed", errors.New("array too large"))
 					}
 				}
 
-				// t.Beep (string) (string)
-			case "Beep":
+				// t.Kept (uint64) (uint64)
+			case "Kept":
 				{
-					sval, err := jr.ReadString(8192)
+
+					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Beep: string too long")
+						return jr.PathError(".Kept", err)
+					}
+
+					t.Kept = uint64(nval)
+
+				}
+
+				// t.Labels (map[string]string) (map)
+			case "Labels":
+				{
+					if err := jr.ReadObjectOpen(); err != nil {
+						return jr.PathError(".Labels", err)
+					}
+
+					t.Labels = map[string]string{}
+
+					close, err := jr.PeekObjectClose()
+					if err != nil {
+						return jr.PathError(".Labels", err)
+					}
+					if close {
+						if err := jr.ReadObjectClose(); err != nil {
+							return jr.PathError(".Labels", err)
+						}
+					} else {
+						for i, l := 0, 8192; i < l; i++ {
+							var key string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Labels", errors.New("string too long"))
+									}
+									return jr.PathError(".Labels", err)
+								}
+								key = string(sval)
+							}
+							if err := jr.ReadObjectColon(); err != nil {
+								return jr.PathError(".Labels", err)
+							}
+							var v string
+							{
+								sval, err := jr.ReadString(8192)
+								if err != nil {
+									if errors.Is(err, jsg.ErrLimitExceeded) {
+										return jr.PathError(".Labels"+jsg.KeyPath(string(key)), errors.New("string too long"))
+									}
+									return jr.PathError(".Labels"+jsg.KeyPath(string(key)), err)
+								}
+								v = string(sval)
+							}
+							t.Labels[key] = v
+
+							close, err := jr.ReadObjectCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Labels", err)
+							}
+							if close {
+								break
+							}
+							if i == l-1 {
+								return jr.PathError(".Labels", errors.New("map too large"))
+							}
 						}
-						return fmt.Errorf("t.Beep: %w", err)
 					}
-					t.Beep = string(sval)
 				}
+
+				// t.Link (cid.Cid) (struct)
+			case "Link":
+				{
+
+					c, err := jr.ReadCid()
+					if err != nil {
+						return jr.PathError(".Link", err)
+					}
+					t.Link = c
+
+				}
+
+				// t.Ratio (float32) (float32)
+			case "Ratio":
+				{
+
+					nval, err := jr.ReadNumberAsFloat32()
+					if err != nil {
+						return jr.PathError(".Ratio", err)
+					}
+					t.Ratio = float32(nval)
+
+				}
+
+				// t.Window (testing.Window) (struct)
+			case "Window":
+
+				if err := t.Window.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Window", err)
+				}
+
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("TestEmpty: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TestEmpty: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("TestEmpty: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *TestConstField) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *OmitEmptyKinds) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Link.Defined() {
+		cb(t.Link)
+	}
+
+	if err := jsg.ScanLinks(&t.Window, cb); err != nil {
+		return fmt.Errorf("t.Window: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Window) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2363,19 +11288,21 @@ func (t *TestConstField) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Cats (string) (string)
-	if len("Cats") > 8192 {
-		return fmt.Errorf("String in field \"Cats\" was too long")
+	// t.End (uint64) (uint64)
+	if len("End") > 8192 {
+		return fmt.Errorf("String in field \"End\" was too long")
 	}
-	if err := jw.WriteString(string("Cats")); err != nil {
-		return fmt.Errorf("\"Cats\": %w", err)
+	if err := jw.WriteString(string("End")); err != nil {
+		return fmt.Errorf("\"End\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if err := jw.WriteString("dogsdrool"); err != nil {
-		return err
+
+	if err := jw.WriteUint64(uint64(t.End)); err != nil {
+		return fmt.Errorf("t.End: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -2383,19 +11310,19 @@ func (t *TestConstField) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Thing (int64) (int64)
-	if len("Thing") > 8192 {
-		return fmt.Errorf("String in field \"Thing\" was too long")
+	// t.Start (uint64) (uint64)
+	if len("Start") > 8192 {
+		return fmt.Errorf("String in field \"Start\" was too long")
 	}
-	if err := jw.WriteString(string("Thing")); err != nil {
-		return fmt.Errorf("\"Thing\": %w", err)
+	if err := jw.WriteString(string("Start")); err != nil {
+		return fmt.Errorf("\"Start\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
 
-	if err := jw.WriteInt64(int64(t.Thing)); err != nil {
-		return fmt.Errorf("t.Thing: %w", err)
+	if err := jw.WriteUint64(uint64(t.Start)); err != nil {
+		return fmt.Errorf("t.Start: %w", err)
 	}
 
 	written++
@@ -2404,88 +11331,101 @@ func (t *TestConstField) MarshalDagJSON(w io.Writer) error {
 	}
 	return nil
 }
-func (t *TestConstField) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = TestConstField{}
+func (t *Window) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = Window{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("TestConstField: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("TestConstField: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("TestConstField: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("TestConstField: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("TestConstField: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("TestConstField: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Cats (string) (string)
-			case "Cats":
+			// t.End (uint64) (uint64)
+			case "End":
 				{
-					sval, err := jr.ReadString(8192)
+
+					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Cats: string too long")
-						}
-						return fmt.Errorf("t.Cats: %w", err)
+						return jr.PathError(".End", err)
 					}
-					t.Cats = string(sval)
+
+					t.End = uint64(nval)
+
 				}
 
-				// t.Thing (int64) (int64)
-			case "Thing":
+				// t.Start (uint64) (uint64)
+			case "Start":
 				{
 
-					nval, err := jr.ReadNumberAsInt64()
+					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						return fmt.Errorf("t.Thing: %w", err)
+						return jr.PathError(".Start", err)
 					}
-					t.Thing = int64(nval)
+
+					t.Start = uint64(nval)
 
 				}
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("TestConstField: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TestConstField: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("TestConstField: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *Window) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *Envelope) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2495,21 +11435,21 @@ func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Zp (string) (string)
-	if len("ap") > 8192 {
-		return fmt.Errorf("String in field \"ap\" was too long")
+	// t.Body (string) (string)
+	if len("Body") > 8192 {
+		return fmt.Errorf("String in field \"Body\" was too long")
 	}
-	if err := jw.WriteString(string("ap")); err != nil {
-		return fmt.Errorf("\"ap\": %w", err)
+	if err := jw.WriteString(string("Body")); err != nil {
+		return fmt.Errorf("\"Body\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Zp) > 8192 {
-		return fmt.Errorf("String in field t.Zp was too long")
+	if len(t.Body) > 8192 {
+		return fmt.Errorf("String in field t.Body was too long")
 	}
-	if err := jw.WriteString(string(t.Zp)); err != nil {
-		return fmt.Errorf("t.Zp: %w", err)
+	if err := jw.WriteString(string(t.Body)); err != nil {
+		return fmt.Errorf("t.Body: %w", err)
 	}
 	written++
 	if written > 0 {
@@ -2518,19 +11458,89 @@ func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Foo (int64) (int64)
-	if len("foo") > 8192 {
-		return fmt.Errorf("String in field \"foo\" was too long")
+	// t.Hops (uint8) (uint8)
+	if len("Hops") > 8192 {
+		return fmt.Errorf("String in field \"Hops\" was too long")
 	}
-	if err := jw.WriteString(string("foo")); err != nil {
-		return fmt.Errorf("\"foo\": %w", err)
+	if err := jw.WriteString(string("Hops")); err != nil {
+		return fmt.Errorf("\"Hops\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
 
-	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
-		return fmt.Errorf("t.Foo: %w", err)
+	if err := jw.WriteUint64(uint64(t.Hops)); err != nil {
+		return fmt.Errorf("t.Hops: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Extra.Signature ([]uint8) (slice)
+	if len("Signature") > 8192 {
+		return fmt.Errorf("String in field \"Signature\" was too long")
+	}
+	if err := jw.WriteString(string("Signature")); err != nil {
+		return fmt.Errorf("\"Signature\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Extra.Signature) > 2097152 {
+		return fmt.Errorf("Byte array in field t.Extra.Signature was too long")
+	}
+
+	if err := jw.WriteBytes(t.Extra.Signature); err != nil {
+		return fmt.Errorf("t.Extra.Signature: %w", err)
+	}
+
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.trace.TraceID (string) (string)
+	if len("TraceID") > 8192 {
+		return fmt.Errorf("String in field \"TraceID\" was too long")
+	}
+	if err := jw.WriteString(string("TraceID")); err != nil {
+		return fmt.Errorf("\"TraceID\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.trace.TraceID) > 8192 {
+		return fmt.Errorf("String in field t.trace.TraceID was too long")
+	}
+	if err := jw.WriteString(string(t.trace.TraceID)); err != nil {
+		return fmt.Errorf("t.trace.TraceID: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Header.Version (uint64) (uint64)
+	if len("Version") > 8192 {
+		return fmt.Errorf("String in field \"Version\" was too long")
+	}
+	if err := jw.WriteString(string("Version")); err != nil {
+		return fmt.Errorf("\"Version\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+
+	if err := jw.WriteUint64(uint64(t.Header.Version)); err != nil {
+		return fmt.Errorf("t.Header.Version: %w", err)
 	}
 
 	written++
@@ -2540,21 +11550,21 @@ func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Bar (string) (string)
-	if len("beep") > 8192 {
-		return fmt.Errorf("String in field \"beep\" was too long")
+	// t.Header.Sender (string) (string)
+	if len("from") > 8192 {
+		return fmt.Errorf("String in field \"from\" was too long")
 	}
-	if err := jw.WriteString(string("beep")); err != nil {
-		return fmt.Errorf("\"beep\": %w", err)
+	if err := jw.WriteString(string("from")); err != nil {
+		return fmt.Errorf("\"from\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Bar) > 8192 {
-		return fmt.Errorf("String in field t.Bar was too long")
+	if len(t.Header.Sender) > 8192 {
+		return fmt.Errorf("String in field t.Header.Sender was too long")
 	}
-	if err := jw.WriteString(string(t.Bar)); err != nil {
-		return fmt.Errorf("t.Bar: %w", err)
+	if err := jw.WriteString(string(t.Header.Sender)); err != nil {
+		return fmt.Errorf("t.Header.Sender: %w", err)
 	}
 	written++
 	if written > 0 {
@@ -2563,134 +11573,192 @@ func (t *TestCanonicalFieldOrder) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Drond (int64) (int64)
-	if len("Drond") > 8192 {
-		return fmt.Errorf("String in field \"Drond\" was too long")
+	// t.Stamp (testing.Stamp) (struct)
+	if len("stamp") > 8192 {
+		return fmt.Errorf("String in field \"stamp\" was too long")
 	}
-	if err := jw.WriteString(string("Drond")); err != nil {
-		return fmt.Errorf("\"Drond\": %w", err)
+	if err := jw.WriteString(string("stamp")); err != nil {
+		return fmt.Errorf("\"stamp\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-
-	if err := jw.WriteInt64(int64(t.Drond)); err != nil {
-		return fmt.Errorf("t.Drond: %w", err)
+	if err := t.Stamp.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Stamp: %w", err)
 	}
-
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
 	return nil
 }
-func (t *TestCanonicalFieldOrder) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = TestCanonicalFieldOrder{}
+func (t *Envelope) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = Envelope{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("TestCanonicalFieldOrder: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Zp (string) (string)
-			case "ap":
+			// t.Body (string) (string)
+			case "Body":
 				{
 					sval, err := jr.ReadString(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Zp: string too long")
+							return jr.PathError(".Body", errors.New("string too long"))
 						}
-						return fmt.Errorf("t.Zp: %w", err)
+						return jr.PathError(".Body", err)
 					}
-					t.Zp = string(sval)
+					t.Body = string(sval)
 				}
 
-				// t.Foo (int64) (int64)
-			case "foo":
+				// t.Hops (uint8) (uint8)
+			case "Hops":
 				{
 
-					nval, err := jr.ReadNumberAsInt64()
+					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						return fmt.Errorf("t.Foo: %w", err)
+						return jr.PathError(".Hops", err)
 					}
-					t.Foo = int64(nval)
+
+					if nval > math.MaxUint8 {
+						return jr.PathError(".Hops", fmt.Errorf("value %d out of range for uint8", nval))
+					}
+
+					t.Hops = uint8(nval)
 
 				}
 
-				// t.Bar (string) (string)
-			case "beep":
+				// t.Extra.Signature ([]uint8) (slice)
+			case "Signature":
+
+				{
+					bval, err := jr.ReadBytes(2097152)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Extra.Signature", errors.New("byte array too large"))
+						}
+						return jr.PathError(".Extra.Signature", err)
+					}
+					if len(bval) > 0 {
+						t.Extra.Signature = []uint8(bval)
+					}
+				}
+
+				// t.trace.TraceID (string) (string)
+			case "TraceID":
 				{
 					sval, err := jr.ReadString(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Bar: string too long")
+							return jr.PathError(".trace.TraceID", errors.New("string too long"))
 						}
-						return fmt.Errorf("t.Bar: %w", err)
+						return jr.PathError(".trace.TraceID", err)
 					}
-					t.Bar = string(sval)
+					t.trace.TraceID = string(sval)
 				}
 
-				// t.Drond (int64) (int64)
-			case "Drond":
+				// t.Header.Version (uint64) (uint64)
+			case "Version":
 				{
 
-					nval, err := jr.ReadNumberAsInt64()
+					nval, err := jr.ReadNumberAsUint64()
 					if err != nil {
-						return fmt.Errorf("t.Drond: %w", err)
+						return jr.PathError(".Header.Version", err)
 					}
-					t.Drond = int64(nval)
 
+					t.Header.Version = uint64(nval)
+
+				}
+
+				// t.Header.Sender (string) (string)
+			case "from":
+				{
+					sval, err := jr.ReadString(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Header.Sender", errors.New("string too long"))
+						}
+						return jr.PathError(".Header.Sender", err)
+					}
+					t.Header.Sender = string(sval)
+				}
+
+				// t.Stamp (testing.Stamp) (struct)
+			case "stamp":
+
+				if err := t.Stamp.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Stamp", err)
 				}
+
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("TestCanonicalFieldOrder: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TestCanonicalFieldOrder: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("TestCanonicalFieldOrder: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *MapStringString) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *Envelope) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.Stamp, cb); err != nil {
+		return fmt.Errorf("t.Stamp: %w", err)
+	}
+
+	return nil
+}
+
+func (t *Stamp) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2699,57 +11767,19 @@ func (t *MapStringString) MarshalDagJSON(w io.Writer) error {
 		return err
 	}
 
-	// t.Snorkleblump (map[string]string) (map)
-	if len("Snorkleblump") > 8192 {
-		return fmt.Errorf("String in field \"Snorkleblump\" was too long")
+	// t.At (int64) (int64)
+	if len("At") > 8192 {
+		return fmt.Errorf("String in field \"At\" was too long")
 	}
-	if err := jw.WriteString(string("Snorkleblump")); err != nil {
-		return fmt.Errorf("\"Snorkleblump\": %w", err)
+	if err := jw.WriteString(string("At")); err != nil {
+		return fmt.Errorf("\"At\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	{
-		if len(t.Snorkleblump) > 4096 {
-			return fmt.Errorf("cannot marshal t.Snorkleblump map too large")
-		}
-
-		if err := jw.WriteObjectOpen(); err != nil {
-			return fmt.Errorf("t.Snorkleblump: %w", err)
-		}
-
-		keys := make([]string, 0, len(t.Snorkleblump))
-		for k := range t.Snorkleblump {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for i, k := range keys {
-			if i > 0 {
-				if err := jw.WriteComma(); err != nil {
-					return fmt.Errorf("t.Snorkleblump: %w", err)
-				}
-			}
-			v := t.Snorkleblump[k]
-			if len(k) > 8192 {
-				return fmt.Errorf("String in field k was too long")
-			}
-			if err := jw.WriteString(string(k)); err != nil {
-				return fmt.Errorf("k: %w", err)
-			}
-			if err := jw.WriteObjectColon(); err != nil {
-				return fmt.Errorf("t.Snorkleblump: %w", err)
-			}
 
-			if len(v) > 8192 {
-				return fmt.Errorf("String in field v was too long")
-			}
-			if err := jw.WriteString(string(v)); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
-		}
-		if err := jw.WriteObjectClose(); err != nil {
-			return fmt.Errorf("t.Snorkleblump: %w", err)
-		}
+	if err := jw.WriteInt64(int64(t.At)); err != nil {
+		return fmt.Errorf("t.At: %w", err)
 	}
 
 	if err := jw.WriteObjectClose(); err != nil {
@@ -2757,117 +11787,88 @@ func (t *MapStringString) MarshalDagJSON(w io.Writer) error {
 	}
 	return nil
 }
-func (t *MapStringString) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = MapStringString{}
+func (t *Stamp) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = Stamp{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("MapStringString: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("MapStringString: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("MapStringString: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("MapStringString: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("MapStringString: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("MapStringString: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Snorkleblump (map[string]string) (map)
-			case "Snorkleblump":
-				if err := jr.ReadObjectOpen(); err != nil {
-					return fmt.Errorf("t.Snorkleblump: %w", err)
-				}
-
-				t.Snorkleblump = map[string]string{}
+			// t.At (int64) (int64)
+			case "At":
+				{
 
-				close, err := jr.PeekObjectClose()
-				if err != nil {
-					return fmt.Errorf("t.Snorkleblump: %w", err)
-				}
-				if close {
-					if err := jr.ReadObjectClose(); err != nil {
-						return fmt.Errorf("t.Snorkleblump: %w", err)
-					}
-				} else {
-					for i, l := 0, 8192; i < l; i++ {
-						var k string
-						{
-							sval, err := jr.ReadString(8192)
-							if err != nil {
-								if errors.Is(err, jsg.ErrLimitExceeded) {
-									return fmt.Errorf("k: string too long")
-								}
-								return fmt.Errorf("k: %w", err)
-							}
-							k = string(sval)
-						}
-						if err := jr.ReadObjectColon(); err != nil {
-							return fmt.Errorf("t.Snorkleblump: %w", err)
-						}
-						var v string
-						{
-							sval, err := jr.ReadString(8192)
-							if err != nil {
-								if errors.Is(err, jsg.ErrLimitExceeded) {
-									return fmt.Errorf("v: string too long")
-								}
-								return fmt.Errorf("v: %w", err)
-							}
-							v = string(sval)
-						}
-						t.Snorkleblump[k] = v
-						close, err := jr.ReadObjectCloseOrComma()
-						if err != nil {
-							return fmt.Errorf("t.Snorkleblump: %w", err)
-						}
-						if close {
-							break
-						}
+					nval, err := jr.ReadNumberAsInt64()
+					if err != nil {
+						return jr.PathError(".At", err)
 					}
+
+					t.At = int64(nval)
+
 				}
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("MapStringString: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("MapStringString: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("MapStringString: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *TestSliceNilPreserve) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *Stamp) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func marshalDagJSON_Page_SimpleTypeOne(t *Page[SimpleTypeOne], w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -2877,22 +11878,37 @@ func (t *TestSliceNilPreserve) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Cat (string) (string)
-	if len("Cat") > 8192 {
-		return fmt.Errorf("String in field \"Cat\" was too long")
+	// t.Items ([]testing.SimpleTypeOne) (slice)
+	if len("Items") > 8192 {
+		return fmt.Errorf("String in field \"Items\" was too long")
 	}
-	if err := jw.WriteString(string("Cat")); err != nil {
-		return fmt.Errorf("\"Cat\": %w", err)
+	if err := jw.WriteString(string("Items")); err != nil {
+		return fmt.Errorf("\"Items\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Cat) > 8192 {
-		return fmt.Errorf("String in field t.Cat was too long")
+	if len(t.Items) > 8192 {
+		return fmt.Errorf("Slice value in field t.Items was too long")
 	}
-	if err := jw.WriteString(string(t.Cat)); err != nil {
-		return fmt.Errorf("t.Cat: %w", err)
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Items: %w", err)
+	}
+	for i, v := range t.Items {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Items: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Items: %w", err)
 	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -2900,132 +11916,207 @@ func (t *TestSliceNilPreserve) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Not ([]uint64) (slice)
-	if len("Not") > 8192 {
-		return fmt.Errorf("String in field \"Not\" was too long")
+	// t.Next (string) (string)
+	if len("Next") > 8192 {
+		return fmt.Errorf("String in field \"Next\" was too long")
 	}
-	if err := jw.WriteString(string("Not")); err != nil {
-		return fmt.Errorf("\"Not\": %w", err)
+	if err := jw.WriteString(string("Next")); err != nil {
+		return fmt.Errorf("\"Next\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Not) > 8192 {
-		return fmt.Errorf("Slice value in field t.Not was too long")
-	}
-
-	if t.Not == nil {
+	if t.Next == nil {
 		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.Not: %w", err)
+			return fmt.Errorf("t.Next: %w", err)
 		}
 	} else {
+		if len(*t.Next) > 8192 {
+			return fmt.Errorf("String in field t.Next was too long")
+		}
+		if err := jw.WriteString(string(*t.Next)); err != nil {
+			return fmt.Errorf("t.Next: %w", err)
+		}
+	}
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func unmarshalDagJSON_Page_SimpleTypeOne(t *Page[SimpleTypeOne], r io.Reader) (err error) {
+	*t = Page[SimpleTypeOne]{}
 
-		if err := jw.WriteArrayOpen(); err != nil {
-			return fmt.Errorf("t.Not: %w", err)
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
 		}
-		for i, v := range t.Not {
-			if i > 0 {
-				if err := jw.WriteComma(); err != nil {
-					return fmt.Errorf("t.Not: %w", err)
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Items ([]testing.SimpleTypeOne) (slice)
+			case "Items":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Items", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".Items", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Items", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]SimpleTypeOne, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Items"+jsg.IndexPath(i), err)
+							}
+
+							t.Items = append(t.Items, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Items", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Items", errors.New("slice too large"))
+							}
+						}
+					}
+
 				}
-			}
 
-			if err := jw.WriteUint64(uint64(v)); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
+				// t.Next (string) (string)
+			case "Next":
+				{
+					sval, err := jr.ReadStringOrNull(8192)
+					if err != nil {
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Next", errors.New("string too long"))
+						}
+						return jr.PathError(".Next", err)
+					}
+					if sval != nil {
+						t.Next = (*string)(sval)
+					}
+				}
+			default:
 
-		}
-		if err := jw.WriteArrayClose(); err != nil {
-			return fmt.Errorf("t.Not: %w", err)
-		}
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
 
-	}
+			}
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
 		}
 	}
 
-	// t.Beep (int64) (int64)
-	if len("Beep") > 8192 {
-		return fmt.Errorf("String in field \"Beep\" was too long")
-	}
-	if err := jw.WriteString(string("Beep")); err != nil {
-		return fmt.Errorf("\"Beep\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-
-	if err := jw.WriteInt64(int64(t.Beep)); err != nil {
-		return fmt.Errorf("t.Beep: %w", err)
+	return nil
+}
+func scanDagJSONLinks_Page_SimpleTypeOne(t *Page[SimpleTypeOne], cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
 	}
+	for _, v := range t.Items {
 
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
 		}
-	}
 
-	// t.Other ([]uint8) (slice)
-	if len("Other") > 8192 {
-		return fmt.Errorf("String in field \"Other\" was too long")
-	}
-	if err := jw.WriteString(string("Other")); err != nil {
-		return fmt.Errorf("\"Other\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-	if len(t.Other) > 2097152 {
-		return fmt.Errorf("Byte array in field t.Other was too long")
 	}
+	return nil
+}
 
-	if err := jw.WriteBytes(t.Other); err != nil {
-		return fmt.Errorf("t.Other: %w", err)
+func marshalDagJSON_Page_int64(t *Page[int64], w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
 	}
-
-	written++
-	if written > 0 {
-		if err := jw.WriteComma(); err != nil {
-			return err
-		}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
 	}
+	written := 0
 
-	// t.Stuff ([]uint64) (slice)
-	if len("Stuff") > 8192 {
-		return fmt.Errorf("String in field \"Stuff\" was too long")
+	// t.Items ([]int64) (slice)
+	if len("Items") > 8192 {
+		return fmt.Errorf("String in field \"Items\" was too long")
 	}
-	if err := jw.WriteString(string("Stuff")); err != nil {
-		return fmt.Errorf("\"Stuff\": %w", err)
+	if err := jw.WriteString(string("Items")); err != nil {
+		return fmt.Errorf("\"Items\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Stuff) > 8192 {
-		return fmt.Errorf("Slice value in field t.Stuff was too long")
+	if len(t.Items) > 8192 {
+		return fmt.Errorf("Slice value in field t.Items was too long")
 	}
 
 	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.Stuff: %w", err)
+		return fmt.Errorf("t.Items: %w", err)
 	}
-	for i, v := range t.Stuff {
+	for i, v := range t.Items {
 		if i > 0 {
 			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.Stuff: %w", err)
+				return fmt.Errorf("t.Items: %w", err)
 			}
 		}
 
-		if err := jw.WriteUint64(uint64(v)); err != nil {
+		if err := jw.WriteInt64(int64(v)); err != nil {
 			return fmt.Errorf("v: %w", err)
 		}
 
 	}
 	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.Stuff: %w", err)
+		return fmt.Errorf("t.Items: %w", err)
 	}
 
 	written++
@@ -3035,294 +12126,190 @@ func (t *TestSliceNilPreserve) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.NotOther ([]uint8) (slice)
-	if len("NotOther") > 8192 {
-		return fmt.Errorf("String in field \"NotOther\" was too long")
+	// t.Next (string) (string)
+	if len("Next") > 8192 {
+		return fmt.Errorf("String in field \"Next\" was too long")
 	}
-	if err := jw.WriteString(string("NotOther")); err != nil {
-		return fmt.Errorf("\"NotOther\": %w", err)
+	if err := jw.WriteString(string("Next")); err != nil {
+		return fmt.Errorf("\"Next\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.NotOther) > 2097152 {
-		return fmt.Errorf("Byte array in field t.NotOther was too long")
-	}
-
-	if t.NotOther == nil {
+	if t.Next == nil {
 		if err := jw.WriteNull(); err != nil {
-			return fmt.Errorf("t.NotOther: %w", err)
+			return fmt.Errorf("t.Next: %w", err)
 		}
 	} else {
-
-		if err := jw.WriteBytes(t.NotOther); err != nil {
-			return fmt.Errorf("t.NotOther: %w", err)
+		if len(*t.Next) > 8192 {
+			return fmt.Errorf("String in field t.Next was too long")
+		}
+		if err := jw.WriteString(string(*t.Next)); err != nil {
+			return fmt.Errorf("t.Next: %w", err)
 		}
-
 	}
-
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
 	return nil
 }
-func (t *TestSliceNilPreserve) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = TestSliceNilPreserve{}
+func unmarshalDagJSON_Page_int64(t *Page[int64], r io.Reader) (err error) {
+	*t = Page[int64]{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("TestSliceNilPreserve: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("TestSliceNilPreserve: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("TestSliceNilPreserve: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("TestSliceNilPreserve: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("TestSliceNilPreserve: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("TestSliceNilPreserve: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Cat (string) (string)
-			case "Cat":
-				{
-					sval, err := jr.ReadString(8192)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Cat: string too long")
-						}
-						return fmt.Errorf("t.Cat: %w", err)
-					}
-					t.Cat = string(sval)
-				}
-
-				// t.Not ([]uint64) (slice)
-			case "Not":
-				{
-
-					open, err := jr.ReadArrayOpenOrNull()
-					if err != nil {
-						return fmt.Errorf("t.Not: %w", err)
-					}
-					if open {
-
-						close, err := jr.PeekArrayClose()
-						if err != nil {
-							return fmt.Errorf("t.Not: %w", err)
-						}
-						if close {
-							if err := jr.ReadArrayClose(); err != nil {
-								return fmt.Errorf("t.Not: %w", err)
-							}
-
-							t.Not = []uint64{}
-
-						} else {
-							for i := 0; i < 8192; i++ {
-								item := make([]uint64, 1)
-								{
-
-									nval, err := jr.ReadNumberAsUint64()
-									if err != nil {
-										return fmt.Errorf("item[0]: %w", err)
-									}
-									item[0] = uint64(nval)
-
-								}
-								t.Not = append(t.Not, item[0])
-
-								close, err := jr.ReadArrayCloseOrComma()
-								if err != nil {
-									return fmt.Errorf("t.Not: %w", err)
-								}
-								if close {
-									break
-								}
-								if i == 8192-1 {
-									return fmt.Errorf("t.Not: slice too large")
-								}
-							}
-						}
-
-					}
-
-				}
-
-				// t.Beep (int64) (int64)
-			case "Beep":
-				{
-
-					nval, err := jr.ReadNumberAsInt64()
-					if err != nil {
-						return fmt.Errorf("t.Beep: %w", err)
-					}
-					t.Beep = int64(nval)
-
-				}
-
-				// t.Other ([]uint8) (slice)
-			case "Other":
-
-				{
-					bval, err := jr.ReadBytes(2097152)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Other: byte array too large")
-						}
-						return fmt.Errorf("t.Other: %w", err)
-					}
-					if len(bval) > 0 {
-						t.Other = []uint8(bval)
-					}
-				}
-
-				// t.Stuff ([]uint64) (slice)
-			case "Stuff":
+			// t.Items ([]int64) (slice)
+			case "Items":
 				{
 
 					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.Stuff: %w", err)
+						return jr.PathError(".Items", err)
 					}
 
 					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.Stuff: %w", err)
+						return jr.PathError(".Items", err)
 					}
 					if close {
 						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.Stuff: %w", err)
+							return jr.PathError(".Items", err)
 						}
 
 					} else {
 						for i := 0; i < 8192; i++ {
-							item := make([]uint64, 1)
+							item := make([]int64, 1)
 							{
 
-								nval, err := jr.ReadNumberAsUint64()
+								nval, err := jr.ReadNumberAsInt64()
 								if err != nil {
-									return fmt.Errorf("item[0]: %w", err)
+									return jr.PathError(".Items"+jsg.IndexPath(i), err)
 								}
-								item[0] = uint64(nval)
+
+								item[0] = int64(nval)
 
 							}
-							t.Stuff = append(t.Stuff, item[0])
+							t.Items = append(t.Items, item[0])
 
 							close, err := jr.ReadArrayCloseOrComma()
 							if err != nil {
-								return fmt.Errorf("t.Stuff: %w", err)
+								return jr.PathError(".Items", err)
 							}
 							if close {
 								break
 							}
 							if i == 8192-1 {
-								return fmt.Errorf("t.Stuff: slice too large")
+								return jr.PathError(".Items", errors.New("slice too large"))
 							}
 						}
 					}
 
 				}
 
-				// t.NotOther ([]uint8) (slice)
-			case "NotOther":
-
+				// t.Next (string) (string)
+			case "Next":
 				{
-					bval, err := jr.ReadBytesOrNull(2097152)
+					sval, err := jr.ReadStringOrNull(8192)
 					if err != nil {
 						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.NotOther: byte array too large")
+							return jr.PathError(".Next", errors.New("string too long"))
 						}
-						return fmt.Errorf("t.NotOther: %w", err)
+						return jr.PathError(".Next", err)
 					}
-					if bval != nil {
-						t.NotOther = []uint8(*bval)
+					if sval != nil {
+						t.Next = (*string)(sval)
 					}
 				}
-
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("TestSliceNilPreserve: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("TestSliceNilPreserve: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("TestSliceNilPreserve: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *StringPtrSlices) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func scanDagJSONLinks_Page_int64(t *Page[int64], cb func(cid.Cid)) error {
 	if t == nil {
-		err := jw.WriteNull()
-		return err
-	}
-	if err := jw.WriteObjectOpen(); err != nil {
-		return err
-	}
-	written := 0
-
-	// t.Strings ([]string) (slice)
-	if len("Strings") > 8192 {
-		return fmt.Errorf("String in field \"Strings\" was too long")
-	}
-	if err := jw.WriteString(string("Strings")); err != nil {
-		return fmt.Errorf("\"Strings\": %w", err)
-	}
-	if err := jw.WriteObjectColon(); err != nil {
-		return err
-	}
-	if len(t.Strings) > 8192 {
-		return fmt.Errorf("Slice value in field t.Strings was too long")
+		return nil
 	}
+	return nil
+}
 
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.Strings: %w", err)
-	}
-	for i, v := range t.Strings {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.Strings: %w", err)
-			}
-		}
-		if len(v) > 8192 {
-			return fmt.Errorf("String in field v was too long")
-		}
-		if err := jw.WriteString(string(v)); err != nil {
-			return fmt.Errorf("v: %w", err)
-		}
+func marshalDagJSON_Pair_string_ptr_big_Int(t *Pair[string, *big.Int], w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
 	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.Strings: %w", err)
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
 	}
+	written := 0
 
+	// t.Key (string) (string)
+	if len("Key") > 8192 {
+		return fmt.Errorf("String in field \"Key\" was too long")
+	}
+	if err := jw.WriteString(string("Key")); err != nil {
+		return fmt.Errorf("\"Key\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Key) > 8192 {
+		return fmt.Errorf("String in field t.Key was too long")
+	}
+	if err := jw.WriteString(string(t.Key)); err != nil {
+		return fmt.Errorf("t.Key: %w", err)
+	}
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -3330,205 +12317,126 @@ func (t *StringPtrSlices) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.StringPtrs ([]*string) (slice)
-	if len("StringPtrs") > 8192 {
-		return fmt.Errorf("String in field \"StringPtrs\" was too long")
+	// t.Value (big.Int) (struct)
+	if len("Value") > 8192 {
+		return fmt.Errorf("String in field \"Value\" was too long")
 	}
-	if err := jw.WriteString(string("StringPtrs")); err != nil {
-		return fmt.Errorf("\"StringPtrs\": %w", err)
+	if err := jw.WriteString(string("Value")); err != nil {
+		return fmt.Errorf("\"Value\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.StringPtrs) > 8192 {
-		return fmt.Errorf("Slice value in field t.StringPtrs was too long")
-	}
-
-	if err := jw.WriteArrayOpen(); err != nil {
-		return fmt.Errorf("t.StringPtrs: %w", err)
-	}
-	for i, v := range t.StringPtrs {
-		if i > 0 {
-			if err := jw.WriteComma(); err != nil {
-				return fmt.Errorf("t.StringPtrs: %w", err)
-			}
+	if t.Value == nil {
+		if err := jw.WriteUint8(0); err != nil {
+			return fmt.Errorf("t.Value: %w", err)
 		}
-		if v == nil {
-			if err := jw.WriteNull(); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
-		} else {
-			if len(*v) > 8192 {
-				return fmt.Errorf("String in field v was too long")
-			}
-			if err := jw.WriteString(string(*v)); err != nil {
-				return fmt.Errorf("v: %w", err)
-			}
+	} else {
+		if err := jw.WriteBigInt(t.Value); err != nil {
+			return fmt.Errorf("t.Value: %w", err)
 		}
 	}
-	if err := jw.WriteArrayClose(); err != nil {
-		return fmt.Errorf("t.StringPtrs: %w", err)
-	}
-
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
 		return err
 	}
 	return nil
 }
-func (t *StringPtrSlices) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = StringPtrSlices{}
+func unmarshalDagJSON_Pair_string_ptr_big_Int(t *Pair[string, *big.Int], r io.Reader) (err error) {
+	*t = Pair[string, *big.Int]{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("StringPtrSlices: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("StringPtrSlices: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("StringPtrSlices: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("StringPtrSlices: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("StringPtrSlices: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("StringPtrSlices: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Strings ([]string) (slice)
-			case "Strings":
+			// t.Key (string) (string)
+			case "Key":
 				{
-
-					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.Strings: %w", err)
-					}
-
-					close, err := jr.PeekArrayClose()
+					sval, err := jr.ReadString(8192)
 					if err != nil {
-						return fmt.Errorf("t.Strings: %w", err)
-					}
-					if close {
-						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.Strings: %w", err)
-						}
-
-					} else {
-						for i := 0; i < 8192; i++ {
-							item := make([]string, 1)
-							{
-								sval, err := jr.ReadString(8192)
-								if err != nil {
-									if errors.Is(err, jsg.ErrLimitExceeded) {
-										return fmt.Errorf("item[0]: string too long")
-									}
-									return fmt.Errorf("item[0]: %w", err)
-								}
-								item[0] = string(sval)
-							}
-							t.Strings = append(t.Strings, item[0])
-
-							close, err := jr.ReadArrayCloseOrComma()
-							if err != nil {
-								return fmt.Errorf("t.Strings: %w", err)
-							}
-							if close {
-								break
-							}
-							if i == 8192-1 {
-								return fmt.Errorf("t.Strings: slice too large")
-							}
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Key", errors.New("string too long"))
 						}
+						return jr.PathError(".Key", err)
 					}
-
+					t.Key = string(sval)
 				}
 
-				// t.StringPtrs ([]*string) (slice)
-			case "StringPtrs":
+				// t.Value (big.Int) (struct)
+			case "Value":
 				{
-
-					if err := jr.ReadArrayOpen(); err != nil {
-						return fmt.Errorf("t.StringPtrs: %w", err)
-					}
-
-					close, err := jr.PeekArrayClose()
+					nval, err := jr.ReadNumberAsBigInt(8192)
 					if err != nil {
-						return fmt.Errorf("t.StringPtrs: %w", err)
-					}
-					if close {
-						if err := jr.ReadArrayClose(); err != nil {
-							return fmt.Errorf("t.StringPtrs: %w", err)
-						}
-
-					} else {
-						for i := 0; i < 8192; i++ {
-							item := make([]*string, 1)
-							{
-								sval, err := jr.ReadStringOrNull(8192)
-								if err != nil {
-									if errors.Is(err, jsg.ErrLimitExceeded) {
-										return fmt.Errorf("item[0]: string too long")
-									}
-									return fmt.Errorf("item[0]: %w", err)
-								}
-								if sval != nil {
-									item[0] = (*string)(sval)
-								}
-							}
-							t.StringPtrs = append(t.StringPtrs, item[0])
-
-							close, err := jr.ReadArrayCloseOrComma()
-							if err != nil {
-								return fmt.Errorf("t.StringPtrs: %w", err)
-							}
-							if close {
-								break
-							}
-							if i == 8192-1 {
-								return fmt.Errorf("t.StringPtrs: slice too large")
-							}
+						if errors.Is(err, jsg.ErrLimitExceeded) {
+							return jr.PathError(".Value", errors.New("number too large"))
 						}
+						return jr.PathError(".Value", err)
 					}
-
+					t.Value = nval
 				}
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("StringPtrSlices: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("StringPtrSlices: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("StringPtrSlices: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
-func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func scanDagJSONLinks_Pair_string_ptr_big_Int(t *Pair[string, *big.Int], cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *GenericFields) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -3538,21 +12446,19 @@ func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) error {
 	}
 	written := 0
 
-	// t.Foo (int64) (int64)
-	if len("foo") > 8192 {
-		return fmt.Errorf("String in field \"foo\" was too long")
+	// t.Ones (testing.Page[github.com/alanshaw/dag-json-gen/testing.SimpleTypeOne]) (struct)
+	if len("Ones") > 8192 {
+		return fmt.Errorf("String in field \"Ones\" was too long")
 	}
-	if err := jw.WriteString(string("foo")); err != nil {
-		return fmt.Errorf("\"foo\": %w", err)
+	if err := jw.WriteString(string("Ones")); err != nil {
+		return fmt.Errorf("\"Ones\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-
-	if err := jw.WriteInt64(int64(t.Foo)); err != nil {
-		return fmt.Errorf("t.Foo: %w", err)
+	if err := t.Ones.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Ones: %w", err)
 	}
-
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -3560,22 +12466,37 @@ func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.Bar (string) (string)
-	if len("beep") > 8192 {
-		return fmt.Errorf("String in field \"beep\" was too long")
+	// t.Pages ([]testing.Page[int64]) (slice)
+	if len("Pages") > 8192 {
+		return fmt.Errorf("String in field \"Pages\" was too long")
 	}
-	if err := jw.WriteString(string("beep")); err != nil {
-		return fmt.Errorf("\"beep\": %w", err)
+	if err := jw.WriteString(string("Pages")); err != nil {
+		return fmt.Errorf("\"Pages\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.Bar) > 8192 {
-		return fmt.Errorf("String in field t.Bar was too long")
+	if len(t.Pages) > 8192 {
+		return fmt.Errorf("Slice value in field t.Pages was too long")
 	}
-	if err := jw.WriteString(string(t.Bar)); err != nil {
-		return fmt.Errorf("t.Bar: %w", err)
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Pages: %w", err)
+	}
+	for i, v := range t.Pages {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Pages: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
 	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Pages: %w", err)
+	}
+
 	written++
 	if written > 0 {
 		if err := jw.WriteComma(); err != nil {
@@ -3583,21 +12504,18 @@ func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) error {
 		}
 	}
 
-	// t.LongerNamedField (string) (string)
-	if len("LongerNamedField") > 8192 {
-		return fmt.Errorf("String in field \"LongerNamedField\" was too long")
+	// t.Sum (testing.Pair[string,*math/big.Int]) (struct)
+	if len("Sum") > 8192 {
+		return fmt.Errorf("String in field \"Sum\" was too long")
 	}
-	if err := jw.WriteString(string("LongerNamedField")); err != nil {
-		return fmt.Errorf("\"LongerNamedField\": %w", err)
+	if err := jw.WriteString(string("Sum")); err != nil {
+		return fmt.Errorf("\"Sum\": %w", err)
 	}
 	if err := jw.WriteObjectColon(); err != nil {
 		return err
 	}
-	if len(t.LongerNamedField) > 8192 {
-		return fmt.Errorf("String in field t.LongerNamedField was too long")
-	}
-	if err := jw.WriteString(string(t.LongerNamedField)); err != nil {
-		return fmt.Errorf("t.LongerNamedField: %w", err)
+	if err := t.Sum.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Sum: %w", err)
 	}
 	written++
 	if err := jw.WriteObjectClose(); err != nil {
@@ -3605,96 +12523,211 @@ func (t *FieldNameOverlap) MarshalDagJSON(w io.Writer) error {
 	}
 	return nil
 }
-func (t *FieldNameOverlap) UnmarshalDagJSON(r io.Reader) (err error) {
-	*t = FieldNameOverlap{}
+func (t *GenericFields) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = GenericFields{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadObjectOpen(); err != nil {
-		return fmt.Errorf("FieldNameOverlap: %w", err)
+		return jr.PathError("", err)
 	}
+
 	close, err := jr.PeekObjectClose()
 	if err != nil {
-		return fmt.Errorf("FieldNameOverlap: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadObjectClose(); err != nil {
-			return fmt.Errorf("FieldNameOverlap: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 		for i := uint64(0); i < 8192; i++ {
 			name, err := jr.ReadString(8192)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("FieldNameOverlap: string too large")
+					return jr.PathError("", errors.New("string too large"))
 				}
-				return fmt.Errorf("FieldNameOverlap: %w", err)
+				return jr.PathError("", err)
 			}
 			if err := jr.ReadObjectColon(); err != nil {
-				return fmt.Errorf("FieldNameOverlap: %w", err)
+				return jr.PathError("", err)
 			}
 			switch name {
 
-			// t.Foo (int64) (int64)
-			case "foo":
+			// t.Ones (testing.Page[github.com/alanshaw/dag-json-gen/testing.SimpleTypeOne]) (struct)
+			case "Ones":
+
+				if err := t.Ones.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Ones", err)
+				}
+
+				// t.Pages ([]testing.Page[int64]) (slice)
+			case "Pages":
 				{
 
-					nval, err := jr.ReadNumberAsInt64()
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Pages", err)
+					}
+
+					close, err := jr.PeekArrayClose()
 					if err != nil {
-						return fmt.Errorf("t.Foo: %w", err)
+						return jr.PathError(".Pages", err)
 					}
-					t.Foo = int64(nval)
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Pages", err)
+						}
 
-				}
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]Page[int64], 1)
 
-				// t.Bar (string) (string)
-			case "beep":
-				{
-					sval, err := jr.ReadString(8192)
-					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.Bar: string too long")
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Pages"+jsg.IndexPath(i), err)
+							}
+
+							t.Pages = append(t.Pages, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Pages", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Pages", errors.New("slice too large"))
+							}
 						}
-						return fmt.Errorf("t.Bar: %w", err)
 					}
-					t.Bar = string(sval)
+
 				}
 
-				// t.LongerNamedField (string) (string)
-			case "LongerNamedField":
+				// t.Sum (testing.Pair[string,*math/big.Int]) (struct)
+			case "Sum":
+
 				{
-					sval, err := jr.ReadString(8192)
+					null, err := jr.PeekNull()
 					if err != nil {
-						if errors.Is(err, jsg.ErrLimitExceeded) {
-							return fmt.Errorf("t.LongerNamedField: string too long")
+						return jr.PathError(".Sum", err)
+					}
+					if null {
+						if err := jr.ReadNull(); err != nil {
+							return jr.PathError(".Sum", err)
+						}
+					} else {
+						t.Sum = new(Pair[string, *big.Int])
+						if err := t.Sum.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(".Sum", err)
 						}
-						return fmt.Errorf("t.LongerNamedField: %w", err)
 					}
-					t.LongerNamedField = string(sval)
 				}
+
 			default:
+
 				// Field doesn't exist on this type, so ignore it
 				if err := jr.DiscardType(); err != nil {
-					return fmt.Errorf("FieldNameOverlap: ignoring field %s: %w", name, err)
+					return jr.PathError(jsg.KeyPath(name), err)
 				}
+
 			}
 
 			close, err := jr.ReadObjectCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("FieldNameOverlap: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
 				break
 			}
 			if i == 8192-1 {
-				return fmt.Errorf("FieldNameOverlap: map too large")
+				return jr.PathError("", errors.New("map too large"))
 			}
 		}
 	}
 
 	return nil
 }
+func (t *GenericFields) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.Ones, cb); err != nil {
+		return fmt.Errorf("t.Ones: %w", err)
+	}
+
+	for _, v := range t.Pages {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+
+	if t.Sum != nil {
+		if err := jsg.ScanLinks(t.Sum, cb); err != nil {
+			return fmt.Errorf("t.Sum: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Page[_]) MarshalDagJSON(w io.Writer) error {
+	switch t := any(t).(type) {
+	case *Page[SimpleTypeOne]:
+		return marshalDagJSON_Page_SimpleTypeOne(t, w)
+	case *Page[int64]:
+		return marshalDagJSON_Page_int64(t, w)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
+
+func (t *Page[_]) UnmarshalDagJSON(r io.Reader) error {
+	switch t := any(t).(type) {
+	case *Page[SimpleTypeOne]:
+		return unmarshalDagJSON_Page_SimpleTypeOne(t, r)
+	case *Page[int64]:
+		return unmarshalDagJSON_Page_int64(t, r)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
+
+func (t *Page[_]) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	switch t := any(t).(type) {
+	case *Page[SimpleTypeOne]:
+		return scanDagJSONLinks_Page_SimpleTypeOne(t, cb)
+	case *Page[int64]:
+		return scanDagJSONLinks_Page_int64(t, cb)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
+
+func (t *Pair[_, _]) MarshalDagJSON(w io.Writer) error {
+	switch t := any(t).(type) {
+	case *Pair[string, *big.Int]:
+		return marshalDagJSON_Pair_string_ptr_big_Int(t, w)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
+
+func (t *Pair[_, _]) UnmarshalDagJSON(r io.Reader) error {
+	switch t := any(t).(type) {
+	case *Pair[string, *big.Int]:
+		return unmarshalDagJSON_Pair_string_ptr_big_Int(t, r)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
+
+func (t *Pair[_, _]) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	switch t := any(t).(type) {
+	case *Pair[string, *big.Int]:
+		return scanDagJSONLinks_Pair_string_ptr_big_Int(t, cb)
+	}
+	return fmt.Errorf("no dag-json codec was generated for %T", t)
+}
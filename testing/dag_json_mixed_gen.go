@@ -0,0 +1,447 @@
+// Code generated by github.com/alanshaw/dag-json-gen. DO NOT EDIT.
+
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+	cid "github.com/ipfs/go-cid"
+)
+
+var _ = cid.Undef
+var _ = bytes.NewReader
+var _ = math.E
+var _ = sort.Sort
+var _ = strconv.Itoa
+var _ = errors.Is
+
+func (t *MixedTuple) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("MixedTuple: %w", err)
+	}
+
+	// t.Count (int64) (int64)
+
+	if err := jw.WriteInt64(int64(t.Count)); err != nil {
+		return fmt.Errorf("t.Count: %w", err)
+	}
+
+	if err := jw.WriteComma(); err != nil {
+		return fmt.Errorf("Label: %w", err)
+	}
+
+	// t.Label (string) (string)
+	if len(t.Label) > 8192 {
+		return fmt.Errorf("String in field t.Label was too long")
+	}
+	if err := jw.WriteString(string(t.Label)); err != nil {
+		return fmt.Errorf("t.Label: %w", err)
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("MixedTuple: %w", err)
+	}
+	return nil
+}
+
+func (t *MixedTuple) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MixedTuple{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadArrayOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+	close, err := jr.PeekArrayClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+
+		// t.Count (int64) (int64)
+
+		{
+
+			nval, err := jr.ReadNumberAsInt64()
+			if err != nil {
+				return jr.PathError(".Count", err)
+			}
+
+			t.Count = int64(nval)
+
+		}
+		{
+			close, err := jr.ReadArrayCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				return jr.PathError("", errors.New("json input has too few fields 1 < 2"))
+			}
+		}
+
+		// t.Label (string) (string)
+
+		{
+			sval, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError(".Label", errors.New("string too long"))
+				}
+				return jr.PathError(".Label", err)
+			}
+			t.Label = string(sval)
+		}
+		if err := jr.ReadArrayClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	}
+	return nil
+}
+
+func (t *MixedTuple) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
+
+func (t *MixedMap) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+	written := 0
+
+	// t.Tuple (testing.MixedTuple) (struct)
+	if len("Tuple") > 8192 {
+		return fmt.Errorf("String in field \"Tuple\" was too long")
+	}
+	if err := jw.WriteString(string("Tuple")); err != nil {
+		return fmt.Errorf("\"Tuple\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Tuple.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Tuple: %w", err)
+	}
+	written++
+	if written > 0 {
+		if err := jw.WriteComma(); err != nil {
+			return err
+		}
+	}
+
+	// t.Tuples ([]testing.MixedTuple) (slice)
+	if len("Tuples") > 8192 {
+		return fmt.Errorf("String in field \"Tuples\" was too long")
+	}
+	if err := jw.WriteString(string("Tuples")); err != nil {
+		return fmt.Errorf("\"Tuples\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if len(t.Tuples) > 8192 {
+		return fmt.Errorf("Slice value in field t.Tuples was too long")
+	}
+
+	if err := jw.WriteArrayOpen(); err != nil {
+		return fmt.Errorf("t.Tuples: %w", err)
+	}
+	for i, v := range t.Tuples {
+		if i > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return fmt.Errorf("t.Tuples: %w", err)
+			}
+		}
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+	}
+	if err := jw.WriteArrayClose(); err != nil {
+		return fmt.Errorf("t.Tuples: %w", err)
+	}
+
+	written++
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *MixedMap) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MixedMap{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Tuple (testing.MixedTuple) (struct)
+			case "Tuple":
+
+				if err := t.Tuple.UnmarshalDagJSON(jr); err != nil {
+					return jr.PathError(".Tuple", err)
+				}
+
+				// t.Tuples ([]testing.MixedTuple) (slice)
+			case "Tuples":
+				{
+
+					if err := jr.ReadArrayOpen(); err != nil {
+						return jr.PathError(".Tuples", err)
+					}
+
+					close, err := jr.PeekArrayClose()
+					if err != nil {
+						return jr.PathError(".Tuples", err)
+					}
+					if close {
+						if err := jr.ReadArrayClose(); err != nil {
+							return jr.PathError(".Tuples", err)
+						}
+
+					} else {
+						for i := 0; i < 8192; i++ {
+							item := make([]MixedTuple, 1)
+
+							if err := item[0].UnmarshalDagJSON(jr); err != nil {
+								return jr.PathError(".Tuples"+jsg.IndexPath(i), err)
+							}
+
+							t.Tuples = append(t.Tuples, item[0])
+
+							close, err := jr.ReadArrayCloseOrComma()
+							if err != nil {
+								return jr.PathError(".Tuples", err)
+							}
+							if close {
+								break
+							}
+							if i == 8192-1 {
+								return jr.PathError(".Tuples", errors.New("slice too large"))
+							}
+						}
+					}
+
+				}
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *MixedMap) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if err := jsg.ScanLinks(&t.Tuple, cb); err != nil {
+		return fmt.Errorf("t.Tuple: %w", err)
+	}
+
+	for _, v := range t.Tuples {
+
+		if err := jsg.ScanLinks(&v, cb); err != nil {
+			return fmt.Errorf("v: %w", err)
+		}
+
+	}
+	return nil
+}
+
+func (t *MixedDefault) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
+	if t == nil {
+		err := jw.WriteNull()
+		return err
+	}
+	if err := jw.WriteObjectOpen(); err != nil {
+		return err
+	}
+
+	// t.Inner (testing.MixedTuple) (struct)
+	if len("Inner") > 8192 {
+		return fmt.Errorf("String in field \"Inner\" was too long")
+	}
+	if err := jw.WriteString(string("Inner")); err != nil {
+		return fmt.Errorf("\"Inner\": %w", err)
+	}
+	if err := jw.WriteObjectColon(); err != nil {
+		return err
+	}
+	if err := t.Inner.MarshalDagJSON(jw); err != nil {
+		return fmt.Errorf("t.Inner: %w", err)
+	}
+	if err := jw.WriteObjectClose(); err != nil {
+		return err
+	}
+	return nil
+}
+func (t *MixedDefault) UnmarshalDagJSON(r io.Reader) (err error) {
+	*t = MixedDefault{}
+
+	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
+	defer func() {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return jr.PathError("", err)
+	}
+
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return jr.PathError("", err)
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return jr.PathError("", err)
+		}
+	} else {
+		for i := uint64(0); i < 8192; i++ {
+			name, err := jr.ReadString(8192)
+			if err != nil {
+				if errors.Is(err, jsg.ErrLimitExceeded) {
+					return jr.PathError("", errors.New("string too large"))
+				}
+				return jr.PathError("", err)
+			}
+			if err := jr.ReadObjectColon(); err != nil {
+				return jr.PathError("", err)
+			}
+			switch name {
+
+			// t.Inner (testing.MixedTuple) (struct)
+			case "Inner":
+
+				{
+					null, err := jr.PeekNull()
+					if err != nil {
+						return jr.PathError(".Inner", err)
+					}
+					if null {
+						if err := jr.ReadNull(); err != nil {
+							return jr.PathError(".Inner", err)
+						}
+					} else {
+						t.Inner = new(MixedTuple)
+						if err := t.Inner.UnmarshalDagJSON(jr); err != nil {
+							return jr.PathError(".Inner", err)
+						}
+					}
+				}
+
+			default:
+
+				// Field doesn't exist on this type, so ignore it
+				if err := jr.DiscardType(); err != nil {
+					return jr.PathError(jsg.KeyPath(name), err)
+				}
+
+			}
+
+			close, err := jr.ReadObjectCloseOrComma()
+			if err != nil {
+				return jr.PathError("", err)
+			}
+			if close {
+				break
+			}
+			if i == 8192-1 {
+				return jr.PathError("", errors.New("map too large"))
+			}
+		}
+	}
+
+	return nil
+}
+func (t *MixedDefault) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Inner != nil {
+		if err := jsg.ScanLinks(t.Inner, cb); err != nil {
+			return fmt.Errorf("t.Inner: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -3,23 +3,28 @@
 package testing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"strconv"
 
 	jsg "github.com/alanshaw/dag-json-gen"
 	cid "github.com/ipfs/go-cid"
 )
 
 var _ = cid.Undef
+var _ = bytes.NewReader
 var _ = math.E
 var _ = sort.Sort
+var _ = strconv.Itoa
 var _ = errors.Is
 
-func (t *LimitedStruct) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *LimitedStruct) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -86,21 +91,22 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = LimitedStruct{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("LimitedStruct: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("LimitedStruct: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("LimitedStruct: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -109,16 +115,16 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 
 			if err := jr.ReadArrayOpen(); err != nil {
-				return fmt.Errorf("t.Arr: %w", err)
+				return jr.PathError(".Arr", err)
 			}
 
 			close, err := jr.PeekArrayClose()
 			if err != nil {
-				return fmt.Errorf("t.Arr: %w", err)
+				return jr.PathError(".Arr", err)
 			}
 			if close {
 				if err := jr.ReadArrayClose(); err != nil {
-					return fmt.Errorf("t.Arr: %w", err)
+					return jr.PathError(".Arr", err)
 				}
 
 			} else {
@@ -128,8 +134,9 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 
 						nval, err := jr.ReadNumberAsUint64()
 						if err != nil {
-							return fmt.Errorf("item[0]: %w", err)
+							return jr.PathError(".Arr"+jsg.IndexPath(i), err)
 						}
+
 						item[0] = uint64(nval)
 
 					}
@@ -137,13 +144,13 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 
 					close, err := jr.ReadArrayCloseOrComma()
 					if err != nil {
-						return fmt.Errorf("t.Arr: %w", err)
+						return jr.PathError(".Arr", err)
 					}
 					if close {
 						break
 					}
 					if i == 10-1 {
-						return fmt.Errorf("t.Arr: slice too large")
+						return jr.PathError(".Arr", errors.New("slice too large"))
 					}
 				}
 			}
@@ -152,10 +159,10 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("LimitedStruct: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 1 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 1 < 3"))
 			}
 		}
 
@@ -165,9 +172,9 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 			bval, err := jr.ReadBytes(9)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Byts: byte array too large")
+					return jr.PathError(".Byts", errors.New("byte array too large"))
 				}
-				return fmt.Errorf("t.Byts: %w", err)
+				return jr.PathError(".Byts", err)
 			}
 			if len(bval) > 0 {
 				t.Byts = []uint8(bval)
@@ -177,10 +184,10 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 		{
 			close, err := jr.ReadArrayCloseOrComma()
 			if err != nil {
-				return fmt.Errorf("LimitedStruct: %w", err)
+				return jr.PathError("", err)
 			}
 			if close {
-				return fmt.Errorf("json input has too few fields 2 < 3")
+				return jr.PathError("", errors.New("json input has too few fields 2 < 3"))
 			}
 		}
 
@@ -190,15 +197,22 @@ func (t *LimitedStruct) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(8)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Str: string too long")
+					return jr.PathError(".Str", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.Str: %w", err)
+				return jr.PathError(".Str", err)
 			}
 			t.Str = string(sval)
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("LimitedStruct: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
+
+func (t *LimitedStruct) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
@@ -3,23 +3,28 @@
 package testing
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"sort"
+	"strconv"
 
 	jsg "github.com/alanshaw/dag-json-gen"
 	cid "github.com/ipfs/go-cid"
 )
 
 var _ = cid.Undef
+var _ = bytes.NewReader
 var _ = math.E
 var _ = sort.Sort
+var _ = strconv.Itoa
 var _ = errors.Is
 
-func (t *LongString) MarshalDagJSON(w io.Writer) error {
-	jw := jsg.NewDagJsonWriter(w)
+func (t *LongString) MarshalDagJSON(w io.Writer) (err error) {
+	jw := jsg.NewBufferedDagJsonWriter(w)
+	defer jw.Release(w, &err)
 	if t == nil {
 		err := jw.WriteNull()
 		return err
@@ -45,21 +50,22 @@ func (t *LongString) UnmarshalDagJSON(r io.Reader) (err error) {
 	*t = LongString{}
 
 	jr := jsg.NewDagJsonReader(r)
+	defer jr.Release(r)
 	defer func() {
 		if err == io.EOF {
 			err = io.ErrUnexpectedEOF
 		}
 	}()
 	if err := jr.ReadArrayOpen(); err != nil {
-		return fmt.Errorf("LongString: %w", err)
+		return jr.PathError("", err)
 	}
 	close, err := jr.PeekArrayClose()
 	if err != nil {
-		return fmt.Errorf("LongString: %w", err)
+		return jr.PathError("", err)
 	}
 	if close {
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("LongString: %w", err)
+			return jr.PathError("", err)
 		}
 	} else {
 
@@ -69,15 +75,22 @@ func (t *LongString) UnmarshalDagJSON(r io.Reader) (err error) {
 			sval, err := jr.ReadString(10000)
 			if err != nil {
 				if errors.Is(err, jsg.ErrLimitExceeded) {
-					return fmt.Errorf("t.Val: string too long")
+					return jr.PathError(".Val", errors.New("string too long"))
 				}
-				return fmt.Errorf("t.Val: %w", err)
+				return jr.PathError(".Val", err)
 			}
 			t.Val = string(sval)
 		}
 		if err := jr.ReadArrayClose(); err != nil {
-			return fmt.Errorf("LongString: %w", err)
+			return jr.PathError("", err)
 		}
 	}
 	return nil
 }
+
+func (t *LongString) ScanDagJSONLinks(cb func(cid.Cid)) error {
+	if t == nil {
+		return nil
+	}
+	return nil
+}
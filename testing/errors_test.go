@@ -0,0 +1,91 @@
+package testing
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+)
+
+func TestDecodeErrorPath(t *testing.T) {
+	two := SimpleTypeTwo{Stuff: &SimpleTypeTwo{}}
+	two.Stuff.Arrrrrghay[2].Foo = "marker"
+	v1 := SimpleStructV1{OldMap: map[string]SimpleTypeOne{"a b": {Foo: "marker"}}}
+	// Union members are decoded once the whole union has been read.
+	unions := UnionContainer{
+		Keyed:    KeyedUnion{Label: ptr("a")},
+		Envelope: &EnvelopeUnion{Square: &Square{Side: 1}},
+		Inline:   []InlineUnion{{Circle: &Circle{}}, {Square: &Square{Color: "marker"}}},
+		Kinded:   map[string]KindedUnion{"k": {List: ptr([]string{"a"})}},
+	}
+	kinded := UnionContainer{
+		Keyed:    KeyedUnion{Label: ptr("a")},
+		Envelope: &EnvelopeUnion{Count: ptr(uint64(1))},
+		Kinded:   map[string]KindedUnion{"k": {List: ptr([]string{"a", "marker"})}},
+	}
+
+	for _, tc := range []struct {
+		val  jsg.DagJsonMarshaler
+		into jsg.DagJsonUnmarshaler
+		path string
+	}{
+		{&two, new(SimpleTypeTwo), ".Stuff.Arrrrrghay[2].Foo"},
+		{&v1, new(SimpleStructV1), `.OldMap["a b"].Foo`},
+		{&EnvelopeUnion{Square: &Square{Color: "marker"}}, new(EnvelopeUnion), ".Square.Color"},
+		{&unions, new(UnionContainer), ".Inline[1].Square.Color"},
+		{&kinded, new(UnionContainer), `.Kinded["k"].List[1]`},
+	} {
+		var buf bytes.Buffer
+		if err := tc.val.MarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		enc := buf.Bytes()
+		offset := bytes.Index(enc, []byte(`"marker"`))
+		enc = bytes.Replace(enc, []byte(`"marker"`), []byte("12345678"), 1)
+
+		err := tc.into.UnmarshalDagJSON(bytes.NewReader(enc))
+		var de *jsg.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected a DecodeError, got %v", err)
+		}
+		if de.Path != tc.path || de.Expected != "string" || de.Actual != "number" || de.Offset != int64(offset) {
+			t.Errorf("unexpected error %#v", de)
+		}
+		if !strings.HasPrefix(err.Error(), tc.path+": expected string but read number") {
+			t.Errorf("unexpected message %q", err)
+		}
+	}
+}
+
+func TestDecodeErrorInlineUnionOffset(t *testing.T) {
+	// The discriminant is left out of the member, but offsets after it are
+	// still those of the input.
+	for _, in := range []string{
+		`{"shape":"square","Color":12345678,"Side":1}`,
+		`{"Side":1,"shape":"square","Color":12345678}`,
+	} {
+		err := new(InlineUnion).UnmarshalDagJSON(strings.NewReader(in))
+		var de *jsg.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected a DecodeError, got %v", err)
+		}
+		if de.Path != ".Square.Color" || de.Offset != int64(strings.Index(in, "12345678")) {
+			t.Errorf("%s: unexpected error %#v", in, de)
+		}
+	}
+}
+
+func TestDecodeErrorCause(t *testing.T) {
+	var v SignedArray
+	err := v.UnmarshalDagJSON(strings.NewReader(`[[1,2,3`))
+	var de *jsg.DecodeError
+	if !errors.As(err, &de) || de.Path != ".Signed" || de.Offset != 7 {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("expected the cause to be io.EOF, got %v", de.Err)
+	}
+}
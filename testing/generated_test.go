@@ -0,0 +1,64 @@
+package testing
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+)
+
+// TestGeneratedFilesUpToDate checks the generated files against what
+// testgen would write.
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	for _, f := range GeneratedFiles {
+		if err := f.Check("."); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// recorder is a jsg.TestingT that records failures.
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestStaleGeneratedFile(t *testing.T) {
+	data, err := os.ReadFile("dag_json_mixed_gen.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fname := filepath.Join(t.TempDir(), "dag_json_mixed_gen.go")
+	stale := strings.Replace(string(data), "func (t *MixedMap) MarshalDagJSON", "// hand edited\nfunc (t *MixedMap) MarshalDagJSON", 1)
+	if err := os.WriteFile(fname, []byte(stale), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = jsg.CheckEncodersFile(fname, "testing", MixedTuple{}, MixedMap{}, MixedDefault{})
+	var serr *jsg.StaleFileError
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected a stale file error, got %v", err)
+	}
+	if serr.Name != fname || !strings.Contains(serr.Diff, "\n-// hand edited\n") || !strings.Contains(serr.Diff, "\n@@ -") {
+		t.Fatalf("expected a diff removing the edit:\n%s", serr.Diff)
+	}
+
+	var r recorder
+	jsg.AssertGenerated(&r, fname, "testing", MixedTuple{}, MixedMap{}, MixedDefault{})
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], serr.Diff) {
+		t.Fatalf("expected the diff to be reported, got %q", r.errors)
+	}
+
+	if err := jsg.CheckEncodersFile(filepath.Join(t.TempDir(), "missing.go"), "testing", MixedTuple{}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing file error, got %v", err)
+	}
+}
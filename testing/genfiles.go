@@ -0,0 +1,144 @@
+package testing
+
+import (
+	"math/big"
+	"path/filepath"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+)
+
+// GeneratedFile describes a file of generated code in this package, so that
+// testgen and the test checking the file is up to date agree on its contents.
+type GeneratedFile struct {
+	Name string
+	// Encoding is "tuple" or "map", or empty for each type to be encoded in
+	// the representation it declares.
+	Encoding string
+	Gen      jsg.Gen
+	Types    []interface{}
+}
+
+// GeneratedFiles lists every generated file in this package.
+var GeneratedFiles = []GeneratedFile{
+	{
+		Name:     "dag_json_gen.go",
+		Encoding: "tuple",
+		Types: []interface{}{
+			SignedArray{},
+			SimpleTypeOne{},
+			SimpleTypeTwo{},
+			DeferredContainer{},
+			FixedArrays{},
+			ThingWithSomeTime{},
+			BigField{},
+			IntArray{},
+			IntAliasArray{},
+			TupleIntArray{},
+			TupleIntArrayOptionals{},
+			IntArrayNewType{},
+			IntArrayAliasNewType{},
+			MapTransparentType{},
+			BigIntContainer{},
+			TupleWithOptionalFields{},
+			FloatContainer{},
+			IntWidths{},
+			TupleWithDefaults{},
+			TupleEnvelope{},
+		},
+	},
+	{
+		Name:     "dag_json_map_gen.go",
+		Encoding: "map",
+		Types: []interface{}{
+			SimpleTypeTree{},
+			NeedScratchForMap{},
+			SimpleStructV1{},
+			SimpleStructV1Lossless{},
+			SimpleStructV2{},
+			RenamedFields{},
+			TestEmpty{},
+			TestConstField{},
+			TestCanonicalFieldOrder{},
+			MapStringString{},
+			TestSliceNilPreserve{},
+			StringPtrSlices{},
+			FieldNameOverlap{},
+			MapKeys{},
+			MapValues{},
+			Circle{},
+			Square{},
+			KeyedUnion{},
+			EnvelopeUnion{},
+			InlineUnion{},
+			KindedUnion{},
+			UnionContainer{},
+			NodeContainer{},
+			StrictFields{},
+			StrictFieldsLossless{},
+			StrictInlineUnion{},
+			LosslessInlineUnion{},
+			UnionHolder{},
+			RequiredFields{},
+			DefaultFields{},
+			OmitEmptyKinds{},
+			Window{},
+			Envelope{},
+			Stamp{},
+			Page[SimpleTypeOne]{},
+			Page[int64]{},
+			Pair[string, *big.Int]{},
+			GenericFields{},
+		},
+	},
+	{
+		Name:     "dag_json_options_gen.go",
+		Encoding: "tuple",
+		Gen: jsg.Gen{
+			MaxArrayLength:  10,
+			MaxByteLength:   9,
+			MaxStringLength: 8,
+		},
+		Types: []interface{}{LimitedStruct{}},
+	},
+	{
+		Name:     "dag_json_options_gen2.go",
+		Encoding: "tuple",
+		Gen: jsg.Gen{
+			MaxArrayLength:  10,
+			MaxByteLength:   9,
+			MaxStringLength: 10000,
+		},
+		Types: []interface{}{LongString{}},
+	},
+	{
+		Name:  "dag_json_mixed_gen.go",
+		Types: []interface{}{MixedTuple{}, MixedMap{}, MixedDefault{}},
+	},
+}
+
+// Write generates the file into dir.
+func (f GeneratedFile) Write(dir string) error {
+	fname := filepath.Join(dir, f.Name)
+	switch f.Encoding {
+	case "tuple":
+		return f.Gen.WriteTupleEncodersToFile(fname, "testing", f.Types...)
+	case "map":
+		return f.Gen.WriteMapEncodersToFile(fname, "testing", f.Types...)
+	default:
+		return f.Gen.WriteEncodersToFile(fname, "testing", f.Types...)
+	}
+}
+
+// Check returns a *jsg.StaleFileError if the file in dir is not what Write
+// would generate.
+func (f GeneratedFile) Check(dir string) error {
+	fname := filepath.Join(dir, f.Name)
+	switch f.Encoding {
+	case "tuple":
+		return f.Gen.CheckTupleEncodersFile(fname, "testing", f.Types...)
+	case "map":
+		return f.Gen.CheckMapEncodersFile(fname, "testing", f.Types...)
+	default:
+		return f.Gen.CheckEncodersFile(fname, "testing", f.Types...)
+	}
+}
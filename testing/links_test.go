@@ -0,0 +1,172 @@
+package testing
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+	"github.com/ipfs/go-cid"
+)
+
+type linkScanner interface {
+	jsg.DagJsonMarshaler
+	jsg.DagJsonLinkScanner
+}
+
+// testLinkScan checks that the generated link scan of val finds the same
+// links as scanning its encoding, in the same order every time, and returns
+// them sorted.
+func testLinkScan(t *testing.T, val linkScanner) []string {
+	t.Helper()
+
+	scan := func() []string {
+		var scanned []string
+		if err := val.ScanDagJSONLinks(func(c cid.Cid) {
+			scanned = append(scanned, c.String())
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return scanned
+	}
+	scanned := scan()
+	for i := 0; i < 10; i++ {
+		if again := scan(); strings.Join(again, ",") != strings.Join(scanned, ",") {
+			t.Fatalf("generated scan found %v, then %v", scanned, again)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := val.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var streamed []string
+	if err := jsg.ScanForLinks(&buf, func(c cid.Cid) {
+		streamed = append(streamed, c.String())
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(scanned)
+	sort.Strings(streamed)
+	if strings.Join(scanned, ",") != strings.Join(streamed, ",") {
+		t.Fatalf("generated scan found %v, streaming scan found %v", scanned, streamed)
+	}
+	return scanned
+}
+
+func TestLinkScanRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(56887))
+	for _, typ := range []reflect.Type{
+		reflect.TypeOf(SimpleTypeTwo{}),
+		reflect.TypeOf(SimpleTypeTree{}),
+	} {
+		for i := 0; i < 100; i++ {
+			val, ok := quick.Value(typ, r)
+			if !ok {
+				t.Fatalf("failed to construct %s", typ)
+			}
+			testLinkScan(t, val.Addr().Interface().(linkScanner))
+		}
+	}
+}
+
+func TestLinkScan(t *testing.T) {
+	a, _ := cid.Parse("bafkqaaa")
+	b, _ := cid.Parse("bafkqaalc")
+
+	t.Run("struct", func(t *testing.T) {
+		val := SimpleStructV1{
+			OldPtr:         &a,
+			OldCidArray:    []cid.Cid{b},
+			OldCidPtrArray: []*cid.Cid{nil, &a},
+		}
+		links := testLinkScan(t, &val)
+		if len(links) != 3 {
+			t.Fatalf("expected 3 links, got %v", links)
+		}
+	})
+
+	t.Run("deferred", func(t *testing.T) {
+		val := DeferredContainer{
+			Stuff:    &SimpleTypeOne{},
+			Deferred: &jsg.Deferred{Raw: []byte(`{"a":[{"/":"bafkqaaa"}],"b":{"/":{"bytes":"AQID"}}}`)},
+		}
+		links := testLinkScan(t, &val)
+		if len(links) != 1 || links[0] != a.String() {
+			t.Fatalf("expected a single link, got %v", links)
+		}
+	})
+
+	t.Run("map values", func(t *testing.T) {
+		val := MapValues{
+			Cids:     map[string]cid.Cid{"a": a, "b": b},
+			CidPtrs:  map[string]*cid.Cid{"a": &a, "n": nil},
+			IntCids:  map[int64]cid.Cid{1: a, 2: b, 3: a},
+			TextCids: map[TextKey]*cid.Cid{{Major: 1}: &b, {Major: 2}: &a, {Major: 3}: nil},
+		}
+		links := testLinkScan(t, &val)
+		if len(links) != 8 {
+			t.Fatalf("expected 8 links, got %v", links)
+		}
+	})
+
+	t.Run("unions", func(t *testing.T) {
+		val := UnionContainer{
+			Keyed:    KeyedUnion{Label: ptr("a")},
+			Envelope: &EnvelopeUnion{Count: ptr(uint64(1))},
+			Kinded:   map[string]KindedUnion{"l": {Link: &a}, "s": {String: ptr("x")}},
+		}
+		links := testLinkScan(t, &val)
+		if len(links) != 1 {
+			t.Fatalf("expected a single link, got %v", links)
+		}
+	})
+
+	t.Run("nodes", func(t *testing.T) {
+		val := NodeContainer{
+			Any:  jsg.NewList([]jsg.Node{jsg.NewLink(a), jsg.NewString("x")}),
+			List: []jsg.Node{jsg.NewMap(map[string]jsg.Node{"b": jsg.NewLink(b)})},
+			Map:  map[string]jsg.Node{"a": jsg.NewLink(a)},
+		}
+		links := testLinkScan(t, &val)
+		if len(links) != 3 {
+			t.Fatalf("expected 3 links, got %v", links)
+		}
+	})
+}
+
+func TestScanForLinks(t *testing.T) {
+	for in, expected := range map[string]string{
+		`null`:             "",
+		`{"/":"bafkqaaa"}`: "bafkqaaa",
+		`[{"/":"bafkqaaa"},{"x":{"/":"bafkqaaa"}}]`: "bafkqaaa,bafkqaaa",
+		`{"/":"notacid","a":1}`:                     "",
+		`{"/":{"bytes":"AQID"}}`:                    "",
+		`{"a":"bafkqaaa","/":"bafkqaaa"}`:           "",
+	} {
+		var found []string
+		if err := jsg.ScanForLinks(strings.NewReader(in), func(c cid.Cid) {
+			found = append(found, c.String())
+		}); err != nil {
+			t.Fatalf("%s: %s", in, err)
+		}
+		if strings.Join(found, ",") != expected {
+			t.Errorf("%s: expected %q, got %v", in, expected, found)
+		}
+	}
+
+	for _, in := range []string{
+		`{"/":"notacid"}`,
+		`[{"/":"bafkqaaa"}`,
+		strings.Repeat("[", jsg.MaxNodeDepth+2) + strings.Repeat("]", jsg.MaxNodeDepth+2),
+	} {
+		if err := jsg.ScanForLinks(strings.NewReader(in), func(cid.Cid) {}); err == nil {
+			t.Errorf("expected an error scanning %.20s", in)
+		}
+	}
+}
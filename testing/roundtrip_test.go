@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
 	"math/rand"
 	"reflect"
@@ -53,15 +54,255 @@ func TestNilPreserveWorks(t *testing.T) {
 	testTypeRoundtrips(t, reflect.TypeOf(TestSliceNilPreserve{}))
 }
 
+func TestFloatContainer(t *testing.T) {
+	testTypeRoundtrips(t, reflect.TypeOf(FloatContainer{}))
+}
+
+func TestFloatEncoding(t *testing.T) {
+	for _, tc := range []struct {
+		f32    float32
+		f64    float64
+		golden string
+	}{
+		{0, 0, `[0.0,0.0,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+		{1, -2, `[1.0,-2.0,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+		{0.1, 0.1, `[0.1,0.1,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+		{1e21, 1e21, `[1e+21,1e+21,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+		{1e-7, 1.5e-7, `[1e-7,1.5e-7,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+		{3.4028235e38, math.MaxFloat64, `[3.4028235e+38,1.7976931348623157e+308,null,null,0.0,[],[],[0.0,0.0,0.0],{}]`},
+	} {
+		val := &FloatContainer{F32: tc.f32, F64: tc.f64}
+		recepticle := &FloatContainer{}
+		testValueRoundtrip(t, val, recepticle, WithGolden(tc.golden))
+		if recepticle.F32 != tc.f32 || recepticle.F64 != tc.f64 {
+			t.Fatalf("floats did not round trip: %v, %v", recepticle.F32, recepticle.F64)
+		}
+	}
+
+	t.Run("pointers, slices and maps", func(t *testing.T) {
+		val := &FloatContainer{
+			F32Ptr:  ptr(float32(2.5)),
+			F64Ptr:  ptr(-0.25),
+			Named:   7,
+			Floats:  []float64{1, 1.5},
+			Float32: []float32{0.3},
+			Fixed:   [3]float64{1, 2, 3},
+			Map:     map[string]float64{"b": 2, "a": 1.25},
+		}
+		recepticle := &FloatContainer{}
+		testValueRoundtrip(t, val, recepticle, WithGolden(`[0.0,0.0,2.5,-0.25,7.0,[1.0,1.5],[0.3],[1.0,2.0,3.0],{"a":1.25,"b":2.0}]`))
+	})
+
+	t.Run("integer syntax", func(t *testing.T) {
+		var out FloatContainer
+		if err := out.UnmarshalDagJSON(strings.NewReader(`[1,2,3,4,5,[6],[7],[8,9,10],{"a":11}]`)); err != nil {
+			t.Fatal(err)
+		}
+		if out.F64 != 2 || *out.F64Ptr != 4 || out.Map["a"] != 11 {
+			t.Fatalf("unexpected value: %#v", out)
+		}
+	})
+
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		val := FloatContainer{F64: f}
+		if err := val.MarshalDagJSON(new(bytes.Buffer)); err == nil {
+			t.Fatalf("marshaling %v should have failed", f)
+		}
+	}
+}
+
+func TestIntWidths(t *testing.T) {
+	testTypeRoundtrips(t, reflect.TypeOf(IntWidths{}))
+
+	t.Run("extremes", func(t *testing.T) {
+		val := &IntWidths{
+			Int:      math.MinInt,
+			Int8:     math.MinInt8,
+			Int16:    math.MaxInt16,
+			Int32:    math.MinInt32,
+			Uint:     math.MaxUint,
+			Uint8:    math.MaxUint8,
+			Uint16:   math.MaxUint16,
+			Uint32:   math.MaxUint32,
+			IntPtr:   ptr(-1),
+			Int8Ptr:  ptr(int8(-8)),
+			UintPtr:  ptr(uint(1)),
+			Uint8Ptr: ptr(uint8(8)),
+			Int32s:   []int32{math.MaxInt32},
+			Uint16s:  []uint16{0},
+			Int8Ptrs: []*int8{ptr(int8(1)), nil},
+			Fixed:    [2]int16{math.MinInt16, math.MaxInt16},
+			Map:      map[string]uint32{"a": math.MaxUint32},
+			MapInt8:  map[string]int8{"b": math.MinInt8},
+		}
+		testValueRoundtrip(t, val, &IntWidths{})
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		for _, in := range []string{
+			`[0,128,0,0,0,0,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,-129,0,0,0,0,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,32768,0,0,0,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,2147483648,0,0,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,256,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,65536,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,0,4294967296,null,null,null,null,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,256,[],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,null,[2147483648],[],[],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,null,[],[],[-129],[0,0],{},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,null,[],[],[],[0,-32769],{},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,null,[],[],[],[0,0],{"a":4294967296},{}]`,
+			`[0,0,0,0,0,0,0,0,null,null,null,null,[],[],[],[0,0],{},{"a":128}]`,
+			`[0,0,0,0,-1,0,0,0,null,null,null,null,[],[],[],[0,0],{},{}]`,
+		} {
+			var out IntWidths
+			if err := out.UnmarshalDagJSON(strings.NewReader(in)); err == nil {
+				t.Fatalf("expected an out of range error decoding %s", in)
+			}
+		}
+	})
+}
+
+func TestMapKeys(t *testing.T) {
+	testTypeRoundtrips(t, reflect.TypeOf(MapKeys{}))
+
+	t.Run("encoding", func(t *testing.T) {
+		val := &MapKeys{
+			Int64s:  map[int64]string{9: "a", 10: "b", -1: "c"},
+			Uint64s: map[uint64]string{math.MaxUint64: "d"},
+			Int8s:   map[int8]string{math.MinInt8: "e"},
+			Ints:    map[int]string{},
+			Named:   map[NamedNumber]string{2: "f"},
+			Strings: map[NamedString]string{"b": "g", "a": "h"},
+			Text:    map[TextKey]string{{Major: 1, Minor: 10}: "i", {Major: 1, Minor: 2}: "j"},
+		}
+		expected := `{"Int64s":{"-1":"c","10":"b","9":"a"},"Int8s":{"-128":"e"},"Ints":{},"Named":{"2":"f"},"Strings":{"a":"h","b":"g"},"Text":{"1.10":"i","1.2":"j"},"Uint64s":{"18446744073709551615":"d"}}`
+
+		buf := new(bytes.Buffer)
+		if err := val.MarshalDagJSON(buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != expected {
+			t.Fatalf("unexpected encoding:\n%s\nexpected:\n%s", buf.String(), expected)
+		}
+		testValueRoundtrip(t, val, &MapKeys{})
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, in := range []string{
+			`{"Int8s":{"128":""}}`,
+			`{"Int64s":{"01":""}}`,
+			`{"Int64s":{"+1":""}}`,
+			`{"Int64s":{"-0":""}}`,
+			`{"Uint64s":{"-1":""}}`,
+			`{"Ints":{"one":""}}`,
+			`{"Text":{"1":""}}`,
+		} {
+			var out MapKeys
+			if err := out.UnmarshalDagJSON(strings.NewReader(in)); err == nil {
+				t.Fatalf("expected an error decoding %s", in)
+			}
+		}
+	})
+}
+
+func TestMapValues(t *testing.T) {
+	dummyCid, _ := cid.Parse("bafkqaaa")
+	val := &MapValues{
+		Uints:   map[string]uint64{"a": 1, "b": math.MaxUint64},
+		Bytes:   map[string][]byte{"a": {1, 2, 3}, "b": {}},
+		Cids:    map[string]cid.Cid{"a": dummyCid},
+		CidPtrs: map[string]*cid.Cid{"a": &dummyCid, "b": nil},
+		Structs: map[string][]SimpleTypeOne{"a": {{Foo: "foo", Value: 1}}, "b": {}},
+		Nested:  map[string]map[string]string{"a": {"b": "c"}, "d": {}},
+		Deep:    map[string]map[int64][]string{"a": {-1: {"b", "c"}, 2: {}}},
+		Bools:   map[string]bool{"t": true, "f": false},
+		IntPtrs: map[string]*int64{"a": ptr(int64(-1)), "b": nil},
+
+		IntCids:  map[int64]cid.Cid{10: dummyCid, 9: dummyCid},
+		TextCids: map[TextKey]*cid.Cid{{Major: 1}: &dummyCid, {Major: 2}: nil},
+	}
+	golden := `{"Bools":{"f":false,"t":true},` +
+		`"Bytes":{"a":{"/":{"bytes":"AQID"}},"b":{"/":{"bytes":""}}},` +
+		`"CidPtrs":{"a":{"/":"bafkqaaa"},"b":null},` +
+		`"Cids":{"a":{"/":"bafkqaaa"}},` +
+		`"Deep":{"a":{"-1":["b","c"],"2":[]}},` +
+		`"IntCids":{"10":{"/":"bafkqaaa"},"9":{"/":"bafkqaaa"}},` +
+		`"IntPtrs":{"a":-1,"b":null},` +
+		`"Nested":{"a":{"b":"c"},"d":{}},` +
+		`"Structs":{"a":[["foo",1,{"/":{"bytes":""}},0,"",[]]],"b":[]},` +
+		`"TextCids":{"1.0":{"/":"bafkqaaa"},"2.0":null},` +
+		`"Uints":{"a":1,"b":18446744073709551615}}`
+
+	// cmp cannot compare the CIDs held in maps, so check the round trip by
+	// re-encoding instead.
+	buf := new(bytes.Buffer)
+	if err := val.MarshalDagJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != golden {
+		t.Fatalf("unexpected encoding:\n%s\nexpected:\n%s", buf.String(), golden)
+	}
+	var out MapValues
+	if err := out.UnmarshalDagJSON(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if *out.CidPtrs["a"] != dummyCid || out.CidPtrs["b"] != nil || *out.IntPtrs["a"] != -1 {
+		t.Fatal("pointer map values did not round trip")
+	}
+	nbuf := new(bytes.Buffer)
+	if err := out.MarshalDagJSON(nbuf); err != nil {
+		t.Fatal(err)
+	}
+	if nbuf.String() != golden {
+		t.Fatalf("unexpected re-encoding:\n%s\nexpected:\n%s", nbuf.String(), golden)
+	}
+}
+
+func TestNodeContainer(t *testing.T) {
+	in := `{"Any":{"a":[1,2.5,"x"]},"List":[null,true],"Map":{"k":{"/":{"bytes":"AQID"}}},"Ptr":null}`
+	var out NodeContainer
+	if err := out.UnmarshalDagJSON(strings.NewReader(in)); err != nil {
+		t.Fatal(err)
+	}
+	if out.Any.Kind() != jsg.KindMap || out.Ptr != nil || len(out.List) != 2 {
+		t.Fatalf("unexpected decoded value: %+v", out)
+	}
+	if b, err := out.Map["k"].AsBytes(); err != nil || !bytes.Equal(b, []byte{1, 2, 3}) {
+		t.Fatalf("unexpected bytes %v: %v", b, err)
+	}
+
+	var buf bytes.Buffer
+	if err := out.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != in {
+		t.Fatalf("unexpected encoding:\n%s\nexpected:\n%s", buf.String(), in)
+	}
+
+	n := jsg.NewString("set")
+	out.Ptr = &n
+	buf.Reset()
+	if err := out.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), `"Ptr":"set"}`) {
+		t.Fatalf("unexpected encoding: %s", buf.String())
+	}
+}
+
 func TestLongStrings(t *testing.T) {
 	testTypeRoundtrips(t, reflect.TypeOf(LongString{}))
 }
 
 func TestBigInt(t *testing.T) {
+	huge, _ := new(big.Int).SetString("-"+strings.Repeat("9", 1000), 10)
 	for _, v := range []BigIntContainer{
 		{Int: big.NewInt(100)},
 		{Int: big.NewInt(0)},
 		{Int: nil},
+		{Int: big.NewInt(-1)},
+		{Int: huge, Bounded: big.NewInt(-9999999999)},
 	} {
 
 		var buf bytes.Buffer
@@ -81,11 +322,16 @@ func TestBigInt(t *testing.T) {
 			t.Fatal("did not round-trip")
 		}
 	}
+
 	var buf bytes.Buffer
-	v := BigIntContainer{Int: big.NewInt(-1)}
-	err := v.MarshalDagJSON(&buf)
-	if err == nil {
-		t.Fatal("marshalling a negative int should have failed")
+	v := BigIntContainer{Bounded: big.NewInt(-10000000000)}
+	if err := v.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var o BigIntContainer
+	err := o.UnmarshalDagJSON(&buf)
+	if err == nil || !strings.Contains(err.Error(), ".Bounded: number too large") {
+		t.Fatalf("expected an error for too many digits, got %v", err)
 	}
 }
 
@@ -123,7 +369,8 @@ func testValueRoundtrip(t *testing.T, obj jsg.DagJsonMarshaler, nobj jsg.DagJson
 		}
 	}
 
-	if err := nobj.UnmarshalDagJSON(bytes.NewReader(enc)); err != nil {
+	// Everything we encode must be canonical, so decode strictly.
+	if err := nobj.UnmarshalDagJSON(jsg.NewDagJsonReader(bytes.NewReader(enc), jsg.Strict())); err != nil {
 		t.Logf("got bad bytes: %s", string(enc))
 		t.Fatal("failed to round trip object: ", err)
 	}
@@ -660,7 +907,7 @@ func TestConfigurability(t *testing.T) {
 			err = ls.UnmarshalDagJSON(strings.NewReader(bad))
 			if err == nil {
 				t.Fatal("expected error")
-			} else if err.Error() != "t.Arr: slice too large" {
+			} else if err.Error() != ".Arr: slice too large (offset 22)" {
 				t.Fatal("unexpected error", err)
 			}
 		})
@@ -699,7 +946,7 @@ func TestConfigurability(t *testing.T) {
 			err = ls.UnmarshalDagJSON(strings.NewReader(bad))
 			if err == nil {
 				t.Fatal("expected error")
-			} else if err.Error() != "t.Byts: byte array too large" {
+			} else if err.Error() != ".Byts: byte array too large (offset 34)" {
 				t.Fatal("unexpected error", err)
 			}
 		})
@@ -738,7 +985,7 @@ func TestConfigurability(t *testing.T) {
 			err = ls.UnmarshalDagJSON(strings.NewReader(bad))
 			if err == nil {
 				t.Fatal("expected error")
-			} else if err.Error() != "t.Str: string too long" {
+			} else if err.Error() != ".Str: string too long (offset 34)" {
 				t.Fatal("unexpected error", err)
 			}
 		})
@@ -810,3 +1057,282 @@ func TestUnmarshalExtraFields(t *testing.T) {
 		t.Fatal("encoding mismatch")
 	}
 }
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	dummyCid, _ := cid.Parse("bafkqaaa")
+	obj := &SimpleStructV2{
+		OldStr:    "oldstr",
+		NewStr:    "newstr",
+		NewBytes:  []byte("newbytes"),
+		OldNum:    10,
+		NewNum:    11,
+		NewPtr:    &dummyCid,
+		NewMap:    map[string]SimpleTypeOne{"bar": {Foo: "bar"}},
+		NewArray:  []SimpleTypeOne{{Value: 1}, {Value: 2}},
+		NewStruct: SimpleTypeOne{Binary: []byte("bin")},
+	}
+	var buf bytes.Buffer
+	if err := obj.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	enc := bytes.Clone(buf.Bytes())
+
+	var old SimpleStructV1Lossless
+	if err := old.UnmarshalDagJSON(bytes.NewReader(enc)); err != nil {
+		t.Fatal(err)
+	}
+	if old.OldStr != "oldstr" || old.OldNum != 10 {
+		t.Fatalf("known fields not decoded: %+v", old)
+	}
+	if len(old.Unknown) != 7 {
+		t.Fatalf("expected the 7 new fields to be kept, got %d", len(old.Unknown))
+	}
+
+	buf.Reset()
+	if err := old.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	// The new fields are merged back in canonical order, which a strict reader
+	// checks, and decode as they were.
+	var back SimpleStructV2
+	if err := back.UnmarshalDagJSON(jsg.NewDagJsonReader(bytes.NewReader(buf.Bytes()), jsg.Strict())); err != nil {
+		t.Fatalf("%s: %s", buf.Bytes(), err)
+	}
+	buf.Reset()
+	if err := back.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), enc) {
+		t.Fatalf("re-encoding lost data:\n%s\n%s", enc, buf.Bytes())
+	}
+
+	var links []cid.Cid
+	if err := old.ScanDagJSONLinks(func(c cid.Cid) { links = append(links, c) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || !links[0].Equals(dummyCid) {
+		t.Fatalf("expected the link in an unknown field to be found, got %v", links)
+	}
+
+	old.Unknown["OldStr"] = jsg.Deferred{Raw: []byte(`"shadow"`)}
+	if err := old.MarshalDagJSON(&buf); err == nil {
+		t.Fatal("expected an unknown field with the key of a known field to fail")
+	}
+}
+
+func TestStrictFields(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		into jsg.DagJsonUnmarshaler
+		err  string
+	}{
+		{`{"Name":"a","count":1}`, new(StrictFields), ""},
+		{`{"count":1,"Name":"a"}`, new(StrictFields), ""},
+		{`{"Name":"a","Other":1}`, new(StrictFields), `["Other"]: unknown field`},
+		{`{"Name":"a","count":1,"Name":"b"}`, new(StrictFields), `["Name"]: duplicate field`},
+		{`{"Name":"a","Other":1}`, new(StrictFieldsLossless), ""},
+		{`{"Other":1,"Name":"a","Other":2}`, new(StrictFieldsLossless), `["Other"]: duplicate field`},
+	} {
+		err := tc.into.UnmarshalDagJSON(strings.NewReader(tc.in))
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %s", tc.in, err)
+		case tc.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tc.err)):
+			t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestRequiredFields(t *testing.T) {
+	for _, tc := range []struct {
+		in  string
+		err string
+	}{
+		{`{"Name":"a","count":1}`, ""},
+		{`{"Name":"","count":0,"tags":[]}`, ""},
+		{`{"Name":"a","tags":[]}`, `missing required fields ["count"]`},
+		{`{}`, `missing required fields ["Name" "count"]`},
+	} {
+		var v RequiredFields
+		err := v.UnmarshalDagJSON(strings.NewReader(tc.in))
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %s", tc.in, err)
+		case tc.err != "" && (err == nil || !strings.HasPrefix(err.Error(), tc.err)):
+			t.Errorf("%s: expected error %q, got %v", tc.in, tc.err, err)
+		}
+	}
+}
+
+func TestDefaultFields(t *testing.T) {
+	var v DefaultFields
+	if err := v.UnmarshalDagJSON(strings.NewReader(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	expected := DefaultFields{Name: "anon", Retries: -3, Limit: 100, Enabled: true, Ratio: 0.5, Kind: "a=b"}
+	if v != expected {
+		t.Fatalf("expected defaults %+v, got %+v", expected, v)
+	}
+
+	// Fields equal to their default are omitted by omitempty, while the zero
+	// value is now written.
+	var buf bytes.Buffer
+	if err := v.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `{"enabled":true,"kind":"a=b","limit":100,"ratio":0.5}` {
+		t.Fatalf("unexpected encoding %s", buf.String())
+	}
+	v.Name, v.Retries = "", 0
+	buf.Reset()
+	if err := v.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back DefaultFields
+	if err := back.UnmarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if back != v {
+		t.Fatalf("expected %+v, got %+v", v, back)
+	}
+
+	var tv TupleWithDefaults
+	if err := tv.UnmarshalDagJSON(strings.NewReader(`[1]`)); err != nil {
+		t.Fatal(err)
+	}
+	if tv != (TupleWithDefaults{Int1: 1, Int2: 7, Str3: "three"}) {
+		t.Fatalf("unexpected tuple %+v", tv)
+	}
+	if err := tv.UnmarshalDagJSON(strings.NewReader(`[1,2]`)); err != nil {
+		t.Fatal(err)
+	}
+	if tv != (TupleWithDefaults{Int1: 1, Int2: 2, Str3: "three"}) {
+		t.Fatalf("unexpected tuple %+v", tv)
+	}
+}
+
+func TestOmitEmptyKinds(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (&OmitEmptyKinds{Labels: map[string]string{}}).MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `{"Kept":0}` {
+		t.Fatalf("expected empty fields to be omitted, got %s", buf.String())
+	}
+
+	c, _ := cid.Parse("bafkqaaa")
+	full := OmitEmptyKinds{
+		Count:   -1,
+		Ratio:   0.5,
+		Enabled: true,
+		Labels:  map[string]string{"a": "b"},
+		Link:    c,
+		Fixed:   [2]int16{0, 1},
+		Window:  Window{End: 1},
+	}
+	buf.Reset()
+	if err := full.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back OmitEmptyKinds
+	if err := back.UnmarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(full, back, cmp.Comparer(cid.Cid.Equals)) {
+		t.Fatalf("expected %+v, got %+v", full, back)
+	}
+}
+
+func TestFlattenedFields(t *testing.T) {
+	env := Envelope{
+		Header: Header{Version: 2, Sender: "alice"},
+		trace:  trace{TraceID: "t1", Hops: 9},
+		Body:   "hello",
+		Hops:   3,
+		Extra:  Footer{Signature: []byte{1}},
+		Stamp:  Stamp{At: 5},
+		Ignore: "dropped",
+	}
+	var buf bytes.Buffer
+	if err := env.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"Body":"hello","Hops":3,"Signature":{"/":{"bytes":"AQ"}},"TraceID":"t1","Version":2,"from":"alice","stamp":{"At":5}}`
+	if buf.String() != expected {
+		t.Fatalf("expected %s, got %s", expected, buf.String())
+	}
+	var back Envelope
+	if err := back.UnmarshalDagJSON(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	env.trace.Hops, env.Ignore = 0, ""
+	if !cmp.Equal(env, back, cmp.AllowUnexported(Envelope{})) {
+		t.Fatalf("expected %+v, got %+v", env, back)
+	}
+
+	tuple := TupleEnvelope{Header: Header{Version: 1, Sender: "bob"}, Body: "hi"}
+	buf.Reset()
+	if err := tuple.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != `[1,"bob","hi"]` {
+		t.Fatalf("unexpected tuple encoding %s", buf.String())
+	}
+	var tback TupleEnvelope
+	if err := tback.UnmarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if tback != tuple {
+		t.Fatalf("expected %+v, got %+v", tuple, tback)
+	}
+}
+
+func TestGenericInstantiations(t *testing.T) {
+	next := "p2"
+	v := GenericFields{
+		Ones:  Page[SimpleTypeOne]{Items: []SimpleTypeOne{{Foo: "a"}}, Next: &next},
+		Sum:   &Pair[string, *big.Int]{Key: "total", Value: big.NewInt(-12)},
+		Pages: []Page[int64]{{Items: []int64{1, 2}}, {}},
+	}
+	var buf bytes.Buffer
+	if err := v.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var back GenericFields
+	if err := back.UnmarshalDagJSON(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(v, back, cmp.Comparer(func(a, b *big.Int) bool { return a.Cmp(b) == 0 })) {
+		t.Fatalf("expected %+v, got %+v", v, back)
+	}
+
+	// There is no codec for instantiations that weren't generated.
+	if err := (&Page[string]{}).MarshalDagJSON(&buf); err == nil || !strings.Contains(err.Error(), "Page[string]") {
+		t.Fatalf("expected an error for an instantiation without a codec, got %v", err)
+	}
+}
+
+func TestDeclaredRepresentations(t *testing.T) {
+	for _, tc := range []struct {
+		val      jsg.DagJsonMarshaler
+		into     jsg.DagJsonUnmarshaler
+		expected string
+	}{
+		{&MixedMap{Tuple: MixedTuple{Count: 1, Label: "a"}, Tuples: []MixedTuple{{Count: 2}}}, new(MixedMap), `{"Tuple":[1,"a"],"Tuples":[[2,""]]}`},
+		{&MixedDefault{Inner: &MixedTuple{Count: 3, Label: "b"}}, new(MixedDefault), `{"Inner":[3,"b"]}`},
+	} {
+		var buf bytes.Buffer
+		if err := tc.val.MarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != tc.expected {
+			t.Fatalf("expected %s, got %s", tc.expected, buf.String())
+		}
+		if err := tc.into.UnmarshalDagJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(tc.val, tc.into, cmp.AllowUnexported(MixedTuple{}, MixedMap{})) {
+			t.Fatalf("expected %+v, got %+v", tc.val, tc.into)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package testing
 
 import (
+	"fmt"
 	"math/big"
 	"math/rand"
 	"reflect"
@@ -14,6 +15,7 @@ const Thingc = 3
 
 type NamedNumber uint64
 type NamedString string
+type NamedFloat float64
 
 type SignedArray struct {
 	Signed []uint64
@@ -64,6 +66,22 @@ type SimpleStructV1 struct {
 	OldCidPtrArray []*cid.Cid
 }
 
+// SimpleStructV1Lossless is SimpleStructV1 keeping the fields it doesn't know
+// about.
+type SimpleStructV1Lossless struct {
+	OldStr         string
+	OldBytes       []byte
+	OldNum         uint64
+	OldPtr         *cid.Cid
+	OldMap         map[string]SimpleTypeOne
+	OldArray       []SimpleTypeOne
+	OldStruct      SimpleTypeOne
+	OldCidArray    []cid.Cid
+	OldCidPtrArray []*cid.Cid
+
+	Unknown map[string]jsg.Deferred `dagjsongen:"unknown"`
+}
+
 type SimpleStructV2 struct {
 	OldStr string
 	NewStr string
@@ -206,7 +224,8 @@ func (ls LongString) Generate(rand *rand.Rand, size int) reflect.Value {
 }
 
 type BigIntContainer struct {
-	Int *big.Int
+	Int     *big.Int
+	Bounded *big.Int `dagjsongen:"maxlen=10"`
 }
 
 type StringPtrSlices struct {
@@ -220,3 +239,275 @@ type TupleWithOptionalFields struct {
 	Int3  int64 `dagjsongen:"optional"`
 	Int4  int64 `dagjsongen:"optional"`
 }
+
+type FloatContainer struct {
+	F32     float32
+	F64     float64
+	F32Ptr  *float32
+	F64Ptr  *float64
+	Named   NamedFloat
+	Floats  []float64
+	Float32 []float32
+	Fixed   [3]float64
+	Map     map[string]float64
+}
+
+type IntWidths struct {
+	Int      int
+	Int8     int8
+	Int16    int16
+	Int32    int32
+	Uint     uint
+	Uint8    uint8
+	Uint16   uint16
+	Uint32   uint32
+	IntPtr   *int
+	Int8Ptr  *int8
+	UintPtr  *uint
+	Uint8Ptr *uint8
+	Int32s   []int32
+	Uint16s  []uint16
+	Int8Ptrs []*int8
+	Fixed    [2]int16
+	Map      map[string]uint32
+	MapInt8  map[string]int8
+}
+
+// TextKey is a map key that encodes itself as "major.minor".
+type TextKey struct {
+	Major uint16
+	Minor uint16
+}
+
+func (k TextKey) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d", k.Major, k.Minor)), nil
+}
+
+func (k *TextKey) UnmarshalText(b []byte) error {
+	_, err := fmt.Sscanf(string(b), "%d.%d", &k.Major, &k.Minor)
+	return err
+}
+
+type MapKeys struct {
+	Int64s  map[int64]string
+	Uint64s map[uint64]string
+	Int8s   map[int8]string
+	Ints    map[int]string
+	Named   map[NamedNumber]string
+	Strings map[NamedString]string
+	Text    map[TextKey]string
+}
+
+type MapValues struct {
+	Uints   map[string]uint64
+	Bytes   map[string][]byte
+	Cids    map[string]cid.Cid
+	CidPtrs map[string]*cid.Cid
+	Structs map[string][]SimpleTypeOne
+	Nested  map[string]map[string]string
+	Deep    map[string]map[int64][]string
+	Bools   map[string]bool
+	IntPtrs map[string]*int64
+
+	IntCids  map[int64]cid.Cid
+	TextCids map[TextKey]*cid.Cid
+}
+
+type Circle struct {
+	Radius uint64
+}
+
+type Square struct {
+	Side  uint64
+	Color string
+}
+
+type KeyedUnion struct {
+	_      struct{} `dagjsongen:"union=keyed"`
+	Circle *Circle  `dagjsongen:"circle"`
+	Square *Square  `dagjsongen:"square"`
+	Label  *string  `dagjsongen:"label"`
+}
+
+type EnvelopeUnion struct {
+	_      struct{} `dagjsongen:"union=envelope,discriminantkey=type,contentkey=content"`
+	Circle *Circle  `dagjsongen:"circle"`
+	Square *Square  `dagjsongen:"square"`
+	Count  *uint64  `dagjsongen:"count"`
+}
+
+type InlineUnion struct {
+	_      struct{} `dagjsongen:"union=inline,discriminantkey=shape"`
+	Circle *Circle  `dagjsongen:"circle"`
+	Square *Square  `dagjsongen:"square"`
+}
+
+type KindedUnion struct {
+	_      struct{} `dagjsongen:"union=kinded"`
+	String *string
+	Int    *int64
+	Float  *float64
+	Bool   *bool
+	Bytes  *[]byte
+	Link   *cid.Cid
+	List   *[]string
+	Map    *Circle `dagjsongen:"kind=map"`
+}
+
+type UnionContainer struct {
+	Keyed    KeyedUnion
+	Envelope *EnvelopeUnion
+	Inline   []InlineUnion
+	Kinded   map[string]KindedUnion
+}
+
+type NodeContainer struct {
+	Any  jsg.Node
+	Ptr  *jsg.Node
+	List []jsg.Node
+	Map  map[string]jsg.Node
+}
+
+type StrictFields struct {
+	_     struct{} `dagjsongen:"strictfields"`
+	Name  string
+	Count uint64 `dagjsongen:"count"`
+}
+
+type StrictFieldsLossless struct {
+	_     struct{} `dagjsongen:"strictfields"`
+	Name  string
+	Extra map[string]jsg.Deferred `dagjsongen:"unknown"`
+}
+
+// StrictInlineUnion has a member that rejects unknown fields, which must not
+// see the discriminant.
+type StrictInlineUnion struct {
+	_      struct{}      `dagjsongen:"union=inline,discriminantkey=kind"`
+	Strict *StrictFields `dagjsongen:"strict"`
+}
+
+// LosslessInlineUnion has a member that keeps unknown fields, which must not
+// include the discriminant.
+type LosslessInlineUnion struct {
+	_        struct{}              `dagjsongen:"union=inline,discriminantkey=kind"`
+	Lossless *StrictFieldsLossless `dagjsongen:"lossless"`
+}
+
+// UnionHolder embeds a union, which keeps its own encoding rather than being
+// flattened.
+type UnionHolder struct {
+	KeyedUnion
+	Name string
+}
+
+type RequiredFields struct {
+	Name  string   `dagjsongen:"required"`
+	Count uint64   `dagjsongen:"count,required"`
+	Tags  []string `dagjsongen:"tags"`
+}
+
+type DefaultFields struct {
+	Name    string      `dagjsongen:"name,default=anon,omitempty"`
+	Retries int8        `dagjsongen:"retries,default=-3,omitempty"`
+	Limit   uint32      `dagjsongen:"limit,default=100"`
+	Enabled bool        `dagjsongen:"enabled,default=true"`
+	Ratio   float64     `dagjsongen:"ratio,default=0.5"`
+	Kind    NamedString `dagjsongen:"kind,default=a=b"`
+}
+
+type TupleWithDefaults struct {
+	Int1 int64
+	Int2 int64  `dagjsongen:"optional,default=7"`
+	Str3 string `dagjsongen:"optional,default=three"`
+}
+
+type OmitEmptyKinds struct {
+	Count   int64             `dagjsongen:"omitempty"`
+	Ratio   float32           `dagjsongen:"omitempty"`
+	Enabled bool              `dagjsongen:"omitempty"`
+	Labels  map[string]string `dagjsongen:"omitempty"`
+	Link    cid.Cid           `dagjsongen:"omitempty"`
+	Fixed   [2]int16          `dagjsongen:"omitempty"`
+	Window  Window            `dagjsongen:"omitempty"`
+	Kept    uint64
+}
+
+type Window struct {
+	Start uint64
+	End   uint64
+}
+
+func (w *Window) IsZero() bool {
+	return w.Start == 0 && w.End == 0
+}
+
+type Header struct {
+	Version uint64
+	Sender  string `dagjsongen:"from"`
+}
+
+type trace struct {
+	TraceID string
+	Hops    int64
+}
+
+type Footer struct {
+	Signature []byte
+}
+
+type Stamp struct {
+	At int64
+}
+
+// Envelope has the fields of Header, trace and Footer flattened into it, with
+// its own Hops shadowing that of trace, and Stamp as an ordinary field.
+type Envelope struct {
+	Header
+	trace
+	Body   string
+	Hops   uint8
+	Extra  Footer `dagjsongen:"flatten"`
+	Stamp  `dagjsongen:"stamp"`
+	Ignore string `dagjsongen:"-"`
+}
+
+type TupleEnvelope struct {
+	Header
+	Body string
+}
+
+// Page and Pair are generic, with codecs generated for each instantiation.
+type Page[T any] struct {
+	Items []T
+	Next  *string
+}
+
+type Pair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+type GenericFields struct {
+	Ones  Page[SimpleTypeOne]
+	Sum   *Pair[string, *big.Int]
+	Pages []Page[int64]
+}
+
+// MixedTuple and MixedMap declare their representation, and are generated
+// into one file with MixedDefault, which is map encoded.
+type MixedTuple struct {
+	_     struct{} `dagjsongen:"tuple"`
+	Count int64
+	Label string
+}
+
+type MixedMap struct {
+	_      struct{} `dagjsongen:"map"`
+	Tuple  MixedTuple
+	Tuples []MixedTuple
+}
+
+type MixedDefault struct {
+	Inner *MixedTuple
+}
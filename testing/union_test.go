@@ -0,0 +1,223 @@
+package testing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	jsg "github.com/alanshaw/dag-json-gen"
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// testUnionRoundtrip checks the encoding of a union against golden and that
+// decoding it and encoding again produces the same bytes. Unions hold a blank
+// marker field, so they are compared by encoding rather than with cmp.
+func testUnionRoundtrip(t *testing.T, val jsg.DagJsonMarshaler, out jsg.DagJsonUnmarshaler, golden string) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := val.MarshalDagJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != golden {
+		t.Fatalf("encoding mismatch:\n%s\nexpected:\n%s", buf.String(), golden)
+	}
+	if err := out.UnmarshalDagJSON(strings.NewReader(golden)); err != nil {
+		t.Fatal(err)
+	}
+	var nbuf bytes.Buffer
+	if err := out.(jsg.DagJsonMarshaler).MarshalDagJSON(&nbuf); err != nil {
+		t.Fatal(err)
+	}
+	if nbuf.String() != golden {
+		t.Fatalf("re-encoding mismatch:\n%s\nexpected:\n%s", nbuf.String(), golden)
+	}
+}
+
+func TestKeyedUnion(t *testing.T) {
+	for _, tc := range []struct {
+		val    KeyedUnion
+		golden string
+	}{
+		{KeyedUnion{Circle: &Circle{Radius: 3}}, `{"circle":{"Radius":3}}`},
+		{KeyedUnion{Square: &Square{Side: 2, Color: "red"}}, `{"square":{"Color":"red","Side":2}}`},
+		{KeyedUnion{Label: ptr("hi")}, `{"label":"hi"}`},
+	} {
+		var out KeyedUnion
+		testUnionRoundtrip(t, &tc.val, &out, tc.golden)
+	}
+}
+
+func TestEnvelopeUnion(t *testing.T) {
+	for _, tc := range []struct {
+		val    EnvelopeUnion
+		golden string
+	}{
+		{EnvelopeUnion{Circle: &Circle{Radius: 3}}, `{"content":{"Radius":3},"type":"circle"}`},
+		{EnvelopeUnion{Square: &Square{Side: 2}}, `{"content":{"Color":"","Side":2},"type":"square"}`},
+		{EnvelopeUnion{Count: ptr(uint64(7))}, `{"content":7,"type":"count"}`},
+	} {
+		var out EnvelopeUnion
+		testUnionRoundtrip(t, &tc.val, &out, tc.golden)
+	}
+
+	t.Run("discriminant first", func(t *testing.T) {
+		var out EnvelopeUnion
+		if err := out.UnmarshalDagJSON(strings.NewReader(`{"type":"count","content":7}`)); err != nil {
+			t.Fatal(err)
+		}
+		if out.Count == nil || *out.Count != 7 {
+			t.Fatalf("unexpected decoded value: %+v", out)
+		}
+	})
+}
+
+func TestInlineUnion(t *testing.T) {
+	for _, tc := range []struct {
+		val    InlineUnion
+		golden string
+	}{
+		{InlineUnion{Circle: &Circle{Radius: 3}}, `{"Radius":3,"shape":"circle"}`},
+		{InlineUnion{Square: &Square{Side: 2, Color: "red"}}, `{"Color":"red","Side":2,"shape":"square"}`},
+	} {
+		var out InlineUnion
+		testUnionRoundtrip(t, &tc.val, &out, tc.golden)
+	}
+}
+
+func TestStrictInlineUnion(t *testing.T) {
+	var out StrictInlineUnion
+	testUnionRoundtrip(t, &StrictInlineUnion{Strict: &StrictFields{Name: "a", Count: 1}}, &out, `{"Name":"a","count":1,"kind":"strict"}`)
+
+	if err := out.UnmarshalDagJSON(strings.NewReader(`{"Name":"a","Other":1,"kind":"strict"}`)); err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Fatalf("expected the member to reject other unknown fields, got %v", err)
+	}
+}
+
+func TestLosslessInlineUnion(t *testing.T) {
+	golden := `{"Name":"a","Other":1,"kind":"lossless"}`
+	var out LosslessInlineUnion
+	if err := out.UnmarshalDagJSON(strings.NewReader(golden)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := out.Lossless.Extra["kind"]; ok || len(out.Lossless.Extra) != 1 {
+		t.Fatalf("expected only the member's unknown fields to be kept, got %v", out.Lossless.Extra)
+	}
+	var again LosslessInlineUnion
+	testUnionRoundtrip(t, &out, &again, golden)
+}
+
+func TestEmbeddedUnion(t *testing.T) {
+	var out UnionHolder
+	val := UnionHolder{KeyedUnion: KeyedUnion{Circle: &Circle{Radius: 1}}, Name: "a"}
+	testUnionRoundtrip(t, &val, &out, `{"KeyedUnion":{"circle":{"Radius":1}},"Name":"a"}`)
+}
+
+func TestKindedUnion(t *testing.T) {
+	link, _ := cid.Parse("bafkqaaa")
+	for _, tc := range []struct {
+		val    KindedUnion
+		golden string
+	}{
+		{KindedUnion{String: ptr("hi")}, `"hi"`},
+		{KindedUnion{Int: ptr(int64(-3))}, `-3`},
+		{KindedUnion{Float: ptr(1.5)}, `1.5`},
+		{KindedUnion{Float: ptr(2.0)}, `2.0`},
+		{KindedUnion{Bool: ptr(true)}, `true`},
+		{KindedUnion{Bytes: ptr([]byte{1, 2, 3})}, `{"/":{"bytes":"AQID"}}`},
+		{KindedUnion{Link: &link}, `{"/":"bafkqaaa"}`},
+		{KindedUnion{List: ptr([]string{"a", "b"})}, `["a","b"]`},
+		{KindedUnion{Map: &Circle{Radius: 1}}, `{"Radius":1}`},
+	} {
+		var out KindedUnion
+		testUnionRoundtrip(t, &tc.val, &out, tc.golden)
+	}
+}
+
+func TestUnionReaderOptions(t *testing.T) {
+	digest, err := mh.Sum([]byte("union"), mh.SHA2_512, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := cid.NewCidV1(cid.Raw, digest)
+	base36, _ := link.StringOfBase('k')
+
+	for _, tc := range []struct {
+		name string
+		in   string
+		opt  jsg.ReaderOption
+	}{
+		{"multihash size", `{"/":"` + link.String() + `"}`, jsg.MaxMultihashSize(32)},
+		{"strict link form", `{"/":"` + base36 + `"}`, jsg.Strict()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var out KindedUnion
+			if err := out.UnmarshalDagJSON(strings.NewReader(tc.in)); err != nil {
+				t.Fatalf("expected the link to decode without options: %s", err)
+			}
+			var nd jsg.Node
+			if err := nd.UnmarshalDagJSON(jsg.NewDagJsonReader(strings.NewReader(tc.in), tc.opt)); err == nil {
+				t.Fatal("expected the reader to reject the link")
+			}
+			out = KindedUnion{}
+			if err := out.UnmarshalDagJSON(jsg.NewDagJsonReader(strings.NewReader(tc.in), tc.opt)); err == nil {
+				t.Fatal("expected the union member to be decoded with the reader's options")
+			}
+		})
+	}
+}
+
+func TestUnionContainer(t *testing.T) {
+	val := UnionContainer{
+		Keyed:    KeyedUnion{Label: ptr("a")},
+		Envelope: &EnvelopeUnion{Count: ptr(uint64(1))},
+		Inline:   []InlineUnion{{Circle: &Circle{Radius: 1}}, {Square: &Square{Side: 2}}},
+		Kinded:   map[string]KindedUnion{"n": {Int: ptr(int64(1))}, "s": {String: ptr("x")}},
+	}
+	golden := `{"Envelope":{"content":1,"type":"count"},` +
+		`"Inline":[{"Radius":1,"shape":"circle"},{"Color":"","Side":2,"shape":"square"}],` +
+		`"Keyed":{"label":"a"},` +
+		`"Kinded":{"n":1,"s":"x"}}`
+	var out UnionContainer
+	testUnionRoundtrip(t, &val, &out, golden)
+}
+
+func TestUnionErrors(t *testing.T) {
+	t.Run("no member", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := (&KeyedUnion{}).MarshalDagJSON(&buf); err == nil {
+			t.Fatal("expected an error marshaling a union with no member set")
+		}
+	})
+
+	t.Run("many members", func(t *testing.T) {
+		var buf bytes.Buffer
+		u := KeyedUnion{Circle: &Circle{}, Label: ptr("")}
+		if err := u.MarshalDagJSON(&buf); err == nil {
+			t.Fatal("expected an error marshaling a union with two members set")
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		out  jsg.DagJsonUnmarshaler
+		in   string
+	}{
+		{"keyed unknown", &KeyedUnion{}, `{"triangle":{}}`},
+		{"keyed extra entry", &KeyedUnion{}, `{"label":"a","square":{}}`},
+		{"envelope unknown", &EnvelopeUnion{}, `{"content":1,"type":"triangle"}`},
+		{"envelope missing content", &EnvelopeUnion{}, `{"type":"count"}`},
+		{"envelope extra key", &EnvelopeUnion{}, `{"content":1,"extra":1,"type":"count"}`},
+		{"inline missing discriminant", &InlineUnion{}, `{"Radius":1}`},
+		{"inline duplicate discriminant", &InlineUnion{}, `{"shape":"circle","shape":"square","Side":1}`},
+		{"inline not a map", &InlineUnion{}, `[1]`},
+		{"kinded no member", &KindedUnion{}, `null`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.out.UnmarshalDagJSON(strings.NewReader(tc.in)); err == nil {
+				t.Fatalf("expected an error decoding %s", tc.in)
+			}
+		})
+	}
+}
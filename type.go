@@ -0,0 +1,93 @@
+package typegen
+
+import (
+	"reflect"
+)
+
+// Type is the description of a Go type that the generator works from. It
+// mirrors the parts of reflect.Type that are needed, so that type information
+// can come either from values at runtime (see ReflectType) or from source
+// code (see LoadPackage).
+type Type interface {
+	Kind() reflect.Kind
+	// Name is the name of a defined type within its package, or empty for
+	// unnamed types.
+	Name() string
+	// PkgPath is the import path of the package defining a named type, or
+	// empty for built-in and unnamed types.
+	PkgPath() string
+	// String is a representation of the type using package names, as
+	// returned by reflect.Type.String.
+	String() string
+
+	Elem() Type
+	Key() Type
+	Len() int
+	Bits() int
+	NumField() int
+	Field(i int) StructField
+
+	// textMethods reports whether the type (or a pointer to it) implements
+	// encoding.TextMarshaler, and whether a pointer to it implements
+	// encoding.TextUnmarshaler.
+	textMethods() (marshaler, unmarshaler bool)
+	// hasIsZero reports whether the type (or a pointer to it) has an
+	// IsZero() bool method.
+	hasIsZero() bool
+}
+
+// StructField is a single field of a struct Type.
+type StructField struct {
+	Name string
+	Type Type
+	Tag  reflect.StructTag
+	// Anonymous is true for embedded fields.
+	Anonymous bool
+}
+
+// sameType reports whether a and b are the same named type.
+func sameType(a, b Type) bool {
+	return a.Name() != "" && a.Name() == b.Name() && a.PkgPath() == b.PkgPath()
+}
+
+type reflectType struct {
+	reflect.Type
+}
+
+// ReflectType returns the Type for a type known at runtime.
+func ReflectType(t reflect.Type) Type {
+	return reflectType{t}
+}
+
+// reflectTypeOf returns the reflect.Type that t was created from by
+// ReflectType, or nil if it was loaded from source.
+func reflectTypeOf(t Type) reflect.Type {
+	if rt, ok := t.(reflectType); ok {
+		return rt.Type
+	}
+	return nil
+}
+
+func (t reflectType) Elem() Type {
+	return reflectType{t.Type.Elem()}
+}
+
+func (t reflectType) Key() Type {
+	return reflectType{t.Type.Key()}
+}
+
+func (t reflectType) Field(i int) StructField {
+	f := t.Type.Field(i)
+	return StructField{Name: f.Name, Type: reflectType{f.Type}, Tag: f.Tag, Anonymous: f.Anonymous}
+}
+
+var isZeroerType = reflect.TypeOf((*interface{ IsZero() bool })(nil)).Elem()
+
+func (t reflectType) hasIsZero() bool {
+	return reflect.PointerTo(t.Type).Implements(isZeroerType)
+}
+
+func (t reflectType) textMethods() (bool, bool) {
+	pt := reflect.PointerTo(t.Type)
+	return t.Implements(textMarshalerType) || pt.Implements(textMarshalerType), pt.Implements(textUnmarshalerType)
+}
@@ -0,0 +1,234 @@
+package typegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// ReadKinded reads the next value in full and returns its data model kind,
+// allowing a kinded union to pick the member to decode it into, along with a
+// reader for the value. The reader has the options of jr and reports offsets
+// in the input of jr, and the caller should call Release(nil) when done with
+// it.
+func ReadKinded(jr *DagJsonReader) (Kind, *DagJsonReader, error) {
+	start := jr.valueOffset()
+	d := new(Deferred)
+	if err := d.UnmarshalDagJSON(jr); err != nil {
+		return 0, nil, err
+	}
+	kind, err := d.Kind()
+	if err != nil {
+		return 0, nil, err
+	}
+	mr := jr.Sub(d.Raw)
+	mr.base = start
+	return kind, mr, nil
+}
+
+// ReadEnvelopeUnion reads a union in envelope representation: a map with
+// exactly two entries, the discriminant and the content. The content is
+// returned as a reader, as with ReadKinded, since it may precede the
+// discriminant.
+func ReadEnvelopeUnion(jr *DagJsonReader, discriminantKey, contentKey string) (string, *DagJsonReader, error) {
+	if err := jr.ReadObjectOpen(); err != nil {
+		return "", nil, err
+	}
+
+	var discriminant *string
+	var content *Deferred
+	var start int64
+	for close := false; !close; {
+		k, err := jr.ReadString(MaxLength)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := jr.ReadObjectColon(); err != nil {
+			return "", nil, err
+		}
+		switch k {
+		case discriminantKey:
+			if discriminant != nil {
+				return "", nil, jr.errorf("duplicate union discriminant key %q", k)
+			}
+			s, err := jr.ReadString(MaxLength)
+			if err != nil {
+				return "", nil, err
+			}
+			discriminant = &s
+		case contentKey:
+			if content != nil {
+				return "", nil, jr.errorf("duplicate union content key %q", k)
+			}
+			start = jr.valueOffset()
+			content = new(Deferred)
+			if err := content.UnmarshalDagJSON(jr); err != nil {
+				return "", nil, err
+			}
+		default:
+			return "", nil, jr.errorf("unexpected key %q in envelope union", k)
+		}
+		close, err = jr.ReadObjectCloseOrComma()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if discriminant == nil {
+		return "", nil, jr.errorf("missing union discriminant key %q", discriminantKey)
+	}
+	if content == nil {
+		return "", nil, jr.errorf("missing union content key %q", contentKey)
+	}
+	mr := jr.Sub(content.Raw)
+	mr.base = start
+	return *discriminant, mr, nil
+}
+
+// ReadInlineUnion reads a union in inline representation: a map holding the
+// member's own fields alongside the discriminant. The map is returned as a
+// reader, as with ReadKinded, without the discriminant entry, so the member can
+// be decoded from it as if the discriminant wasn't there.
+func ReadInlineUnion(jr *DagJsonReader, discriminantKey string) (string, *DagJsonReader, error) {
+	start := jr.valueOffset()
+	if err := jr.ReadObjectOpen(); err != nil {
+		return "", nil, err
+	}
+
+	var discriminant *string
+	// The discriminant entry is left out of the member at skipAt, and was
+	// skipLen bytes long in the input.
+	var skipAt, skipLen int64
+	var buf bytes.Buffer
+	w := NewLimitWriter(&buf, ByteArrayMaxLen)
+	if _, err := w.Write([]byte{'{'}); err != nil {
+		return "", nil, err
+	}
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return "", nil, err
+	}
+	if close {
+		if err := jr.ReadObjectClose(); err != nil {
+			return "", nil, err
+		}
+	}
+	for !close {
+		keyStart := jr.valueOffset()
+		k, err := jr.ReadString(MaxLength)
+		if err != nil {
+			return "", nil, err
+		}
+		if err := jr.ReadObjectColon(); err != nil {
+			return "", nil, err
+		}
+		if k == discriminantKey {
+			if discriminant != nil {
+				return "", nil, jr.errorf("duplicate union discriminant key %q", k)
+			}
+			s, err := jr.ReadString(MaxLength)
+			if err != nil {
+				return "", nil, err
+			}
+			discriminant = &s
+			skipAt, skipLen = int64(buf.Len()), jr.Offset()-keyStart
+		} else {
+			entry := appendString(nil, k)
+			if buf.Len() > 1 {
+				entry = append([]byte{','}, entry...)
+			}
+			if _, err := w.Write(append(entry, ':')); err != nil {
+				return "", nil, jr.fail(err)
+			}
+			if err := parse(jr, w); err != nil {
+				return "", nil, err
+			}
+		}
+		close, err = jr.ReadObjectCloseOrComma()
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if _, err := w.Write([]byte{'}'}); err != nil {
+		return "", nil, jr.fail(err)
+	}
+
+	if discriminant == nil {
+		return "", nil, jr.errorf("missing union discriminant key %q", discriminantKey)
+	}
+	if buf.Len() > 2 {
+		// The comma separating the discriminant from the other entries.
+		skipLen++
+	}
+	mr := jr.Sub(buf.Bytes())
+	mr.base, mr.skipAt, mr.skipLen = start, skipAt, skipLen
+	return *discriminant, mr, nil
+}
+
+// WriteInlineUnion writes a union in inline representation. The member has
+// already been encoded as a map into raw, and the discriminant entry is
+// merged into it, keeping the entries sorted.
+func WriteInlineUnion(w *DagJsonWriter, discriminantKey, discriminant string, raw []byte) error {
+	type entry struct {
+		key   string
+		value []byte
+	}
+
+	entries := []entry{{discriminantKey, appendString(nil, discriminant)}}
+
+	jr := NewDagJsonReader(bytes.NewReader(raw))
+	defer jr.Release(nil)
+	if err := jr.ReadObjectOpen(); err != nil {
+		return fmt.Errorf("inline union member must be a map: %w", err)
+	}
+	close, err := jr.PeekObjectClose()
+	if err != nil {
+		return err
+	}
+	for !close {
+		k, err := jr.ReadString(MaxLength)
+		if err != nil {
+			return err
+		}
+		if k == discriminantKey {
+			return fmt.Errorf("inline union member has a field named after the discriminant key %q", k)
+		}
+		if err := jr.ReadObjectColon(); err != nil {
+			return err
+		}
+		var v Deferred
+		if err := v.UnmarshalDagJSON(jr); err != nil {
+			return err
+		}
+		entries = append(entries, entry{k, v.Raw})
+		close, err = jr.ReadObjectCloseOrComma()
+		if err != nil {
+			return err
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	if err := w.WriteObjectOpen(); err != nil {
+		return err
+	}
+	for i, e := range entries {
+		if i > 0 {
+			if err := w.WriteComma(); err != nil {
+				return err
+			}
+		}
+		if err := w.WriteString(e.key); err != nil {
+			return err
+		}
+		if err := w.WriteObjectColon(); err != nil {
+			return err
+		}
+		if _, err := w.Write(e.value); err != nil {
+			return err
+		}
+	}
+	return w.WriteObjectClose()
+}
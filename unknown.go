@@ -0,0 +1,71 @@
+package typegen
+
+import (
+	"fmt"
+	"sort"
+)
+
+// UnknownFields writes the entries of a struct's catch-all map of unknown
+// fields in among its known fields, so that the encoded map as a whole stays
+// in canonical key order. Generated code calls WriteBefore ahead of each known
+// field, in key order, and WriteRest after the last.
+type UnknownFields struct {
+	m    map[string]Deferred
+	keys []string
+}
+
+// NewUnknownFields sorts the keys of m, the unknown fields of a struct.
+func NewUnknownFields(m map[string]Deferred) UnknownFields {
+	u := UnknownFields{m: m}
+	if len(m) == 0 {
+		return u
+	}
+	u.keys = make([]string, 0, len(m))
+	for k := range m {
+		u.keys = append(u.keys, k)
+	}
+	sort.Strings(u.keys)
+	return u
+}
+
+// WriteBefore writes the entries whose keys sort before key, preceded by a
+// comma unless *written is zero, and adds them to *written. It fails if an
+// unknown field has the key of the known field about to be written.
+func (u *UnknownFields) WriteBefore(jw *DagJsonWriter, key string, written *int) error {
+	if err := u.write(jw, key, true, written); err != nil {
+		return err
+	}
+	if len(u.keys) > 0 && u.keys[0] == key {
+		return fmt.Errorf("unknown field %q has the key of a known field", key)
+	}
+	return nil
+}
+
+// WriteRest writes the remaining entries, like WriteBefore.
+func (u *UnknownFields) WriteRest(jw *DagJsonWriter, written *int) error {
+	return u.write(jw, "", false, written)
+}
+
+func (u *UnknownFields) write(jw *DagJsonWriter, key string, bounded bool, written *int) error {
+	for len(u.keys) > 0 && (!bounded || u.keys[0] < key) {
+		k := u.keys[0]
+		u.keys = u.keys[1:]
+		if *written > 0 {
+			if err := jw.WriteComma(); err != nil {
+				return err
+			}
+		}
+		if err := jw.WriteString(k); err != nil {
+			return err
+		}
+		if err := jw.WriteObjectColon(); err != nil {
+			return err
+		}
+		v := u.m[k]
+		if err := v.MarshalDagJSON(jw); err != nil {
+			return fmt.Errorf("unknown field %q: %w", k, err)
+		}
+		*written++
+	}
+	return nil
+}
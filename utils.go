@@ -4,6 +4,8 @@ import (
 	"io"
 	"reflect"
 	"sort"
+
+	cid "github.com/ipfs/go-cid"
 )
 
 type DagJsonUnmarshaler interface {
@@ -14,10 +16,20 @@ type DagJsonMarshaler interface {
 	MarshalDagJSON(io.Writer) error
 }
 
+// DagJsonLinkScanner is implemented by types that can list the links they
+// hold without being encoded first.
+type DagJsonLinkScanner interface {
+	ScanDagJSONLinks(cb func(cid.Cid)) error
+}
+
 // sort type example objects on name of type
 func sortTypeNames(obs []any) []any {
 	temp := make([]tnAny, len(obs))
 	for i, ob := range obs {
+		if gti, ok := ob.(*GenTypeInfo); ok {
+			temp[i] = tnAny{gti.Name, ob}
+			continue
+		}
 		v := reflect.ValueOf(ob)
 		if v.Kind() == reflect.Pointer {
 			v = v.Elem()
@@ -1,7 +1,11 @@
 package typegen
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -10,11 +14,283 @@ func TestDeferredMaxLengthSingle(t *testing.T) {
 	data := fmt.Sprintf("%q", strings.Repeat("0", ByteArrayMaxLen+1))
 	var deferred Deferred
 	err := deferred.UnmarshalDagJSON(strings.NewReader(data))
-	if err != ErrLimitExceeded {
+	if !errors.Is(err, ErrLimitExceeded) {
 		t.Fatal("deferred: allowed more than the maximum allocation supported")
 	}
 }
 
+func TestDeferredKind(t *testing.T) {
+	for _, tc := range []struct {
+		raw  string
+		kind Kind
+	}{
+		{`null`, KindNull},
+		{`false`, KindBool},
+		{`-12`, KindInt},
+		{`1.5`, KindFloat},
+		{`1e+21`, KindFloat},
+		{`"x"`, KindString},
+		{`[]`, KindList},
+		{`{}`, KindMap},
+		{`{"a":1}`, KindMap},
+		{`{"/":"bafkqaaa"}`, KindLink},
+		{`{"/":"bafkqaaa","a":1}`, KindMap},
+		{`{"/":{"bytes":"AQID"}}`, KindBytes},
+		{`{"/":{"bytes":"AQID","a":1}}`, KindMap},
+		{`{"/":{}}`, KindMap},
+		{`{"/":1}`, KindMap},
+	} {
+		d := Deferred{Raw: []byte(tc.raw)}
+		kind, err := d.Kind()
+		if err != nil {
+			t.Fatalf("%s: %s", tc.raw, err)
+		}
+		if kind != tc.kind {
+			t.Errorf("%s: expected kind %s, got %s", tc.raw, tc.kind, kind)
+		}
+	}
+}
+
+type unionCircle struct{}
+
+func TestParseUnionTypeInfo(t *testing.T) {
+	type noMarker struct {
+		A *string
+	}
+	info, err := ParseTypeInfo(noMarker{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Union != "" {
+		t.Fatalf("expected a plain struct, got union %q", info.Union)
+	}
+
+	for _, tc := range []struct {
+		name string
+		typ  any
+	}{
+		{"unknown representation", struct {
+			_ struct{} `dagjsongen:"union=tagged"`
+			A *string
+		}{}},
+		{"non-pointer member", struct {
+			_ struct{} `dagjsongen:"union=keyed"`
+			A string
+		}{}},
+		{"duplicate discriminant", struct {
+			_ struct{} `dagjsongen:"union=keyed"`
+			A *string  `dagjsongen:"x"`
+			B *uint64  `dagjsongen:"x"`
+		}{}},
+		{"envelope without content key", struct {
+			_ struct{} `dagjsongen:"union=envelope,discriminantkey=tag"`
+			A *string
+		}{}},
+		{"inline with non-struct member", struct {
+			_ struct{} `dagjsongen:"union=inline,discriminantkey=tag"`
+			A *string
+		}{}},
+		{"kinded duplicate kind", struct {
+			_ struct{} `dagjsongen:"union=kinded"`
+			A *int64
+			B *uint8
+		}{}},
+		{"kinded struct without kind", struct {
+			_ struct{} `dagjsongen:"union=kinded"`
+			A *unionCircle
+		}{}},
+	} {
+		if _, err := ParseTypeInfo(tc.typ); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+type declaredTuple struct {
+	_ struct{} `dagjsongen:"tuple"`
+	A int64
+}
+
+func TestDeclaredRepresentation(t *testing.T) {
+	gti, err := ParseTypeInfo(declaredTuple{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gti.Representation != "tuple" {
+		t.Fatalf("expected a tuple representation, got %q", gti.Representation)
+	}
+	var buf bytes.Buffer
+	if err := GenMapEncodersForType(gti, &buf); err == nil {
+		t.Fatal("expected an error generating map encoders for a tuple type")
+	}
+	if err := GenEncodersForType(gti, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "WriteArrayOpen") {
+		t.Fatal("expected tuple encoders")
+	}
+
+	for _, typ := range []any{
+		struct {
+			_ struct{} `dagjsongen:"tupel"`
+		}{},
+		struct {
+			_ struct{} `dagjsongen:"union=keyed,tuple"`
+			A *string
+		}{},
+	} {
+		if _, err := ParseTypeInfo(typ); err == nil {
+			t.Errorf("%T: expected an error", typ)
+		}
+	}
+}
+
+type flatHeader struct {
+	Version uint64
+	Sender  string `dagjsongen:"from"`
+}
+
+// TaggedUnion is exported so that it is kept as an embedded field.
+type TaggedUnion flatUnion
+
+type flatTrace struct {
+	ID      string
+	Version uint64
+}
+
+type flatUnion struct {
+	_ struct{} `dagjsongen:"union=keyed"`
+	A *string  `dagjsongen:"a"`
+}
+
+func TestParseFlattenedTypeInfo(t *testing.T) {
+	info, err := ParseTypeInfo(struct {
+		flatHeader
+		Trace   flatTrace `dagjsongen:"flatten"`
+		Version int64
+		Named   flatHeader `dagjsongen:"named"`
+		TaggedUnion
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range info.Fields {
+		got = append(got, f.Name+":"+f.MapKey)
+	}
+	expected := []string{"flatHeader.Sender:from", "Trace.ID:ID", "Version:Version", "Named:named", "TaggedUnion:TaggedUnion"}
+	if strings.Join(got, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected fields %v, got %v", expected, got)
+	}
+
+	for _, tc := range []struct {
+		name string
+		typ  any
+	}{
+		{"conflict at the same depth", struct {
+			flatHeader
+			flatTrace
+		}{}},
+		{"flattened pointer", struct {
+			*flatHeader `dagjsongen:"inline"`
+		}{}},
+		{"flattened non-struct", struct {
+			A []string `dagjsongen:"inline"`
+		}{}},
+		{"flattened with options", struct {
+			A flatHeader `dagjsongen:"inline,omitempty"`
+		}{}},
+		{"flattened with a name", struct {
+			A flatHeader `dagjsongen:"inline,a"`
+		}{}},
+		{"flattened union", struct {
+			A flatUnion `dagjsongen:"inline"`
+		}{}},
+	} {
+		if _, err := ParseTypeInfo(tc.typ); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+}
+
+func TestRequiredFieldErrors(t *testing.T) {
+	if _, err := ParseTypeInfo(struct {
+		Name string `dagjsongen:"name,required,omitempty"`
+	}{}); err == nil || !strings.Contains(err.Error(), "cannot be omitempty") {
+		t.Errorf("expected an error for a required omitempty field, got %v", err)
+	}
+
+	gti, err := ParseTypeInfo(struct {
+		Name string `dagjsongen:"required"`
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := GenTupleEncodersForType(gti, &buf); err == nil || !strings.Contains(err.Error(), "only supported on map encoded structs") {
+		t.Errorf("expected an error for a required field in a tuple, got %v", err)
+	}
+	if err := GenMapEncodersForType(gti, &buf); err != nil {
+		t.Errorf("expected a required field in a map to be supported: %s", err)
+	}
+}
+
+func TestFieldReflectType(t *testing.T) {
+	gti, err := ParseTypeInfo(struct {
+		Name string
+	}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gti.Fields[0].Type != reflect.TypeOf("") {
+		t.Errorf("expected the field's reflect.Type, got %v", gti.Fields[0].Type)
+	}
+
+	// Type infos built by hand only need to set the reflect.Type.
+	gti = &GenTypeInfo{Name: "Handmade", Fields: []Field{
+		{Name: "Name", MapKey: "name", Type: reflect.TypeOf(""), MaxLen: NoUsrMaxLen},
+	}}
+	var buf bytes.Buffer
+	if err := GenMapEncodersForType(gti, &buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "jw.WriteString(string(t.Name))") {
+		t.Errorf("expected the field to be written as a string:\n%s", buf.String())
+	}
+}
+
+type genericBox[K comparable, V any] struct {
+	M map[K]V
+}
+
+func TestGenericTypeNames(t *testing.T) {
+	pkg := "github.com/alanshaw/dag-json-gen"
+	typ := ReflectType(reflect.TypeOf(genericBox[string, []*url.URL]{}))
+	if name := typeName(nil, pkg, typ); name != "genericBox[string, []*url.URL]" {
+		t.Errorf("unexpected name %s", name)
+	}
+	if name := typeName(nil, "other", typ); name != "jsg.genericBox[string, []*url.URL]" {
+		t.Errorf("unexpected name %s", name)
+	}
+	imports := ImportsForType("other", typ)
+	if len(imports) != 2 || imports[0].PkgPath != pkg || imports[1] != (Import{Name: "url", PkgPath: "net/url"}) {
+		t.Errorf("unexpected imports %v", imports)
+	}
+
+	for path, name := range map[string]string{
+		"net/url":                 "url",
+		"github.com/ipfs/go-cid":  "cid",
+		"example.com/thing/v2":    "thing",
+		"gopkg.in/yaml.v3":        "yaml",
+		"example.com/go-multi-go": "multi",
+		"example.com/3d":          "pkg3d",
+	} {
+		if got := defaultPkgName(path); got != name {
+			t.Errorf("%s: expected %s, got %s", path, name, got)
+		}
+	}
+}
+
 // // TestReadEOFSemantics checks that our helper functions follow this rule when
 // // dealing with EOF:
 // // If the reader can't read a single byte because of EOF, it should return err == io.EOF.
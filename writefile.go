@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"go/format"
+	"io"
 	"os"
 )
 
@@ -19,33 +20,47 @@ func WriteTupleEncodersToFile(fname, pkg string, types ...interface{}) error {
 // The MarshalCBOR and UnmarshalCBOR implementations will marshal/unmarshal each type's fields as a
 // fixed-length CBOR array of field values.
 func (g Gen) WriteTupleEncodersToFile(fname, pkg string, types ...interface{}) error {
-	if g.SortTypeNames {
-		types = sortTypeNames(types)
-	}
-	buf := new(bytes.Buffer)
+	return g.writeFile(fname, pkg, types, g.GenTupleEncodersForType)
+}
 
-	typeInfos := make([]*GenTypeInfo, len(types))
-	for i, t := range types {
-		gti, err := ParseTypeInfo(t)
-		if err != nil {
-			return fmt.Errorf("failed to parse type info: %w", err)
-		}
-		typeInfos[i] = gti
-	}
+// WriteMapFileEncodersToFile is a convenience wrapper around Gen.WriteMapEncodersToFile using
+// default options.
+func WriteMapEncodersToFile(fname, pkg string, types ...interface{}) error {
+	return Gen{}.WriteMapEncodersToFile(fname, pkg, types...)
+}
 
-	if err := g.PrintHeaderAndUtilityMethods(buf, pkg, typeInfos); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
-	}
+// WriteMapFileEncodersToFile generates map backed MarshalCBOR and UnmarshalCBOR implementations for
+// the given types in the specified file, with the specified package name.
+//
+// The MarshalCBOR and UnmarshalCBOR implementations will marshal/unmarshal each type's fields as a
+// map of field names to field values.
+func (g Gen) WriteMapEncodersToFile(fname, pkg string, types ...interface{}) error {
+	return g.writeFile(fname, pkg, types, g.GenMapEncodersForType)
+}
 
-	for _, t := range typeInfos {
-		if err := g.GenTupleEncodersForType(t, buf); err != nil {
-			return fmt.Errorf("failed to generate encoders: %w", err)
-		}
-	}
+// WriteEncodersToFile is a convenience wrapper around Gen.WriteEncodersToFile using default
+// options.
+func WriteEncodersToFile(fname, pkg string, types ...interface{}) error {
+	return Gen{}.WriteEncodersToFile(fname, pkg, types...)
+}
 
-	data, err := format.Source(buf.Bytes())
+// WriteEncodersToFile generates MarshalDagJSON and UnmarshalDagJSON implementations for the given
+// types in the specified file, with the specified package name, encoding each struct in the
+// representation it declares: as a list of field values if it has a blank field tagged
+// `dagjsongen:"tuple"`, and otherwise as a map. The representation of a *GenTypeInfo can also be
+// set through its Representation field.
+func (g Gen) WriteEncodersToFile(fname, pkg string, types ...interface{}) error {
+	return g.writeFile(fname, pkg, types, g.GenEncodersForType)
+}
+
+// genFunc generates the encoders for a type.
+type genFunc func(*GenTypeInfo, io.Writer) error
+
+// writeFile generates the encoders for the given types with gen into fname.
+func (g Gen) writeFile(fname, pkg string, types []interface{}, gen genFunc) error {
+	data, err := g.render(pkg, types, gen)
 	if err != nil {
-		return fmt.Errorf("failed to format: %w", err)
+		return err
 	}
 
 	fi, err := os.Create(fname)
@@ -63,18 +78,9 @@ func (g Gen) WriteTupleEncodersToFile(fname, pkg string, types ...interface{}) e
 	return nil
 }
 
-// WriteMapFileEncodersToFile is a convenience wrapper around Gen.WriteMapEncodersToFile using
-// default options.
-func WriteMapEncodersToFile(fname, pkg string, types ...interface{}) error {
-	return Gen{}.WriteMapEncodersToFile(fname, pkg, types...)
-}
-
-// WriteMapFileEncodersToFile generates map backed MarshalCBOR and UnmarshalCBOR implementations for
-// the given types in the specified file, with the specified package name.
-//
-// The MarshalCBOR and UnmarshalCBOR implementations will marshal/unmarshal each type's fields as a
-// map of field names to field values.
-func (g Gen) WriteMapEncodersToFile(fname, pkg string, types ...interface{}) error {
+// render generates the formatted source of a file holding the encoders for
+// the given types, generated with gen.
+func (g Gen) render(pkg string, types []interface{}, gen genFunc) ([]byte, error) {
 	if g.SortTypeNames {
 		types = sortTypeNames(types)
 	}
@@ -84,37 +90,29 @@ func (g Gen) WriteMapEncodersToFile(fname, pkg string, types ...interface{}) err
 	for i, t := range types {
 		gti, err := ParseTypeInfo(t)
 		if err != nil {
-			return fmt.Errorf("failed to parse type info: %w", err)
+			return nil, fmt.Errorf("failed to parse type info: %w", err)
 		}
 		typeInfos[i] = gti
 	}
 
-	if err := g.PrintHeaderAndUtilityMethods(buf, pkg, typeInfos); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+	typeInfos, err := g.PrintHeaderAndUtilityMethods(buf, pkg, typeInfos)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write header: %w", err)
 	}
 
 	for i, t := range typeInfos {
-		if err := g.GenMapEncodersForType(t, buf); err != nil {
-			return fmt.Errorf("%T (%s) failed to generate encoders: %w", types[i], t.Name, err)
+		if err := gen(t, buf); err != nil {
+			return nil, fmt.Errorf("%T (%s) failed to generate encoders: %w", types[i], t.Name, err)
 		}
 	}
 
-	data, err := format.Source(buf.Bytes())
-	if err != nil {
-		return err
-	}
-
-	fi, err := os.Create(fname)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %w", err)
+	if err := g.GenGenericMethods(buf, typeInfos); err != nil {
+		return nil, fmt.Errorf("failed to generate generic methods: %w", err)
 	}
 
-	_, err = fi.Write(data)
+	data, err := format.Source(buf.Bytes())
 	if err != nil {
-		_ = fi.Close()
-		return err
+		return nil, fmt.Errorf("failed to format: %w", err)
 	}
-	_ = fi.Close()
-
-	return nil
+	return data, nil
 }